@@ -0,0 +1,38 @@
+package configs
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvCheckinSecret adalah kunci penanda tangan token check-in dari CHECKIN_SECRET,
+// jika kosong memakai SECRET_JWT
+func EnvCheckinSecret() []byte {
+	secret := os.Getenv("CHECKIN_SECRET")
+	if secret == "" {
+		secret = os.Getenv("SECRET_JWT")
+	}
+	return []byte(secret)
+}
+
+// EnvCheckinEarlyWindow adalah seberapa awal token check-in sudah berlaku sebelum peminjaman
+// dimulai, diambil dari CHECKIN_EARLY_MINUTES (default 30 menit)
+func EnvCheckinEarlyWindow() time.Duration {
+	return envMinutes("CHECKIN_EARLY_MINUTES", 30)
+}
+
+// EnvCheckinGracePeriod adalah toleransi dari CHECKIN_GRACE_MINUTES (default 15 menit). Nilainya
+// dipakai sebagai batas keterlambatan check-in sebelum peminjaman ditandai no_show, lama token
+// check-in tetap berlaku setelah jam selesai, dan jeda sebelum peminjaman in_use ditutup otomatis.
+func EnvCheckinGracePeriod() time.Duration {
+	return envMinutes("CHECKIN_GRACE_MINUTES", 15)
+}
+
+func envMinutes(key string, fallback int) time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || minutes < 0 {
+		minutes = fallback
+	}
+	return time.Duration(minutes) * time.Minute
+}
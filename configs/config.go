@@ -91,18 +91,166 @@ func AccountSeeder(db *gorm.DB) error {
 }
 
 func MigrateDB(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&models.User{},
 		&models.Notification{},
 		&models.TemplateMessage{},
 		&models.Lab{},
+		&models.LabSlot{},
+		&models.LabApprovalStep{},
 		&models.LabImage{},
 		&models.HistorySearch{},
 		&models.HistorySeenLab{},
 		&models.Jadwal{},
+		&models.PeminjamanSeries{},
 		&models.Peminjaman{},
+		&models.PeminjamanStatusLog{},
+		&models.PeminjamanApproval{},
+		&models.PeminjamanWaitlist{},
 		&models.BeritaAcaraImage{},
 		&models.SuratRekomendasiImage{},
 		&models.ExamUser{},
+		&models.JobRun{},
+		&models.EquipmentType{},
+		&models.EquipmentUnit{},
+		&models.EquipmentLoan{},
+		&models.EquipmentLoanItem{},
+		&models.EquipmentLoanUnit{},
+		&models.ReturnInspection{},
+		&models.ReturnInspectionItem{},
+		&models.ReturnInspectionImage{},
+		&models.DamageCase{},
+		&models.Penalty{},
+		&models.BookingQuota{},
+		&models.LabClosure{},
+		&models.PeminjamanParticipant{},
+		&models.PeminjamanReschedule{},
+		&models.LetterTemplate{},
+		&models.PeminjamanLetter{},
+		&models.CalendarFeed{},
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := MigrateEnumColumns(db, &models.User{}, &models.Peminjaman{}, &models.PeminjamanStatusLog{}, &models.Jadwal{}); err != nil {
+		return err
+	}
+
+	if err := MigrateJadwalLinks(db); err != nil {
+		return err
+	}
+
+	return MigrateLabSlots(db)
+}
+
+// MigrateJadwalLinks mengisi lab_id dan user_id jadwal lama dari nama lab dan nama user.
+// Nama user hanya dipakai jika cocok dengan tepat satu user, baris yang tetap tidak cocok
+// dicatat di log agar bisa diperbaiki manual oleh admin.
+func MigrateJadwalLinks(db *gorm.DB) error {
+	err := db.Exec(`
+		UPDATE jadwals j
+		JOIN labs l ON l.name = j.name_laboratorium AND l.deleted_at IS NULL
+		SET j.lab_id = l.id
+		WHERE j.lab_id IS NULL
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	err = db.Exec(`
+		UPDATE jadwals j
+		JOIN (
+			SELECT full_name, MIN(id) AS id FROM users
+			WHERE deleted_at IS NULL
+			GROUP BY full_name
+			HAVING COUNT(*) = 1
+		) u ON u.full_name = j.name_user
+		SET j.user_id = u.id
+		WHERE j.user_id IS NULL
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	var unmatchedLabs []models.Jadwal
+	if err := db.Where("lab_id IS NULL").Find(&unmatchedLabs).Error; err != nil {
+		return err
+	}
+	for _, jadwal := range unmatchedLabs {
+		log.Printf("migrasi jadwal: jadwal %d dengan lab %q tidak cocok dengan lab mana pun", jadwal.ID, jadwal.NameLaboratorium)
+	}
+
+	var unmatchedUsers []models.Jadwal
+	if err := db.Where("user_id IS NULL AND name_user <> ''").Find(&unmatchedUsers).Error; err != nil {
+		return err
+	}
+	for _, jadwal := range unmatchedUsers {
+		log.Printf("migrasi jadwal: jadwal %d dengan user %q tidak cocok dengan tepat satu user", jadwal.ID, jadwal.NameUser)
+	}
+
+	if len(unmatchedLabs) > 0 || len(unmatchedUsers) > 0 {
+		log.Printf("migrasi jadwal: %d jadwal tanpa lab dan %d jadwal tanpa user perlu diperbaiki manual", len(unmatchedLabs), len(unmatchedUsers))
+	}
+
+	return nil
 }
+
+// MigrateLabSlots memindahkan data lama yang masih memakai ENUM jam ke konfigurasi slot per lab.
+// Lab yang belum punya slot diberi slot bawaan untuk setiap hari, lalu peminjaman dan jadwal
+// lama dihubungkan ke slot yang jam mulainya sama.
+func MigrateLabSlots(db *gorm.DB) error {
+	var labs []models.Lab
+	if err := db.Find(&labs).Error; err != nil {
+		return err
+	}
+
+	for _, lab := range labs {
+		var count int64
+		if err := db.Model(&models.LabSlot{}).Unscoped().Where("lab_id = ?", lab.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := db.Create(models.DefaultLabSlots(lab.ID)).Error; err != nil {
+			return err
+		}
+	}
+
+	err := db.Exec(`
+		UPDATE peminjamen p
+		JOIN lab_slots s ON s.lab_id = p.lab_id AND s.weekday = DAYOFWEEK(p.tanggal_peminjaman) - 1 AND s.jam_mulai = p.jam_peminjaman AND s.deleted_at IS NULL
+		SET p.lab_slot_id = s.id, p.jam_selesai = s.jam_selesai
+		WHERE p.lab_slot_id IS NULL
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	err = db.Exec(`
+		UPDATE jadwals j
+		JOIN lab_slots s ON s.lab_id = j.lab_id AND s.weekday = DAYOFWEEK(j.tanggal_jadwal) - 1 AND s.jam_mulai = j.waktu_jadwal AND s.deleted_at IS NULL
+		SET j.lab_slot_id = s.id, j.waktu_selesai = s.jam_selesai
+		WHERE j.lab_slot_id IS NULL
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	// Baris yang tidak menemukan slot tetap diberi jam selesai 3 jam setelah jam mulai,
+	// sesuai panjang blok ENUM lama, agar pengecekan bentrok tetap bekerja
+	err = db.Exec(`
+		UPDATE peminjamen SET jam_selesai = DATE_FORMAT(ADDTIME(CONCAT(jam_peminjaman, ':00'), '03:00:00'), '%H:%i')
+		WHERE (jam_selesai IS NULL OR jam_selesai = '') AND jam_peminjaman <> ''
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(`
+		UPDATE jadwals SET waktu_selesai = DATE_FORMAT(ADDTIME(CONCAT(waktu_jadwal, ':00'), '03:00:00'), '%H:%i')
+		WHERE (waktu_selesai IS NULL OR waktu_selesai = '') AND waktu_jadwal <> ''
+	`).Error
+}
+
@@ -0,0 +1,12 @@
+package configs
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvAppBaseURL adalah alamat publik backend dari APP_BASE_URL, dipakai untuk tautan verifikasi
+// yang dicetak sebagai QR pada surat peminjaman
+func EnvAppBaseURL() string {
+	return strings.TrimRight(os.Getenv("APP_BASE_URL"), "/")
+}
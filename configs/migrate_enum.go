@@ -0,0 +1,44 @@
+package configs
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MigrateEnumColumns memperlebar kolom ENUM yang daftar nilainya bertambah di model. AutoMigrate
+// hanya membandingkan nama tipe kolom, sehingga database lama tetap memakai daftar ENUM lama dan
+// menolak status baru. Kolom diubah hanya jika daftar nilainya berbeda dari tag model.
+func MigrateEnumColumns(db *gorm.DB, values ...interface{}) error {
+	for _, value := range values {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(value); err != nil {
+			return err
+		}
+
+		columnTypes, err := db.Migrator().ColumnTypes(value)
+		if err != nil {
+			return err
+		}
+		for _, columnType := range columnTypes {
+			field := stmt.Schema.LookUpField(columnType.Name())
+			if field == nil || !strings.HasPrefix(strings.ToLower(string(field.DataType)), "enum") {
+				continue
+			}
+
+			current, ok := columnType.ColumnType()
+			if !ok || normalizeEnumType(current) == normalizeEnumType(string(field.DataType)) {
+				continue
+			}
+			if err := db.Migrator().AlterColumn(value, field.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeEnumType menyamakan penulisan ENUM di tag model dengan COLUMN_TYPE dari MySQL
+func normalizeEnumType(enumType string) string {
+	return strings.ToLower(strings.ReplaceAll(enumType, " ", ""))
+}
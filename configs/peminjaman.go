@@ -0,0 +1,27 @@
+package configs
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvPeminjamanCancelCutoff adalah batas minimal sebelum peminjaman dimulai agar user masih
+// boleh membatalkan, diambil dari PEMINJAMAN_CANCEL_CUTOFF_HOURS (default 2 jam)
+func EnvPeminjamanCancelCutoff() time.Duration {
+	return envHours("PEMINJAMAN_CANCEL_CUTOFF_HOURS", 2)
+}
+
+// EnvPeminjamanLateCancelWindow adalah rentang sebelum peminjaman dimulai di mana pembatalan
+// dianggap terlambat, diambil dari PEMINJAMAN_LATE_CANCEL_HOURS (default 24 jam)
+func EnvPeminjamanLateCancelWindow() time.Duration {
+	return envHours("PEMINJAMAN_LATE_CANCEL_HOURS", 24)
+}
+
+func envHours(key string, fallback int) time.Duration {
+	hours, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || hours < 0 {
+		hours = fallback
+	}
+	return time.Duration(hours) * time.Hour
+}
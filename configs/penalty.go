@@ -0,0 +1,46 @@
+package configs
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvPenaltySuspendThreshold adalah jumlah poin penalti aktif yang membuat user ditangguhkan dari
+// peminjaman, diambil dari PENALTY_SUSPEND_THRESHOLD (default 10, 0 berarti tidak pernah ditangguhkan)
+func EnvPenaltySuspendThreshold() int {
+	return envInt("PENALTY_SUSPEND_THRESHOLD", 10)
+}
+
+// EnvPenaltyExpiry adalah masa berlaku poin penalti dari PENALTY_EXPIRY_DAYS (default 90 hari)
+func EnvPenaltyExpiry() time.Duration {
+	return time.Duration(envInt("PENALTY_EXPIRY_DAYS", 90)) * 24 * time.Hour
+}
+
+// EnvPenaltyNoShowPoints adalah poin untuk peminjaman yang tidak check-in (PENALTY_NO_SHOW_POINTS, default 3)
+func EnvPenaltyNoShowPoints() int {
+	return envInt("PENALTY_NO_SHOW_POINTS", 3)
+}
+
+// EnvPenaltyLateReturnPoints adalah poin per hari keterlambatan pengembalian alat (PENALTY_LATE_RETURN_POINTS, default 1)
+func EnvPenaltyLateReturnPoints() int {
+	return envInt("PENALTY_LATE_RETURN_POINTS", 1)
+}
+
+// EnvPenaltyDamagePoints adalah poin untuk kerusakan yang ditemukan saat pemeriksaan (PENALTY_DAMAGE_POINTS, default 5)
+func EnvPenaltyDamagePoints() int {
+	return envInt("PENALTY_DAMAGE_POINTS", 5)
+}
+
+// EnvPenaltyMissingPoints adalah poin untuk barang yang hilang saat pemeriksaan (PENALTY_MISSING_POINTS, default 10)
+func EnvPenaltyMissingPoints() int {
+	return envInt("PENALTY_MISSING_POINTS", 10)
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		value = fallback
+	}
+	return value
+}
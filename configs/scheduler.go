@@ -0,0 +1,17 @@
+package configs
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvSchedulerInterval adalah jeda antar eksekusi job scheduler, diambil dari
+// SCHEDULER_INTERVAL_MINUTES (default 5 menit)
+func EnvSchedulerInterval() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("SCHEDULER_INTERVAL_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 5
+	}
+	return time.Duration(minutes) * time.Minute
+}
@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/middlewares"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ApprovalController interface {
+	GetLabApprovalSteps(c echo.Context) error
+	UpdateLabApprovalSteps(c echo.Context) error
+	GetPendingApprovals(c echo.Context) error
+	DecidePeminjamanApproval(c echo.Context) error
+	DecideRescheduleApproval(c echo.Context) error
+}
+
+type approvalController struct {
+	approvalUsecase usecases.ApprovalUsecase
+}
+
+func NewApprovalController(approvalUsecase usecases.ApprovalUsecase) ApprovalController {
+	return &approvalController{approvalUsecase}
+}
+
+func (c *approvalController) GetLabApprovalSteps(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.Param("id"))
+
+	chain, err := c.approvalUsecase.GetLabApprovalSteps(uint(labID))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get lab approval steps",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get lab approval steps",
+			chain,
+		),
+	)
+}
+
+func (c *approvalController) UpdateLabApprovalSteps(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.Param("id"))
+
+	var stepsInput dtos.LabApprovalStepsInput
+	if err := ctx.Bind(&stepsInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding lab approval steps",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	chain, err := c.approvalUsecase.UpdateLabApprovalSteps(uint(labID), stepsInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update lab approval steps",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated lab approval steps",
+			chain,
+		),
+	)
+}
+
+func (c *approvalController) GetPendingApprovals(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	role, err := middlewares.GetRoleFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	approvals, count, err := c.approvalUsecase.GetPendingApprovals(userId, role, page, limit)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get pending approvals",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get pending approvals",
+			approvals,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *approvalController) DecidePeminjamanApproval(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	role, err := middlewares.GetRoleFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var decisionInput dtos.ApprovalDecisionInput
+	if err := ctx.Bind(&decisionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding approval decision",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	decision, err := c.approvalUsecase.DecidePeminjamanApproval(userId, role, uint(id), decisionInput)
+	if err != nil {
+		statusCode := helpers.GetStatusCode(err, http.StatusBadRequest)
+		return ctx.JSON(
+			statusCode,
+			helpers.NewErrorResponse(
+				statusCode,
+				"Failed to decide peminjaman approval",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully decided peminjaman approval",
+			decision,
+		),
+	)
+}
+
+func (c *approvalController) DecideRescheduleApproval(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	role, err := middlewares.GetRoleFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var decisionInput dtos.ApprovalDecisionInput
+	if err := ctx.Bind(&decisionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding approval decision",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	decision, err := c.approvalUsecase.DecideRescheduleApproval(userId, role, uint(id), decisionInput)
+	if err != nil {
+		statusCode := helpers.GetStatusCode(err, http.StatusBadRequest)
+		return ctx.JSON(
+			statusCode,
+			helpers.NewErrorResponse(
+				statusCode,
+				"Failed to decide reschedule approval",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully decided reschedule approval",
+			decision,
+		),
+	)
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/middlewares"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BookingQuotaController interface {
+	GetBookingQuotas(c echo.Context) error
+	CreateBookingQuota(c echo.Context) error
+	UpdateBookingQuota(c echo.Context) error
+	DeleteBookingQuota(c echo.Context) error
+	GetUserQuota(c echo.Context) error
+}
+
+type bookingQuotaController struct {
+	bookingQuotaUsecase usecases.BookingQuotaUsecase
+}
+
+func NewBookingQuotaController(bookingQuotaUsecase usecases.BookingQuotaUsecase) BookingQuotaController {
+	return &bookingQuotaController{bookingQuotaUsecase}
+}
+
+func (c *bookingQuotaController) GetBookingQuotas(ctx echo.Context) error {
+	bookingQuotas, err := c.bookingQuotaUsecase.GetBookingQuotas()
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get booking quotas",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get booking quotas",
+			bookingQuotas,
+		),
+	)
+}
+
+func (c *bookingQuotaController) CreateBookingQuota(ctx echo.Context) error {
+	var bookingQuotaInput dtos.BookingQuotaInput
+	if err := ctx.Bind(&bookingQuotaInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding booking quota",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	bookingQuota, err := c.bookingQuotaUsecase.CreateBookingQuota(bookingQuotaInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to create booking quota",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to create booking quota",
+			bookingQuota,
+		),
+	)
+}
+
+func (c *bookingQuotaController) UpdateBookingQuota(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var bookingQuotaInput dtos.BookingQuotaInput
+	if err := ctx.Bind(&bookingQuotaInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding booking quota",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	bookingQuota, err := c.bookingQuotaUsecase.UpdateBookingQuota(uint(id), bookingQuotaInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update booking quota",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to update booking quota",
+			bookingQuota,
+		),
+	)
+}
+
+func (c *bookingQuotaController) DeleteBookingQuota(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err := c.bookingQuotaUsecase.DeleteBookingQuota(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to delete booking quota",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted booking quota",
+			nil,
+		),
+	)
+}
+
+func (c *bookingQuotaController) GetUserQuota(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	labIDParam := ctx.QueryParam("lab_id")
+	labID, err := strconv.Atoi(labIDParam)
+	if err != nil {
+		labID = 0
+	}
+
+	quota, err := c.bookingQuotaUsecase.GetUserQuota(userId, uint(labID), ctx.QueryParam("tanggal"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get quota",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get quota",
+			quota,
+		),
+	)
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/middlewares"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalendarFeedController interface {
+	GetCalendarFeed(c echo.Context) error
+	RotateCalendarFeed(c echo.Context) error
+	GetUserCalendar(c echo.Context) error
+	GetLabCalendar(c echo.Context) error
+}
+
+type calendarFeedController struct {
+	calendarFeedUsecase usecases.CalendarFeedUsecase
+}
+
+func NewCalendarFeedController(calendarFeedUsecase usecases.CalendarFeedUsecase) CalendarFeedController {
+	return &calendarFeedController{calendarFeedUsecase}
+}
+
+func (c *calendarFeedController) GetCalendarFeed(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	calendarFeed, err := c.calendarFeedUsecase.GetCalendarFeed(userId)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get calendar feed",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get calendar feed",
+			calendarFeed,
+		),
+	)
+}
+
+func (c *calendarFeedController) RotateCalendarFeed(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	calendarFeed, err := c.calendarFeedUsecase.RotateCalendarFeed(userId)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to rotate calendar feed",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to rotate calendar feed",
+			calendarFeed,
+		),
+	)
+}
+
+func (c *calendarFeedController) GetUserCalendar(ctx echo.Context) error {
+	calendar, err := c.calendarFeedUsecase.GetUserCalendar(ctx.Param("token"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get calendar",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.Blob(http.StatusOK, calendarContentType, calendar)
+}
+
+func (c *calendarFeedController) GetLabCalendar(ctx echo.Context) error {
+	// Akhiran .ics opsional agar alamat feed mudah dikenali aplikasi kalender
+	id, _ := strconv.Atoi(strings.TrimSuffix(ctx.Param("id"), ".ics"))
+
+	calendar, err := c.calendarFeedUsecase.GetLabCalendar(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to get lab calendar",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.Blob(http.StatusOK, calendarContentType, calendar)
+}
@@ -0,0 +1,485 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/middlewares"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EquipmentLoanController interface {
+	GetEquipmentLoans(c echo.Context) error
+	GetEquipmentLoanByID(c echo.Context) error
+	CreateEquipmentLoan(c echo.Context) error
+	CancelEquipmentLoan(c echo.Context) error
+	AdminGetEquipmentLoans(c echo.Context) error
+	AdminGetEquipmentLoanByID(c echo.Context) error
+	DecideEquipmentLoan(c echo.Context) error
+	HandOverEquipmentLoan(c echo.Context) error
+	ReturnEquipmentLoan(c echo.Context) error
+}
+
+type equipmentLoanController struct {
+	equipmentLoanUsecase usecases.EquipmentLoanUsecase
+}
+
+func NewEquipmentLoanController(equipmentLoanUsecase usecases.EquipmentLoanUsecase) EquipmentLoanController {
+	return &equipmentLoanController{equipmentLoanUsecase}
+}
+
+func (c *equipmentLoanController) GetEquipmentLoans(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	equipmentLoans, count, err := c.equipmentLoanUsecase.GetEquipmentLoans(page, limit, userId, ctx.QueryParam("status"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment loans",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully to get equipment loans",
+			equipmentLoans,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *equipmentLoanController) GetEquipmentLoanByID(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	equipmentLoan, err := c.equipmentLoanUsecase.GetEquipmentLoanByID(userId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get equipment loan",
+			equipmentLoan,
+		),
+	)
+}
+
+func (c *equipmentLoanController) CreateEquipmentLoan(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var equipmentLoanInput dtos.EquipmentLoanInput
+	if err := ctx.Bind(&equipmentLoanInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	equipmentLoan, err := c.equipmentLoanUsecase.CreateEquipmentLoan(userId, equipmentLoanInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to create equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to create equipment loan",
+			equipmentLoan,
+		),
+	)
+}
+
+func (c *equipmentLoanController) CancelEquipmentLoan(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	equipmentLoan, err := c.equipmentLoanUsecase.CancelEquipmentLoan(userId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to cancel equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to cancel equipment loan",
+			equipmentLoan,
+		),
+	)
+}
+
+func (c *equipmentLoanController) AdminGetEquipmentLoans(ctx echo.Context) error {
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	equipmentLoans, count, err := c.equipmentLoanUsecase.GetEquipmentLoans(page, limit, 0, ctx.QueryParam("status"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment loans",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully to get equipment loans",
+			equipmentLoans,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *equipmentLoanController) AdminGetEquipmentLoanByID(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	equipmentLoan, err := c.equipmentLoanUsecase.GetEquipmentLoanByID(0, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get equipment loan",
+			equipmentLoan,
+		),
+	)
+}
+
+func (c *equipmentLoanController) DecideEquipmentLoan(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var decisionInput dtos.EquipmentLoanDecisionInput
+	if err := ctx.Bind(&decisionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding equipment loan decision",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	equipmentLoan, err := c.equipmentLoanUsecase.DecideEquipmentLoan(adminId, uint(id), decisionInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to decide equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to decide equipment loan",
+			equipmentLoan,
+		),
+	)
+}
+
+func (c *equipmentLoanController) HandOverEquipmentLoan(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var handoverInput dtos.EquipmentLoanHandoverInput
+	if err := ctx.Bind(&handoverInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding equipment loan handover",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	equipmentLoan, err := c.equipmentLoanUsecase.HandOverEquipmentLoan(adminId, uint(id), handoverInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to hand over equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to hand over equipment loan",
+			equipmentLoan,
+		),
+	)
+}
+
+func (c *equipmentLoanController) ReturnEquipmentLoan(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	equipmentLoan, err := c.equipmentLoanUsecase.ReturnEquipmentLoan(adminId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to return equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to return equipment loan",
+			equipmentLoan,
+		),
+	)
+}
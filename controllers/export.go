@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ExportController interface {
+	ExportPeminjamans(c echo.Context) error
+	ExportJadwals(c echo.Context) error
+}
+
+type exportController struct {
+	exportUsecase usecases.ExportUsecase
+}
+
+func NewExportController(exportUsecase usecases.ExportUsecase) ExportController {
+	return &exportController{exportUsecase}
+}
+
+func (c *exportController) ExportPeminjamans(ctx echo.Context) error {
+	format := ctx.QueryParam("format")
+	setExportHeaders(ctx, "peminjaman", format)
+
+	// filter mengikuti daftar peminjaman admin, search tetap mencari nama lab
+	labIDParam, _ := strconv.Atoi(ctx.QueryParam("lab_id"))
+	filter := dtos.PeminjamanFilter{
+		LabID:   uint(labIDParam),
+		LabName: ctx.QueryParam("search"),
+		User:    ctx.QueryParam("user"),
+		Status:  ctx.QueryParam("status"),
+		From:    ctx.QueryParam("from"),
+		To:      ctx.QueryParam("to"),
+	}
+
+	err := c.exportUsecase.ExportPeminjamans(ctx.Response(), format, filter)
+	return exportResult(ctx, err, "Failed to export peminjaman")
+}
+
+func (c *exportController) ExportJadwals(ctx echo.Context) error {
+	format := ctx.QueryParam("format")
+	setExportHeaders(ctx, "jadwal", format)
+
+	err := c.exportUsecase.ExportJadwals(
+		ctx.Response(),
+		format,
+		ctx.QueryParam("name_laboratorium"),
+		ctx.QueryParam("from"),
+		ctx.QueryParam("to"),
+	)
+	return exportResult(ctx, err, "Failed to export jadwal")
+}
+
+func setExportHeaders(ctx echo.Context, name, format string) {
+	extension := helpers.ExportFormatCSV
+	if strings.ToLower(format) == helpers.ExportFormatXLSX {
+		extension = helpers.ExportFormatXLSX
+	}
+	ctx.Response().Header().Set(echo.HeaderContentType, helpers.ExportContentType(format))
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%s-%s.%s", name, time.Now().Format("20060102"), extension))
+}
+
+// exportResult membalas error JSON selama belum ada data yang terkirim. Header file dari setExportHeaders
+// dihapus lebih dulu agar error dikirim sebagai application/json. Jika export gagal di tengah jalan,
+// response sudah terkirim sebagian sehingga error hanya bisa dicatat di log.
+func exportResult(ctx echo.Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Response().Committed {
+		log.Printf("export terhenti di tengah jalan: %v", err)
+		return nil
+	}
+
+	ctx.Response().Header().Del(echo.HeaderContentType)
+	ctx.Response().Header().Del(echo.HeaderContentDisposition)
+	return ctx.JSON(
+		http.StatusBadRequest,
+		helpers.NewErrorResponse(
+			http.StatusBadRequest,
+			message,
+			helpers.GetErrorData(err),
+		),
+	)
+}
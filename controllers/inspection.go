@@ -0,0 +1,390 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/middlewares"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InspectionController interface {
+	InspectPeminjaman(c echo.Context) error
+	GetPeminjamanInspections(c echo.Context) error
+	InspectEquipmentLoan(c echo.Context) error
+	GetEquipmentLoanInspections(c echo.Context) error
+	GetDamageCases(c echo.Context) error
+	AdminGetDamageCases(c echo.Context) error
+	ResolveDamageCase(c echo.Context) error
+	GetDamageCaseReport(c echo.Context) error
+}
+
+type inspectionController struct {
+	inspectionUsecase usecases.InspectionUsecase
+}
+
+func NewInspectionController(inspectionUsecase usecases.InspectionUsecase) InspectionController {
+	return &inspectionController{inspectionUsecase}
+}
+
+func (c *inspectionController) InspectPeminjaman(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var returnInspectionInput dtos.ReturnInspectionInput
+	if err := ctx.Bind(&returnInspectionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding return inspection",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	returnInspection, err := c.inspectionUsecase.InspectPeminjaman(adminId, uint(id), returnInspectionInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to inspect peminjaman",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to inspect peminjaman",
+			returnInspection,
+		),
+	)
+}
+
+func (c *inspectionController) InspectEquipmentLoan(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var returnInspectionInput dtos.ReturnInspectionInput
+	if err := ctx.Bind(&returnInspectionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding return inspection",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	returnInspection, err := c.inspectionUsecase.InspectEquipmentLoan(adminId, uint(id), returnInspectionInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to inspect equipment loan",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to inspect equipment loan",
+			returnInspection,
+		),
+	)
+}
+
+func (c *inspectionController) GetPeminjamanInspections(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	returnInspections, err := c.inspectionUsecase.GetPeminjamanInspections(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get return inspections",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get return inspections",
+			returnInspections,
+		),
+	)
+}
+
+func (c *inspectionController) GetEquipmentLoanInspections(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	returnInspections, err := c.inspectionUsecase.GetEquipmentLoanInspections(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get return inspections",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get return inspections",
+			returnInspections,
+		),
+	)
+}
+
+func (c *inspectionController) GetDamageCases(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	damageCases, count, err := c.inspectionUsecase.GetDamageCases(page, limit, userId, ctx.QueryParam("status"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get damage cases",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully to get damage cases",
+			damageCases,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *inspectionController) AdminGetDamageCases(ctx echo.Context) error {
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	damageCases, count, err := c.inspectionUsecase.GetDamageCases(page, limit, 0, ctx.QueryParam("status"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get damage cases",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully to get damage cases",
+			damageCases,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *inspectionController) ResolveDamageCase(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var resolveInput dtos.DamageCaseResolveInput
+	if err := ctx.Bind(&resolveInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding damage case",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	damageCase, err := c.inspectionUsecase.ResolveDamageCase(adminId, uint(id), resolveInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to resolve damage case",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to resolve damage case",
+			damageCase,
+		),
+	)
+}
+
+func (c *inspectionController) GetDamageCaseReport(ctx echo.Context) error {
+	fromParam := ctx.QueryParam("from")
+	toParam := ctx.QueryParam("to")
+
+	report, err := c.inspectionUsecase.GetDamageCaseReport(fromParam, toParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get damage case report",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get damage case report",
+			report,
+		),
+	)
+}
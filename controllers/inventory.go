@@ -0,0 +1,325 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InventoryController interface {
+	GetEquipmentTypes(c echo.Context) error
+	CreateEquipmentType(c echo.Context) error
+	UpdateEquipmentType(c echo.Context) error
+	DeleteEquipmentType(c echo.Context) error
+	GetEquipmentUnits(c echo.Context) error
+	CreateEquipmentUnit(c echo.Context) error
+	UpdateEquipmentUnit(c echo.Context) error
+	DeleteEquipmentUnit(c echo.Context) error
+	GetEquipmentStock(c echo.Context) error
+	GetCatalogue(c echo.Context) error
+}
+
+type inventoryController struct {
+	inventoryUsecase usecases.InventoryUsecase
+}
+
+func NewInventoryController(inventoryUsecase usecases.InventoryUsecase) InventoryController {
+	return &inventoryController{inventoryUsecase}
+}
+
+func (c *inventoryController) GetEquipmentTypes(ctx echo.Context) error {
+	equipmentTypes, err := c.inventoryUsecase.GetEquipmentTypes(ctx.QueryParam("search"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment types",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get equipment types",
+			equipmentTypes,
+		),
+	)
+}
+
+func (c *inventoryController) CreateEquipmentType(ctx echo.Context) error {
+	var equipmentTypeInput dtos.EquipmentTypeInput
+	if err := ctx.Bind(&equipmentTypeInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding equipment type",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	equipmentType, err := c.inventoryUsecase.CreateEquipmentType(equipmentTypeInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to create equipment type",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to create equipment type",
+			equipmentType,
+		),
+	)
+}
+
+func (c *inventoryController) UpdateEquipmentType(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var equipmentTypeInput dtos.EquipmentTypeInput
+	if err := ctx.Bind(&equipmentTypeInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding equipment type",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	equipmentType, err := c.inventoryUsecase.UpdateEquipmentType(uint(id), equipmentTypeInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update equipment type",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to update equipment type",
+			equipmentType,
+		),
+	)
+}
+
+func (c *inventoryController) DeleteEquipmentType(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err := c.inventoryUsecase.DeleteEquipmentType(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to delete equipment type",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to delete equipment type",
+			nil,
+		),
+	)
+}
+
+func (c *inventoryController) GetEquipmentUnits(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.QueryParam("lab_id"))
+	equipmentTypeID, _ := strconv.Atoi(ctx.QueryParam("equipment_type_id"))
+
+	equipmentUnits, err := c.inventoryUsecase.GetEquipmentUnits(uint(labID), uint(equipmentTypeID))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment units",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get equipment units",
+			equipmentUnits,
+		),
+	)
+}
+
+func (c *inventoryController) CreateEquipmentUnit(ctx echo.Context) error {
+	var equipmentUnitInput dtos.EquipmentUnitInput
+	if err := ctx.Bind(&equipmentUnitInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding equipment unit",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	equipmentUnit, err := c.inventoryUsecase.CreateEquipmentUnit(equipmentUnitInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to create equipment unit",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to create equipment unit",
+			equipmentUnit,
+		),
+	)
+}
+
+func (c *inventoryController) UpdateEquipmentUnit(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var equipmentUnitInput dtos.EquipmentUnitInput
+	if err := ctx.Bind(&equipmentUnitInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding equipment unit",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	equipmentUnit, err := c.inventoryUsecase.UpdateEquipmentUnit(uint(id), equipmentUnitInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update equipment unit",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to update equipment unit",
+			equipmentUnit,
+		),
+	)
+}
+
+func (c *inventoryController) DeleteEquipmentUnit(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err := c.inventoryUsecase.DeleteEquipmentUnit(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to delete equipment unit",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to delete equipment unit",
+			nil,
+		),
+	)
+}
+
+func (c *inventoryController) GetEquipmentStock(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.QueryParam("lab_id"))
+
+	stocks, err := c.inventoryUsecase.GetEquipmentStock(uint(labID))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment stock",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get equipment stock",
+			stocks,
+		),
+	)
+}
+
+func (c *inventoryController) GetCatalogue(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.QueryParam("lab_id"))
+
+	catalogue, err := c.inventoryUsecase.GetCatalogue(uint(labID), ctx.QueryParam("search"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get equipment catalogue",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get equipment catalogue",
+			catalogue,
+		),
+	)
+}
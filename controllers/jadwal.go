@@ -202,9 +202,9 @@ func (c *jadwalController) CreateJadwal(ctx echo.Context) error {
 	jadwal, err := c.jadwalUsecase.CreateJadwal(&jadwalDTO)
 	if err != nil {
 		return ctx.JSON(
-			http.StatusBadRequest,
+			helpers.GetStatusCode(err, http.StatusBadRequest),
 			helpers.NewErrorResponse(
-				http.StatusBadRequest,
+				helpers.GetStatusCode(err, http.StatusBadRequest),
 				"Failed to created a jadwal",
 				helpers.GetErrorData(err),
 			),
@@ -329,9 +329,9 @@ func (c *jadwalController) UpdateJadwal(ctx echo.Context) error {
 	jadwalResp, err := c.jadwalUsecase.UpdateJadwal(uint(id), jadwalInput)
 	if err != nil {
 		return ctx.JSON(
-			http.StatusBadRequest,
+			helpers.GetStatusCode(err, http.StatusBadRequest),
 			helpers.NewErrorResponse(
-				http.StatusBadRequest,
+				helpers.GetStatusCode(err, http.StatusBadRequest),
 				"Failed to updated a jadwal",
 				helpers.GetErrorData(err),
 			),
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"net/http"
+	"path/filepath"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LabClosureController interface {
+	GetLabClosures(c echo.Context) error
+	CreateLabClosure(c echo.Context) error
+	UpdateLabClosure(c echo.Context) error
+	DeleteLabClosure(c echo.Context) error
+	ImportLabClosures(c echo.Context) error
+}
+
+type labClosureController struct {
+	labClosureUsecase usecases.LabClosureUsecase
+}
+
+func NewLabClosureController(labClosureUsecase usecases.LabClosureUsecase) LabClosureController {
+	return &labClosureController{labClosureUsecase}
+}
+
+func (c *labClosureController) GetLabClosures(ctx echo.Context) error {
+	labIDParam := ctx.QueryParam("lab_id")
+	labID, err := strconv.Atoi(labIDParam)
+	if err != nil {
+		labID = 0
+	}
+
+	labClosures, err := c.labClosureUsecase.GetLabClosures(uint(labID), ctx.QueryParam("from"), ctx.QueryParam("to"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get lab closures",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get lab closures",
+			labClosures,
+		),
+	)
+}
+
+func (c *labClosureController) CreateLabClosure(ctx echo.Context) error {
+	var labClosureInput dtos.LabClosureInput
+	if err := ctx.Bind(&labClosureInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding lab closure",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	labClosure, err := c.labClosureUsecase.CreateLabClosure(labClosureInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to create lab closure",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to create lab closure",
+			labClosure,
+		),
+	)
+}
+
+func (c *labClosureController) UpdateLabClosure(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var labClosureInput dtos.LabClosureInput
+	if err := ctx.Bind(&labClosureInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding lab closure",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	labClosure, err := c.labClosureUsecase.UpdateLabClosure(uint(id), labClosureInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update lab closure",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to update lab closure",
+			labClosure,
+		),
+	)
+}
+
+func (c *labClosureController) DeleteLabClosure(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err := c.labClosureUsecase.DeleteLabClosure(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to delete lab closure",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted lab closure",
+			nil,
+		),
+	)
+}
+
+func (c *labClosureController) ImportLabClosures(ctx echo.Context) error {
+	formHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to read ics file",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	if !strings.EqualFold(filepath.Ext(formHeader.Filename), ".ics") {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"The provided file format is not allowed. Please upload an .ics file",
+				"Bad Request",
+			),
+		)
+	}
+
+	formFile, err := formHeader.Open()
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to read ics file",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	defer formFile.Close()
+
+	// lab_id opsional, tanpa lab_id penutupan berlaku untuk semua lab
+	var labID *uint
+	if labIDParam := ctx.FormValue("lab_id"); labIDParam != "" {
+		parsedLabID, err := strconv.Atoi(labIDParam)
+		if err != nil {
+			return ctx.JSON(
+				http.StatusBadRequest,
+				helpers.NewErrorResponse(
+					http.StatusBadRequest,
+					"Invalid lab_id",
+					helpers.GetErrorData(err),
+				),
+			)
+		}
+		id := uint(parsedLabID)
+		labID = &id
+	}
+
+	importResponse, err := c.labClosureUsecase.ImportLabClosures(labID, formFile)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to import lab closures",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to import lab closures",
+			importResponse,
+		),
+	)
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LabSlotController interface {
+	GetLabSlots(c echo.Context) error
+	CreateLabSlot(c echo.Context) error
+	UpdateLabSlot(c echo.Context) error
+	DeleteLabSlot(c echo.Context) error
+}
+
+type labSlotController struct {
+	labSlotUsecase usecases.LabSlotUsecase
+}
+
+func NewLabSlotController(labSlotUsecase usecases.LabSlotUsecase) LabSlotController {
+	return &labSlotController{labSlotUsecase}
+}
+
+func (c *labSlotController) GetLabSlots(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.Param("id"))
+
+	labSlots, err := c.labSlotUsecase.GetLabSlots(uint(labID))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get lab slots",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get lab slots",
+			labSlots,
+		),
+	)
+}
+
+func (c *labSlotController) CreateLabSlot(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.Param("id"))
+
+	var labSlotInput dtos.LabSlotInput
+	if err := ctx.Bind(&labSlotInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding lab slot",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	labSlot, err := c.labSlotUsecase.CreateLabSlot(uint(labID), labSlotInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to created a lab slot",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to created a lab slot",
+			labSlot,
+		),
+	)
+}
+
+func (c *labSlotController) UpdateLabSlot(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.Param("id"))
+	id, _ := strconv.Atoi(ctx.Param("slotId"))
+
+	var labSlotInput dtos.LabSlotInput
+	if err := ctx.Bind(&labSlotInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding lab slot",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	labSlot, err := c.labSlotUsecase.UpdateLabSlot(uint(labID), uint(id), labSlotInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to updated a lab slot",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated lab slot",
+			labSlot,
+		),
+	)
+}
+
+func (c *labSlotController) DeleteLabSlot(ctx echo.Context) error {
+	labID, _ := strconv.Atoi(ctx.Param("id"))
+	id, _ := strconv.Atoi(ctx.Param("slotId"))
+
+	err := c.labSlotUsecase.DeleteLabSlot(uint(labID), uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to delete lab slot",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted lab slot",
+			nil,
+		),
+	)
+}
@@ -18,6 +18,7 @@ type LabController interface {
 	UpdateLab(c echo.Context) error
 	DeleteLab(c echo.Context) error
 	SearchLabAvailable(c echo.Context) error
+	GetLabAvailability(c echo.Context) error
 }
 
 type labController struct {
@@ -332,3 +333,30 @@ func (c *labController) SearchLabAvailable(ctx echo.Context) error {
 		),
 	)
 }
+
+func (c *labController) GetLabAvailability(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	fromParam := ctx.QueryParam("from")
+	toParam := ctx.QueryParam("to")
+
+	availability, err := c.labUsecase.GetLabAvailability(uint(id), fromParam, toParam)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get lab availability",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get lab availability",
+			availability,
+		),
+	)
+}
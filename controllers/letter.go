@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/middlewares"
+	"sistem_peminjaman_be/usecases"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LetterController interface {
+	GetPeminjamanLetter(c echo.Context) error
+	VerifyLetter(c echo.Context) error
+	GetLetterTemplates(c echo.Context) error
+	CreateLetterTemplate(c echo.Context) error
+	UpdateLetterTemplate(c echo.Context) error
+	DeleteLetterTemplate(c echo.Context) error
+}
+
+type letterController struct {
+	letterUsecase usecases.LetterUsecase
+}
+
+func NewLetterController(letterUsecase usecases.LetterUsecase) LetterController {
+	return &letterController{letterUsecase}
+}
+
+func (c *letterController) GetPeminjamanLetter(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	pdf, err := c.letterUsecase.GetPeminjamanLetter(userId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to get peminjaman letter",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=surat-peminjaman-%d.pdf", id))
+	return ctx.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+func (c *letterController) VerifyLetter(ctx echo.Context) error {
+	verification, err := c.letterUsecase.VerifyLetter(ctx.Param("code"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusNotFound,
+			helpers.NewErrorResponse(
+				http.StatusNotFound,
+				"Failed to verify letter",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to verify letter",
+			verification,
+		),
+	)
+}
+
+func (c *letterController) GetLetterTemplates(ctx echo.Context) error {
+	letterTemplates, err := c.letterUsecase.GetLetterTemplates()
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get letter templates",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get letter templates",
+			letterTemplates,
+		),
+	)
+}
+
+func (c *letterController) CreateLetterTemplate(ctx echo.Context) error {
+	var letterTemplateInput dtos.LetterTemplateInput
+	if err := ctx.Bind(&letterTemplateInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding letter template",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	letterTemplate, err := c.letterUsecase.CreateLetterTemplate(letterTemplateInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to create letter template",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully to create letter template",
+			letterTemplate,
+		),
+	)
+}
+
+func (c *letterController) UpdateLetterTemplate(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var letterTemplateInput dtos.LetterTemplateInput
+	if err := ctx.Bind(&letterTemplateInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding letter template",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	letterTemplate, err := c.letterUsecase.UpdateLetterTemplate(uint(id), letterTemplateInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update letter template",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to update letter template",
+			letterTemplate,
+		),
+	)
+}
+
+func (c *letterController) DeleteLetterTemplate(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	err := c.letterUsecase.DeleteLetterTemplate(uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to delete letter template",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully deleted letter template",
+			nil,
+		),
+	)
+}
@@ -20,6 +20,21 @@ type PeminjamanController interface {
 	CreatePeminjaman(c echo.Context) error
 	AdminUpdatePeminjaman(c echo.Context) error
 	UpdatePeminjaman(c echo.Context) error
+	BulkUpdatePeminjaman(c echo.Context) error
+	RequestReschedule(c echo.Context) error
+	GetReschedules(c echo.Context) error
+	DecideReschedule(c echo.Context) error
+	CancelPeminjaman(c echo.Context) error
+	JoinWaitlist(c echo.Context) error
+	GetWaitlists(c echo.Context) error
+	LeaveWaitlist(c echo.Context) error
+	GetPeminjamanQRCode(c echo.Context) error
+	GetJadwalQRCode(c echo.Context) error
+	Checkin(c echo.Context) error
+	Checkout(c echo.Context) error
+	GetPeminjamanSeriesByID(c echo.Context) error
+	AdminGetPeminjamanSeriesByID(c echo.Context) error
+	UpdatePeminjamanSeries(c echo.Context) error
 }
 
 type peminjamanController struct {
@@ -69,10 +84,18 @@ func (c *peminjamanController) GetAllPeminjamans(ctx echo.Context) error {
 		)
 	}
 
-	nameLaboratoriumParam := ctx.QueryParam("name")
-	statusParam := ctx.QueryParam("status")
+	labIDParam, _ := strconv.Atoi(ctx.QueryParam("lab_id"))
+	filter := dtos.PeminjamanFilter{
+		LabID:   uint(labIDParam),
+		LabName: ctx.QueryParam("name"),
+		Status:  ctx.QueryParam("status"),
+		From:    ctx.QueryParam("from"),
+		To:      ctx.QueryParam("to"),
+		Sort:    ctx.QueryParam("sort"),
+		Order:   ctx.QueryParam("order"),
+	}
 
-	peminjamans, count, err := c.peminjamanUsecase.GetPeminjamans(page, limit, userId, nameLaboratoriumParam, statusParam)
+	peminjamans, count, err := c.peminjamanUsecase.GetPeminjamans(page, limit, userId, filter)
 	if err != nil {
 		return ctx.JSON(
 			http.StatusBadRequest,
@@ -121,11 +144,20 @@ func (c *peminjamanController) GetPeminjamansByAdmin(ctx echo.Context) error {
 		limit = 1000
 	}
 
-	searchParam := ctx.QueryParam("search")
-	statusParam := ctx.QueryParam("status")
-
+	// search tetap mencari nama lab agar klien lama tidak berubah perilaku
+	labIDParam, _ := strconv.Atoi(ctx.QueryParam("lab_id"))
+	filter := dtos.PeminjamanFilter{
+		LabID:   uint(labIDParam),
+		LabName: ctx.QueryParam("search"),
+		User:    ctx.QueryParam("user"),
+		Status:  ctx.QueryParam("status"),
+		From:    ctx.QueryParam("from"),
+		To:      ctx.QueryParam("to"),
+		Sort:    ctx.QueryParam("sort"),
+		Order:   ctx.QueryParam("order"),
+	}
 
-	peminjaman, count, err := c.peminjamanUsecase.GetPeminjamansByAdmin(page, limit, searchParam, statusParam)
+	peminjaman, count, err := c.peminjamanUsecase.GetPeminjamansByAdmin(page, limit, filter)
 	if err != nil {
 		return ctx.JSON(
 			http.StatusBadRequest,
@@ -289,12 +321,38 @@ func (c *peminjamanController) CreatePeminjaman(ctx echo.Context) error {
 		)
 	}
 
+	// Peminjaman berulang membuat satu series beserta peminjaman untuk setiap pertemuan
+	if peminjamanDTO.Recurrence != nil {
+		series, err := c.peminjamanUsecase.CreatePeminjamanSeries(userId, &peminjamanDTO)
+		if err != nil {
+			statusCode := helpers.GetStatusCode(err, http.StatusBadRequest)
+			return ctx.JSON(
+				statusCode,
+				helpers.NewErrorResponse(
+					statusCode,
+					"Failed to created a peminjaman series",
+					helpers.GetErrorData(err),
+				),
+			)
+		}
+
+		return ctx.JSON(
+			http.StatusCreated,
+			helpers.NewResponse(
+				http.StatusCreated,
+				"Successfully to created a peminjaman series",
+				series,
+			),
+		)
+	}
+
 	peminjaman, err := c.peminjamanUsecase.CreatePeminjaman(userId, &peminjamanDTO)
 	if err != nil {
+		statusCode := helpers.GetStatusCode(err, http.StatusBadRequest)
 		return ctx.JSON(
-			http.StatusBadRequest,
+			statusCode,
 			helpers.NewErrorResponse(
-				http.StatusBadRequest,
+				statusCode,
 				"Failed to created a peminjaman",
 				helpers.GetErrorData(err),
 			),
@@ -314,6 +372,30 @@ func (c *peminjamanController) CreatePeminjaman(ctx echo.Context) error {
 //error func
 
 func (c *peminjamanController) UpdatePeminjaman(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
 
 	// Binding input peminjaman
 	var peminjamanInput dtos.PeminjamanInput
@@ -345,12 +427,13 @@ func (c *peminjamanController) UpdatePeminjaman(ctx echo.Context) error {
 	}
 
 	// Perbarui peminjaman
-	peminjamanResp, err := c.peminjamanUsecase.UpdatePeminjaman(uint(id), peminjamanInput)
+	peminjamanResp, err := c.peminjamanUsecase.UpdatePeminjaman(uint(id), adminId, peminjamanInput)
 	if err != nil {
+		statusCode := helpers.GetStatusCode(err, http.StatusBadRequest)
 		return ctx.JSON(
-			http.StatusBadRequest,
+			statusCode,
 			helpers.NewErrorResponse(
-				http.StatusBadRequest,
+				statusCode,
 				"Failed to update peminjaman",
 				helpers.GetErrorData(err),
 			),
@@ -369,7 +452,313 @@ func (c *peminjamanController) UpdatePeminjaman(ctx echo.Context) error {
 }
 
 
+// BulkUpdatePeminjaman memproses banyak peminjaman sekaligus dan mengembalikan laporan per item
+func (c *peminjamanController) BulkUpdatePeminjaman(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var bulkInput dtos.PeminjamanBulkStatusInput
+	if err := ctx.Bind(&bulkInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding bulk peminjaman",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	bulkResp, err := c.peminjamanUsecase.BulkUpdatePeminjamanStatus(adminId, bulkInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to bulk update peminjaman",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully processed bulk peminjaman",
+			bulkResp,
+		),
+	)
+}
+
+
+func (c *peminjamanController) RequestReschedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var rescheduleInput dtos.PeminjamanRescheduleInput
+	if err := ctx.Bind(&rescheduleInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding reschedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	rescheduleResp, err := c.peminjamanUsecase.RequestReschedule(userId, uint(id), rescheduleInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to request reschedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusCreated,
+		helpers.NewResponse(
+			http.StatusCreated,
+			"Successfully requested reschedule",
+			rescheduleResp,
+		),
+	)
+}
+
+func (c *peminjamanController) GetReschedules(ctx echo.Context) error {
+	pageParam := ctx.QueryParam("page")
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		page = 1
+	}
+
+	limitParam := ctx.QueryParam("limit")
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		limit = 10
+	}
+
+	reschedules, count, err := c.peminjamanUsecase.GetReschedules(page, limit, ctx.QueryParam("status"))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get reschedules",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewPaginationResponse(
+			http.StatusOK,
+			"Successfully get reschedules",
+			reschedules,
+			page,
+			limit,
+			count,
+		),
+	)
+}
+
+func (c *peminjamanController) DecideReschedule(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	var decisionInput dtos.PeminjamanRescheduleDecisionInput
+	if err := ctx.Bind(&decisionInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding reschedule decision",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	rescheduleResp, err := c.peminjamanUsecase.DecideReschedule(adminId, uint(id), decisionInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to decide reschedule",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully decided reschedule",
+			rescheduleResp,
+		),
+	)
+}
+
+func (c *peminjamanController) CancelPeminjaman(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var cancelInput dtos.PeminjamanCancelInput
+	if err := ctx.Bind(&cancelInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding peminjaman cancellation",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	peminjaman, err := c.peminjamanUsecase.CancelPeminjaman(userId, uint(id), cancelInput)
+	if err != nil {
+		statusCode := helpers.GetStatusCode(err, http.StatusBadRequest)
+		return ctx.JSON(
+			statusCode,
+			helpers.NewErrorResponse(
+				statusCode,
+				"Failed to cancel peminjaman",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully cancelled peminjaman",
+			peminjaman,
+		),
+	)
+}
+
 func (c *peminjamanController) AdminUpdatePeminjaman(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
 
 	// Binding input peminjaman
 	var peminjamanInput dtos.PeminjamanInput
@@ -401,12 +790,13 @@ func (c *peminjamanController) AdminUpdatePeminjaman(ctx echo.Context) error {
 	}
 
 	// Perbarui peminjaman
-	peminjamanResp, err := c.peminjamanUsecase.AdminUpdatePeminjaman(uint(id), peminjamanInput)
+	peminjamanResp, err := c.peminjamanUsecase.AdminUpdatePeminjaman(uint(id), adminId, peminjamanInput)
 	if err != nil {
+		statusCode := helpers.GetStatusCode(err, http.StatusBadRequest)
 		return ctx.JSON(
-			http.StatusBadRequest,
+			statusCode,
 			helpers.NewErrorResponse(
-				http.StatusBadRequest,
+				statusCode,
 				"Failed to update peminjaman",
 				helpers.GetErrorData(err),
 			),
@@ -424,3 +814,134 @@ func (c *peminjamanController) AdminUpdatePeminjaman(ctx echo.Context) error {
 	)
 }
 
+func (c *peminjamanController) GetPeminjamanSeriesByID(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	series, err := c.peminjamanUsecase.GetPeminjamanSeriesByID(userId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get peminjaman series by id",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get peminjaman series by id",
+			series,
+		),
+	)
+}
+
+func (c *peminjamanController) AdminGetPeminjamanSeriesByID(ctx echo.Context) error {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	series, err := c.peminjamanUsecase.GetPeminjamanSeriesByID(0, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to get peminjaman series by id",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully to get peminjaman series by id",
+			series,
+		),
+	)
+}
+
+func (c *peminjamanController) UpdatePeminjamanSeries(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	adminId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var seriesInput dtos.PeminjamanSeriesStatusInput
+	if err := ctx.Bind(&seriesInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding peminjaman series",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	series, err := c.peminjamanUsecase.UpdatePeminjamanSeries(uint(id), adminId, seriesInput)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed to update peminjaman series",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully updated peminjaman series",
+			series,
+		),
+	)
+}
@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"net/http"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/middlewares"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (c *peminjamanController) GetPeminjamanQRCode(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	role, err := middlewares.GetRoleFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	// Admin boleh mengambil QR peminjaman milik siapa saja
+	if role == "admin" {
+		userId = 0
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	png, err := c.peminjamanUsecase.GetPeminjamanQRCode(userId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to get check-in QR code",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.Blob(http.StatusOK, "image/png", png)
+}
+
+func (c *peminjamanController) GetJadwalQRCode(ctx echo.Context) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	role, err := middlewares.GetRoleFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+	if role == "admin" {
+		userId = 0
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+
+	png, err := c.peminjamanUsecase.GetJadwalQRCode(userId, uint(id))
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to get check-in QR code",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.Blob(http.StatusOK, "image/png", png)
+}
+
+func (c *peminjamanController) Checkin(ctx echo.Context) error {
+	return c.handleCheckin(ctx, c.peminjamanUsecase.Checkin, "check-in")
+}
+
+func (c *peminjamanController) Checkout(ctx echo.Context) error {
+	return c.handleCheckin(ctx, c.peminjamanUsecase.Checkout, "check-out")
+}
+
+func (c *peminjamanController) handleCheckin(ctx echo.Context, action func(actorID uint, role string, input dtos.CheckinInput) (dtos.CheckinResponse, error), name string) error {
+	tokenString := middlewares.GetTokenFromHeader(ctx.Request())
+	if tokenString == "" {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				"Unauthorized",
+			),
+		)
+	}
+
+	userId, err := middlewares.GetUserIdFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	role, err := middlewares.GetRoleFromToken(tokenString)
+	if err != nil {
+		return ctx.JSON(
+			http.StatusUnauthorized,
+			helpers.NewErrorResponse(
+				http.StatusUnauthorized,
+				"No token provided",
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	var checkinInput dtos.CheckinInput
+	if err := ctx.Bind(&checkinInput); err != nil {
+		return ctx.JSON(
+			http.StatusBadRequest,
+			helpers.NewErrorResponse(
+				http.StatusBadRequest,
+				"Failed binding "+name,
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	checkin, err := action(userId, role, checkinInput)
+	if err != nil {
+		return ctx.JSON(
+			helpers.GetStatusCode(err, http.StatusBadRequest),
+			helpers.NewErrorResponse(
+				helpers.GetStatusCode(err, http.StatusBadRequest),
+				"Failed to "+name,
+				helpers.GetErrorData(err),
+			),
+		)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		helpers.NewResponse(
+			http.StatusOK,
+			"Successfully "+name,
+			checkin,
+		),
+	)
+}
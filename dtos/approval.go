@@ -0,0 +1,60 @@
+package dtos
+
+import "time"
+
+type LabApprovalStepInput struct {
+	Name           string `form:"name" json:"name" example:"Dosen Pembimbing"`
+	ApproverRole   string `form:"approver_role" json:"approver_role" example:"dosen"`
+	ApproverUserID *uint  `form:"approver_user_id" json:"approver_user_id,omitempty" example:"2"`
+}
+
+type LabApprovalStepsInput struct {
+	Steps []LabApprovalStepInput `form:"steps" json:"steps"`
+}
+
+type LabApprovalStepResponse struct {
+	StepOrder      int    `json:"step_order" example:"1"`
+	Name           string `json:"name" example:"Dosen Pembimbing"`
+	ApproverRole   string `json:"approver_role" example:"dosen"`
+	ApproverUserID *uint  `json:"approver_user_id,omitempty" example:"2"`
+}
+
+type LabApprovalChainResponse struct {
+	LabID uint                      `json:"lab_id" example:"1"`
+	Steps []LabApprovalStepResponse `json:"steps"`
+}
+
+type ApprovalDecisionInput struct {
+	Decision string `form:"decision" json:"decision" example:"approve"`
+	Comment  string `form:"comment" json:"comment" example:"Surat rekomendasi sudah sesuai"`
+}
+
+type PeminjamanApprovalResponse struct {
+	StepOrder      int        `json:"step_order" example:"1"`
+	Name           string     `json:"name" example:"Dosen Pembimbing"`
+	ApproverRole   string     `json:"approver_role" example:"dosen"`
+	ApproverUserID *uint      `json:"approver_user_id,omitempty" example:"2"`
+	Status         string     `json:"status" example:"pending"`
+	DecidedByID    *uint      `json:"decided_by_id,omitempty" example:"2"`
+	Comment        string     `json:"comment,omitempty"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type PendingApprovalResponse struct {
+	PeminjamanID      uint                          `json:"peminjaman_id" example:"1"`
+	TanggalPeminjaman string                        `json:"tanggal_peminjaman" example:"2002-09-12"`
+	JamPeminjaman     string                        `json:"jam_peminjaman" example:"09:00"`
+	JamSelesai        string                        `json:"jam_selesai" example:"12:00"`
+	Description       string                        `json:"description"`
+	Lab               LabByIDResponses              `json:"lab"`
+	User              *UserInformationResponses     `json:"user,omitempty"`
+	Reschedule        *PeminjamanRescheduleResponse `json:"reschedule,omitempty"`
+	CurrentStep       PeminjamanApprovalResponse    `json:"current_step"`
+}
+
+type ApprovalDecisionResponse struct {
+	PeminjamanID uint                         `json:"peminjaman_id" example:"1"`
+	RescheduleID *uint                        `json:"reschedule_id,omitempty" example:"1"`
+	Status       string                       `json:"status" example:"request"`
+	Approvals    []PeminjamanApprovalResponse `json:"approvals"`
+}
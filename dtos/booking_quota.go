@@ -0,0 +1,48 @@
+package dtos
+
+import "time"
+
+// BookingQuotaInput dipakai admin untuk mengatur kuota, batas yang dikosongkan berarti tidak dibatasi
+type BookingQuotaInput struct {
+	Role               string `form:"role" json:"role" example:"user"`
+	LabID              *uint  `form:"lab_id" json:"lab_id,omitempty" example:"1"`
+	MaxActiveRequests  *int   `form:"max_active_requests" json:"max_active_requests,omitempty" example:"2"`
+	MaxBookingsPerWeek *int   `form:"max_bookings_per_week" json:"max_bookings_per_week,omitempty" example:"3"`
+	MaxHoursPerMonth   *int   `form:"max_hours_per_month" json:"max_hours_per_month,omitempty" example:"24"`
+}
+
+type BookingQuotaResponse struct {
+	BookingQuotaID     uint              `json:"booking_quota_id" example:"1"`
+	Role               string            `json:"role" example:"user"`
+	Lab                *LabByIDResponses `json:"lab,omitempty"`
+	MaxActiveRequests  *int              `json:"max_active_requests" example:"2"`
+	MaxBookingsPerWeek *int              `json:"max_bookings_per_week" example:"3"`
+	MaxHoursPerMonth   *int              `json:"max_hours_per_month" example:"24"`
+	CreatedAt          time.Time         `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt          time.Time         `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+// QuotaStatusResponse adalah pemakaian satu kuota oleh user pada minggu dan bulan tertentu
+type QuotaStatusResponse struct {
+	BookingQuotaID  uint               `json:"booking_quota_id" example:"1"`
+	Role            string             `json:"role" example:"user"`
+	Lab             *LabByIDResponses  `json:"lab,omitempty"`
+	WeekStart       string             `json:"week_start" example:"2023-05-15"`
+	WeekEnd         string             `json:"week_end" example:"2023-05-21"`
+	Month           string             `json:"month" example:"2023-05"`
+	ActiveRequests  QuotaCountResponse `json:"active_requests"`
+	BookingsPerWeek QuotaCountResponse `json:"bookings_per_week"`
+	HoursPerMonth   QuotaHoursResponse `json:"hours_per_month"`
+}
+
+type QuotaCountResponse struct {
+	Limit     *int `json:"limit" example:"3"`
+	Used      int  `json:"used" example:"2"`
+	Remaining *int `json:"remaining" example:"1"`
+}
+
+type QuotaHoursResponse struct {
+	Limit     *int     `json:"limit" example:"24"`
+	Used      float64  `json:"used" example:"6"`
+	Remaining *float64 `json:"remaining" example:"18"`
+}
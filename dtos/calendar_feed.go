@@ -0,0 +1,12 @@
+package dtos
+
+import "time"
+
+// CalendarFeedResponse berisi alamat feed kalender pribadi yang bisa ditambahkan ke aplikasi
+// kalender. Siapa pun yang mengetahui token bisa membaca feed, rotasi token membatalkan alamat lama.
+type CalendarFeedResponse struct {
+	Token     string     `json:"token" example:"3f9a0c6e2b..."`
+	FeedURL   string     `json:"feed_url" example:"https://api.example.com/api/v1/public/calendar/user/3f9a0c6e2b....ics"`
+	RotatedAt *time.Time `json:"rotated_at" example:"2024-05-17T15:07:16.504+07:00"`
+	CreatedAt time.Time  `json:"created_at" example:"2024-05-17T15:07:16.504+07:00"`
+}
@@ -0,0 +1,23 @@
+package dtos
+
+import "time"
+
+type CheckinInput struct {
+	Token string `form:"token" json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+type CheckinResponse struct {
+	Kind          string     `json:"kind" example:"peminjaman"`
+	ID            uint       `json:"id" example:"1"`
+	Status        string     `json:"status" example:"in_use"`
+	ActualStartAt *time.Time `json:"actual_start_at,omitempty" example:"2023-05-17T09:02:16.504+07:00"`
+	ActualEndAt   *time.Time `json:"actual_end_at,omitempty" example:"2023-05-17T11:45:16.504+07:00"`
+}
+
+type PeminjamanUsageResponse struct {
+	ActualStartAt   *time.Time `json:"actual_start_at,omitempty" example:"2023-05-17T09:02:16.504+07:00"`
+	ActualEndAt     *time.Time `json:"actual_end_at,omitempty" example:"2023-05-17T11:45:16.504+07:00"`
+	DurationMinutes int        `json:"duration_minutes" example:"163"`
+	CheckedInByID   *uint      `json:"checked_in_by_id,omitempty" example:"2"`
+	CheckedOutByID  *uint      `json:"checked_out_by_id,omitempty" example:"2"`
+}
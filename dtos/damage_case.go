@@ -0,0 +1,52 @@
+package dtos
+
+import "time"
+
+// DamageCaseResolveInput menutup kasus kerusakan sebagai resolved (sudah diganti/diperbaiki) atau waived (dibebaskan)
+type DamageCaseResolveInput struct {
+	Status     string `form:"status" json:"status" example:"resolved"`
+	Resolution string `form:"resolution" json:"resolution" example:"Unit sudah diganti oleh peminjam"`
+}
+
+type DamageCaseResponse struct {
+	DamageCaseID       uint                   `json:"damage_case_id" example:"1"`
+	ReturnInspectionID uint                   `json:"return_inspection_id" example:"1"`
+	UserID             uint                   `json:"user_id" example:"1"`
+	LabID              uint                   `json:"lab_id" example:"1"`
+	EquipmentUnit      *EquipmentUnitResponse `json:"equipment_unit,omitempty"`
+	Condition          string                 `json:"condition" example:"damaged"`
+	Description        string                 `json:"description"`
+	Status             string                 `json:"status" example:"open"`
+	Resolution         string                 `json:"resolution,omitempty"`
+	ResolvedAt         *time.Time             `json:"resolved_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	CreatedAt          time.Time              `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt          time.Time              `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+// DamageCaseSummary adalah hasil agregasi kasus kerusakan per lab, kondisi, dan status
+type DamageCaseSummary struct {
+	LabID     uint   `json:"lab_id"`
+	Condition string `json:"condition"`
+	Status    string `json:"status"`
+	Total     int    `json:"total"`
+}
+
+type DamageCaseReportResponse struct {
+	From     string                  `json:"from" example:"2023-05-01"`
+	To       string                  `json:"to" example:"2023-05-31"`
+	Total    int                     `json:"total" example:"4"`
+	Open     int                     `json:"open" example:"2"`
+	Resolved int                     `json:"resolved" example:"1"`
+	Waived   int                     `json:"waived" example:"1"`
+	Damaged  int                     `json:"damaged" example:"3"`
+	Missing  int                     `json:"missing" example:"1"`
+	Labs     []DamageCaseLabResponse `json:"labs"`
+}
+
+type DamageCaseLabResponse struct {
+	Lab     LabByIDResponses `json:"lab"`
+	Total   int              `json:"total" example:"2"`
+	Open    int              `json:"open" example:"1"`
+	Damaged int              `json:"damaged" example:"2"`
+	Missing int              `json:"missing" example:"0"`
+}
@@ -24,6 +24,13 @@ type DashboardfilterResponse struct {
 }
 
 
+// DashboardLabCount adalah jumlah peminjaman atau jadwal satu lab dalam periode dashboard
+type DashboardLabCount struct {
+	LabID uint   `json:"lab_id"`
+	Name  string `json:"name"`
+	Total int    `json:"total"`
+}
+
 type FilterDashboardByMonthRequest struct {
 	Month int `json:"month"`
 	Year  int `json:"year"`
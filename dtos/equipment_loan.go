@@ -0,0 +1,49 @@
+package dtos
+
+import "time"
+
+type EquipmentLoanInput struct {
+	LabID          uint                     `form:"lab_id" json:"lab_id" example:"1"`
+	TanggalPinjam  *string                  `form:"tanggal_pinjam" json:"tanggal_pinjam" example:"2023-05-20"`
+	TanggalKembali *string                  `form:"tanggal_kembali" json:"tanggal_kembali" example:"2023-05-22"`
+	Description    string                   `form:"description" json:"description"`
+	Items          []EquipmentLoanItemInput `form:"items" json:"items"`
+}
+
+type EquipmentLoanItemInput struct {
+	EquipmentTypeID uint `form:"equipment_type_id" json:"equipment_type_id" example:"1"`
+	Quantity        int  `form:"quantity" json:"quantity" example:"2"`
+}
+
+type EquipmentLoanDecisionInput struct {
+	Decision string `form:"decision" json:"decision" example:"approve"`
+	Note     string `form:"note" json:"note"`
+}
+
+// EquipmentLoanHandoverInput berisi unit yang diserahkan, jika kosong unit dipilih otomatis
+type EquipmentLoanHandoverInput struct {
+	UnitIDs []uint `form:"unit_ids" json:"unit_ids"`
+}
+
+type EquipmentLoanResponse struct {
+	EquipmentLoanID uint                        `json:"equipment_loan_id" example:"1"`
+	TanggalPinjam   string                      `json:"tanggal_pinjam" example:"2023-05-20"`
+	TanggalKembali  string                      `json:"tanggal_kembali" example:"2023-05-22"`
+	Description     string                      `json:"description"`
+	Status          string                      `json:"status" example:"request"`
+	AdminNote       string                      `json:"admin_note,omitempty"`
+	DecidedAt       *time.Time                  `json:"decided_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	HandedOverAt    *time.Time                  `json:"handed_over_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	ReturnedAt      *time.Time                  `json:"returned_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	Items           []EquipmentLoanItemResponse `json:"items"`
+	Units           []EquipmentUnitResponse     `json:"units,omitempty"`
+	Lab             LabByIDResponses            `json:"lab"`
+	User            *UserInformationResponses   `json:"user,omitempty"`
+	CreatedAt       time.Time                   `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt       time.Time                   `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type EquipmentLoanItemResponse struct {
+	EquipmentType EquipmentTypeResponse `json:"equipment_type"`
+	Quantity      int                   `json:"quantity" example:"2"`
+}
@@ -0,0 +1,42 @@
+package dtos
+
+import "time"
+
+// PeminjamanExportRow adalah satu baris export peminjaman, waktu sudah diformat oleh query
+type PeminjamanExportRow struct {
+	ID                uint
+	FullName          string
+	NIMNIP            string
+	LabName           string
+	TanggalPeminjaman string
+	JamPeminjaman     string
+	JamSelesai        string
+	Status            string
+	Description       string
+	RequestedAt       string
+	AcceptedAt        string
+	RejectedAt        string
+	CancelledAt       string
+}
+
+// JadwalExportFilter memakai filter name_laboratorium dari daftar jadwal ditambah rentang tanggal jadwal
+type JadwalExportFilter struct {
+	NameLaboratorium string
+	From             *time.Time
+	To               *time.Time
+}
+
+// JadwalExportRow adalah satu baris export jadwal. AcceptedAt terisi jika jadwal berasal dari peminjaman.
+type JadwalExportRow struct {
+	ID            uint
+	FullName      string
+	NIMNIP        string
+	LabName       string
+	TanggalJadwal string
+	WaktuJadwal   string
+	WaktuSelesai  string
+	Status        string
+	PeminjamanID  uint
+	CreatedAt     string
+	AcceptedAt    string
+}
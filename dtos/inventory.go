@@ -0,0 +1,70 @@
+package dtos
+
+import "time"
+
+type EquipmentTypeInput struct {
+	Name        string `form:"name" json:"name" example:"Osiloskop Digital"`
+	Category    string `form:"category" json:"category" example:"Alat Ukur"`
+	Description string `form:"description" json:"description"`
+	ImageUrl    string `form:"image_url" json:"image_url"`
+}
+
+type EquipmentTypeResponse struct {
+	EquipmentTypeID uint      `json:"equipment_type_id" example:"1"`
+	Name            string    `json:"name" example:"Osiloskop Digital"`
+	Category        string    `json:"category" example:"Alat Ukur"`
+	Description     string    `json:"description"`
+	ImageUrl        string    `json:"image_url"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt       time.Time `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type EquipmentUnitInput struct {
+	EquipmentTypeID uint   `form:"equipment_type_id" json:"equipment_type_id" example:"1"`
+	LabID           uint   `form:"lab_id" json:"lab_id" example:"1"`
+	SerialNumber    string `form:"serial_number" json:"serial_number" example:"OSC-2023-001"`
+	Condition       string `form:"condition" json:"condition,omitempty" example:"good"`
+	Status          string `form:"status" json:"status,omitempty" example:"available"`
+	Notes           string `form:"notes" json:"notes"`
+}
+
+type EquipmentUnitResponse struct {
+	EquipmentUnitID uint                  `json:"equipment_unit_id" example:"1"`
+	EquipmentType   EquipmentTypeResponse `json:"equipment_type"`
+	LabID           uint                  `json:"lab_id" example:"1"`
+	SerialNumber    string                `json:"serial_number" example:"OSC-2023-001"`
+	Condition       string                `json:"condition" example:"good"`
+	Status          string                `json:"status" example:"available"`
+	Notes           string                `json:"notes"`
+	CreatedAt       time.Time             `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt       time.Time             `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+// EquipmentStock adalah hasil agregasi unit per lab dan jenis alat
+type EquipmentStock struct {
+	LabID           uint `json:"lab_id"`
+	EquipmentTypeID uint `json:"equipment_type_id"`
+	Total           int  `json:"total"`
+	Available       int  `json:"available"`
+	Loaned          int  `json:"loaned"`
+	Maintenance     int  `json:"maintenance"`
+}
+
+type EquipmentStockResponse struct {
+	Lab           LabByIDResponses      `json:"lab"`
+	EquipmentType EquipmentTypeResponse `json:"equipment_type"`
+	Total         int                   `json:"total" example:"5"`
+	Available     int                   `json:"available" example:"3"`
+	Loaned        int                   `json:"loaned" example:"1"`
+	Maintenance   int                   `json:"maintenance" example:"1"`
+}
+
+type EquipmentCatalogueResponse struct {
+	EquipmentType EquipmentTypeResponse     `json:"equipment_type"`
+	Stocks        []EquipmentCatalogueStock `json:"stocks"`
+}
+
+type EquipmentCatalogueStock struct {
+	Lab       LabByIDResponses `json:"lab"`
+	Available int              `json:"available" example:"3"`
+}
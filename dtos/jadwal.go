@@ -6,6 +6,9 @@ import "time"
 type JadwalInput struct {
 	TanggalJadwal           *string 					   `form:"tanggal_jadwal" json:"tanggal_jadwal,omitempty" example:"2002-09-12"`
 	WaktuJadwal             string    					   `form:"waktu_jadwal" json:"waktu_jadwal" example:"09:00"`
+	LabSlotID               *uint                          `form:"lab_slot_id" json:"lab_slot_id,omitempty" example:"1"`
+	LabID                   *uint                          `form:"lab_id" json:"lab_id,omitempty" example:"1"`
+	UserID                  *uint                          `form:"user_id" json:"user_id,omitempty" example:"2"`
 	NameUser                string    					   `form:"name_user" json:"name_user"`
   	NameLaboratorium        string    					   `form:"name_lab" json:"name_lab"`
 	BeritaAcaraImage        []BeritaAcaraImageInput        `form:"beritaacara_image" json:"beritaacara_image"`
@@ -20,6 +23,14 @@ type JadwalResponse struct {
 	JadwalID           		int                      	   `form:"jadwal_id" json:"jadwal_id"`
 	TanggalJadwal           string 					   	   `form:"tanggal_jadwal" json:"tanggal_jadwal,omitempty" example:"2002-09-12"`
 	WaktuJadwal             string    					   `form:"waktu_jadwal" json:"waktu_jadwal" example:"09:00"`
+	WaktuSelesai            string                         `form:"waktu_selesai" json:"waktu_selesai" example:"12:00"`
+	LabSlotID               *uint                          `json:"lab_slot_id,omitempty" example:"1"`
+	LabID                   *uint                          `json:"lab_id,omitempty" example:"1"`
+	UserID                  *uint                          `json:"user_id,omitempty" example:"2"`
+	Lab                     *LabByIDResponses              `json:"lab,omitempty"`
+	User                    *UserInformationResponses      `json:"user,omitempty"`
+	PeminjamanID            *uint                          `json:"peminjaman_id,omitempty" example:"1"`
+	Peminjaman              *JadwalPeminjamanResponse      `json:"peminjaman,omitempty"`
 	NameUser                string    					   `form:"name_user" json:"name_user"`
   	NameLaboratorium        string    					   `form:"name_lab" json:"name_lab"`
 	BeritaAcaraImage        []BeritaAcaraImageResponse     `form:"beritaacara_image" json:"beritaacara_image"`
@@ -39,3 +50,13 @@ type JadwalUserResponses struct {
 	UpdatedAt       		*time.Time                     `json:"updated_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
 }
 
+
+// JadwalPeminjamanResponse merangkum peminjaman asal dari jadwal yang dibuat otomatis
+type JadwalPeminjamanResponse struct {
+	PeminjamanID      uint   `json:"peminjaman_id" example:"1"`
+	TanggalPeminjaman string `json:"tanggal_peminjaman" example:"2002-09-12"`
+	JamPeminjaman     string `json:"jam_peminjaman" example:"09:00"`
+	JamSelesai        string `json:"jam_selesai" example:"12:00"`
+	Description       string `json:"description"`
+	Status            string `json:"status" example:"accept"`
+}
@@ -0,0 +1,33 @@
+package dtos
+
+import "time"
+
+// LabClosureInput dipakai admin untuk menutup lab. LabID kosong berarti berlaku untuk semua lab,
+// jam dikosongkan berarti tutup seharian.
+type LabClosureInput struct {
+	LabID          *uint   `form:"lab_id" json:"lab_id,omitempty" example:"1"`
+	TanggalMulai   *string `form:"tanggal_mulai" json:"tanggal_mulai" example:"2024-08-17"`
+	TanggalSelesai *string `form:"tanggal_selesai" json:"tanggal_selesai,omitempty" example:"2024-08-17"`
+	JamMulai       string  `form:"jam_mulai" json:"jam_mulai,omitempty" example:"09:00"`
+	JamSelesai     string  `form:"jam_selesai" json:"jam_selesai,omitempty" example:"12:00"`
+	Reason         string  `form:"reason" json:"reason" example:"Hari Kemerdekaan"`
+}
+
+type LabClosureResponse struct {
+	LabClosureID   uint              `json:"lab_closure_id" example:"1"`
+	Lab            *LabByIDResponses `json:"lab,omitempty"`
+	TanggalMulai   string            `json:"tanggal_mulai" example:"2024-08-17"`
+	TanggalSelesai string            `json:"tanggal_selesai" example:"2024-08-17"`
+	JamMulai       string            `json:"jam_mulai,omitempty" example:"09:00"`
+	JamSelesai     string            `json:"jam_selesai,omitempty" example:"12:00"`
+	Reason         string            `json:"reason" example:"Hari Kemerdekaan"`
+	Source         string            `json:"source" example:"manual"`
+	CreatedAt      time.Time         `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt      time.Time         `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type LabClosureImportResponse struct {
+	Created  int                  `json:"created" example:"12"`
+	Updated  int                  `json:"updated" example:"2"`
+	Closures []LabClosureResponse `json:"closures"`
+}
@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+type LabSlotInput struct {
+	Weekday    *int   `form:"weekday" json:"weekday" example:"1"`
+	JamMulai   string `form:"jam_mulai" json:"jam_mulai" example:"09:00"`
+	JamSelesai string `form:"jam_selesai" json:"jam_selesai" example:"12:00"`
+	IsActive   *bool  `form:"is_active" json:"is_active,omitempty" example:"true"`
+}
+
+type LabSlotResponse struct {
+	LabSlotID  uint      `json:"lab_slot_id"`
+	LabID      uint      `json:"lab_id"`
+	Weekday    int       `json:"weekday" example:"1"`
+	JamMulai   string    `json:"jam_mulai" example:"09:00"`
+	JamSelesai string    `json:"jam_selesai" example:"12:00"`
+	IsActive   bool      `json:"is_active" example:"true"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
@@ -6,6 +6,7 @@ type LabInput struct {
 	Name            string                 `form:"name" json:"name"`
 	LabImage        []LabImageInput        `form:"lab_image" json:"lab_image"`
 	Description     string                 `form:"description" json:"description"`
+	Capacity        int                    `form:"capacity" json:"capacity" example:"30"`
 }
 
 type LabResponse struct {
@@ -13,6 +14,7 @@ type LabResponse struct {
 	Name            string                    `form:"name" json:"name"`  
 	LabImage        []LabImageResponse        `form:"lab_image" json:"lab_image"`
 	Description     string                    `form:"description" json:"description"`
+	Capacity        int                       `form:"capacity" json:"capacity" example:"30"`
 	CreatedAt       time.Time                 `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
 	UpdatedAt       time.Time                 `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
 }
@@ -21,6 +23,7 @@ type LabByIDResponse struct {
 	Name            string                     `form:"name" json:"name"`
 	LabImage        []LabImageResponse         `form:"lab_image" json:"lab_image"`
 	Description     string                     `form:"description" json:"description"`
+	Capacity        int                        `form:"capacity" json:"capacity" example:"30"`
 	CreatedAt       time.Time                  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
 	UpdatedAt       time.Time                  `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
 }
@@ -30,6 +33,7 @@ type LabByIDResponses struct {
 	Name            string                    `form:"name" json:"name"`
 	LabImage        []LabImageResponse        `form:"lab_image" json:"lab_image"`
 	Description     string                    `form:"description" json:"description"`
+	Capacity        int                       `form:"capacity" json:"capacity" example:"30"`
 	CreatedAt       *time.Time                `json:"created_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
 	UpdatedAt       *time.Time                `json:"updated_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
 }
@@ -42,3 +46,33 @@ type LabByIDSimply struct {
 	CreatedAt       *time.Time                `json:"created_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
 	UpdatedAt       *time.Time                `json:"updated_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
 }
+
+type LabSlotUsage struct {
+	Tanggal    string `json:"tanggal"`
+	Jam        string `json:"jam"`
+	JamSelesai string `json:"jam_selesai"`
+	Requested  int    `json:"requested"`
+	Booked    int    `json:"booked"`
+	Scheduled int    `json:"scheduled"`
+}
+
+type LabAvailabilityResponse struct {
+	LabID uint                  `json:"lab_id"`
+	Name  string                `json:"name"`
+	From  string                `json:"from" example:"2024-07-01"`
+	To    string                `json:"to" example:"2024-07-07"`
+	Dates []LabAvailabilityDate `json:"dates"`
+}
+
+type LabAvailabilityDate struct {
+	Tanggal  string                `json:"tanggal" example:"2024-07-01"`
+	Slots    []LabAvailabilitySlot `json:"slots"`
+	Closures []LabClosureResponse  `json:"closures,omitempty"`
+}
+
+type LabAvailabilitySlot struct {
+	LabSlotID  uint   `json:"lab_slot_id" example:"1"`
+	Jam        string `json:"jam" example:"09:00"`
+	JamSelesai string `json:"jam_selesai" example:"12:00"`
+	Status     string `json:"status" example:"free"`
+}
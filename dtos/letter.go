@@ -0,0 +1,51 @@
+package dtos
+
+import "time"
+
+// LetterTemplateInput dipakai admin untuk mengatur template surat peminjaman. Body dan Closing
+// boleh berisi placeholder {{nama}}, {{nim}}, {{lab}}, {{tanggal}}, {{jam_mulai}}, {{jam_selesai}},
+// {{approver}}, {{nomor}}, dan {{keperluan}}.
+type LetterTemplateInput struct {
+	Name               string `form:"name" json:"name" example:"Template Default"`
+	InstitutionName    string `form:"institution_name" json:"institution_name" example:"Laboratorium Teknik Informatika"`
+	InstitutionAddress string `form:"institution_address" json:"institution_address" example:"Jl. Kampus No. 1"`
+	Title              string `form:"title" json:"title" example:"SURAT IZIN PEMINJAMAN LABORATORIUM"`
+	Body               string `form:"body" json:"body" example:"Dengan ini menerangkan bahwa {{nama}} ({{nim}}) diizinkan menggunakan {{lab}}."`
+	Closing            string `form:"closing" json:"closing" example:"Demikian surat ini dibuat untuk dipergunakan sebagaimana mestinya."`
+	SignatoryName      string `form:"signatory_name" json:"signatory_name" example:"Dr. Budi"`
+	SignatoryTitle     string `form:"signatory_title" json:"signatory_title" example:"Kepala Laboratorium"`
+	IsActive           bool   `form:"is_active" json:"is_active" example:"true"`
+}
+
+type LetterTemplateResponse struct {
+	LetterTemplateID   uint      `json:"letter_template_id" example:"1"`
+	Name               string    `json:"name" example:"Template Default"`
+	InstitutionName    string    `json:"institution_name" example:"Laboratorium Teknik Informatika"`
+	InstitutionAddress string    `json:"institution_address" example:"Jl. Kampus No. 1"`
+	Title              string    `json:"title" example:"SURAT IZIN PEMINJAMAN LABORATORIUM"`
+	Body               string    `json:"body" example:"Dengan ini menerangkan bahwa {{nama}} ({{nim}}) diizinkan menggunakan {{lab}}."`
+	Closing            string    `json:"closing" example:"Demikian surat ini dibuat untuk dipergunakan sebagaimana mestinya."`
+	SignatoryName      string    `json:"signatory_name" example:"Dr. Budi"`
+	SignatoryTitle     string    `json:"signatory_title" example:"Kepala Laboratorium"`
+	IsActive           bool      `json:"is_active" example:"true"`
+	CreatedAt          time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt          time.Time `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+// LetterVerificationResponse adalah hasil verifikasi publik dari kode QR surat. Valid bernilai
+// false jika peminjaman sudah dibatalkan atau tidak lagi berstatus diterima.
+type LetterVerificationResponse struct {
+	Valid             bool       `json:"valid" example:"true"`
+	Code              string     `json:"code" example:"9F2C4A1B7E3D5C60A8B1"`
+	Number            string     `json:"number" example:"0001/PL/LAB/2024"`
+	IssuedAt          *time.Time `json:"issued_at" example:"2024-05-17T15:07:16.504+07:00"`
+	PeminjamanID      uint       `json:"peminjaman_id" example:"1"`
+	Status            string     `json:"status" example:"accept"`
+	BorrowerName      string     `json:"borrower_name" example:"Andi"`
+	NIMNIP            string     `json:"nim_nip" example:"1234567890"`
+	Lab               string     `json:"lab" example:"Lab Jaringan"`
+	TanggalPeminjaman string     `json:"tanggal_peminjaman" example:"2024-05-20"`
+	JamPeminjaman     string     `json:"jam_peminjaman" example:"08:00"`
+	JamSelesai        string     `json:"jam_selesai" example:"10:00"`
+	Approver          string     `json:"approver" example:"Dr. Budi"`
+}
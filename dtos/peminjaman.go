@@ -6,9 +6,13 @@ type PeminjamanInput struct {
 	LabID           			int                      			`form:"lab_id" json:"lab_id" example:"1"`
 	TanggalPeminjaman  			*string 					   		`form:"tanggal_peminjaman" json:"tanggal_peminjaman,omitempty" example:"2002-09-12"`
 	JamPeminjaman    			string    							`form:"jam_peminjaman" json:"jam_peminjaman" example:"09:00"`
+	LabSlotID                   *uint                               `form:"lab_slot_id" json:"lab_slot_id,omitempty" example:"1"`
 	SuratRekomendasiImage       []SuratRekomendasiImageInput        `form:"suratrekomendasi_image" json:"suratrekomendasi_image"`
 	Description     			string                 				`form:"description" json:"description"`
 	Status                 	    string    					   	    `form:"status" json:"status" example:"request"`
+	Reason                      string                              `form:"reason" json:"reason,omitempty"`
+	Recurrence                  *RecurrenceInput                    `form:"recurrence" json:"recurrence,omitempty"`
+	Participants                []PeminjamanParticipantInput        `form:"participants" json:"participants,omitempty"`
 }
 
 type StatusResponse struct {
@@ -19,13 +23,35 @@ type PeminjamanResponse struct {
 	PeminjamanID     			int                       			`json:"peminjaman_id" example:"1"`
 	TanggalPeminjaman  			string 					   			`form:"tanggal_peminjaman" json:"tanggal_peminjaman,omitempty" example:"2002-09-12"`
 	JamPeminjaman    			string    							`form:"jam_peminjaman" json:"jam_peminjaman" example:"09:00"`
+	JamSelesai                  string                              `form:"jam_selesai" json:"jam_selesai" example:"12:00"`
+	LabSlotID                   *uint                               `json:"lab_slot_id,omitempty" example:"1"`
 	SuratRekomendasiImage       []SuratRekomendasiImageResponse     `form:"suratrekomendasi_image" json:"suratrekomendasi_image"`
 	Description     			string                 				`form:"description" json:"description"`
 	Status                 	    string    					   	    `form:"status" json:"status" example:"request"`
+	SeriesID                    *uint                               `json:"series_id,omitempty" example:"1"`
+	CancelReason                string                              `json:"cancel_reason,omitempty"`
+	CancelledAt                 *time.Time                          `json:"cancelled_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	LateCancellation            bool                                `json:"late_cancellation"`
+	Usage                       *PeminjamanUsageResponse            `json:"usage,omitempty"`
+	Quota                       []QuotaStatusResponse               `json:"quota,omitempty"`
+	Approvals                   []PeminjamanApprovalResponse        `json:"approvals,omitempty"`
+	StatusLogs                  []PeminjamanStatusLogResponse       `json:"status_logs,omitempty"`
+	Participants                []PeminjamanParticipantResponse     `json:"participants,omitempty"`
+	Reschedules                 []PeminjamanRescheduleResponse      `json:"reschedules,omitempty"`
 	Lab            				LabByIDResponses        			`json:"lab"`
 	User           			   *UserInformationResponses 			`json:"user,omitempty"`
 	CreatedAt        			time.Time                			`json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
 	UpdatedAt        			time.Time                 			`json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
 }
 
+type PeminjamanCancelInput struct {
+	Reason string `form:"reason" json:"reason" example:"Kegiatan dibatalkan"`
+}
 
+type PeminjamanCancelResponse struct {
+	PeminjamanID     uint       `json:"peminjaman_id" example:"1"`
+	Status           string     `json:"status" example:"cancelled"`
+	CancelReason     string     `json:"cancel_reason" example:"Kegiatan dibatalkan"`
+	CancelledAt      *time.Time `json:"cancelled_at" example:"2023-05-17T15:07:16.504+07:00"`
+	LateCancellation bool       `json:"late_cancellation" example:"false"`
+}
@@ -0,0 +1,27 @@
+package dtos
+
+// PeminjamanBulkStatusInput dipakai admin untuk memproses banyak peminjaman sekaligus. Mode atomic
+// membatalkan semua perubahan jika ada satu item gagal, mode per_item memproses tiap item sendiri.
+type PeminjamanBulkStatusInput struct {
+	IDs    []uint `form:"ids" json:"ids" example:"1,2,3"`
+	Status string `form:"status" json:"status" example:"accept"`
+	Reason string `form:"reason" json:"reason,omitempty"`
+	Mode   string `form:"mode" json:"mode,omitempty" example:"per_item"`
+}
+
+type PeminjamanBulkStatusResult struct {
+	PeminjamanID uint   `json:"peminjaman_id" example:"1"`
+	FromStatus   string `json:"from_status,omitempty" example:"request"`
+	Status       string `json:"status" example:"accept"`
+	Result       string `json:"result" example:"updated"`
+	Message      string `json:"message,omitempty"`
+}
+
+type PeminjamanBulkStatusResponse struct {
+	Mode      string                       `json:"mode" example:"per_item"`
+	Status    string                       `json:"status" example:"accept"`
+	Total     int                          `json:"total" example:"3"`
+	Succeeded int                          `json:"succeeded" example:"2"`
+	Failed    int                          `json:"failed" example:"1"`
+	Results   []PeminjamanBulkStatusResult `json:"results"`
+}
@@ -0,0 +1,16 @@
+package dtos
+
+// PeminjamanFilter adalah filter daftar peminjaman yang seluruhnya dijalankan di SQL. UserID 0 berarti
+// semua peminjaman (admin), selain itu hanya peminjaman milik user atau kelompok yang diikutinya.
+// From dan To berformat YYYY-MM-DD dan inklusif, Sort berisi salah satu field yang didukung repository.
+type PeminjamanFilter struct {
+	UserID  uint
+	LabID   uint
+	LabName string
+	User    string
+	Status  string
+	From    string
+	To      string
+	Sort    string
+	Order   string
+}
@@ -0,0 +1,14 @@
+package dtos
+
+// PeminjamanParticipantInput diisi NIM untuk peserta yang terdaftar, atau nama saja untuk tamu
+type PeminjamanParticipantInput struct {
+	NIMNIP string `form:"nim_nip" json:"nim_nip,omitempty" example:"2009189"`
+	Name   string `form:"name" json:"name,omitempty" example:"Budi"`
+}
+
+type PeminjamanParticipantResponse struct {
+	UserID *uint  `json:"user_id,omitempty" example:"2"`
+	NIMNIP string `json:"nim_nip,omitempty" example:"2009189"`
+	Name   string `json:"name" example:"Budi"`
+	Guest  bool   `json:"guest" example:"false"`
+}
@@ -0,0 +1,36 @@
+package dtos
+
+import "time"
+
+type PeminjamanRescheduleInput struct {
+	TanggalPeminjaman *string `form:"tanggal_peminjaman" json:"tanggal_peminjaman" example:"2002-09-19"`
+	JamPeminjaman     string  `form:"jam_peminjaman" json:"jam_peminjaman" example:"13:00"`
+	LabSlotID         *uint   `form:"lab_slot_id" json:"lab_slot_id,omitempty" example:"2"`
+	Reason            string  `form:"reason" json:"reason" example:"Bentrok dengan ujian"`
+}
+
+type PeminjamanRescheduleDecisionInput struct {
+	Decision string `form:"decision" json:"decision" example:"approve"`
+	Note     string `form:"note" json:"note"`
+}
+
+type PeminjamanRescheduleResponse struct {
+	RescheduleID      uint       `json:"reschedule_id" example:"1"`
+	PeminjamanID      uint       `json:"peminjaman_id" example:"1"`
+	LabID             uint       `json:"lab_id,omitempty" example:"1"`
+	LabName           string     `json:"lab_name,omitempty" example:"Lab Komputer"`
+	RequestedByID     uint       `json:"requested_by_id" example:"2"`
+	FromTanggal       string     `json:"from_tanggal" example:"2002-09-12"`
+	FromJamPeminjaman string     `json:"from_jam_peminjaman" example:"09:00"`
+	FromJamSelesai    string     `json:"from_jam_selesai" example:"12:00"`
+	TanggalPeminjaman string     `json:"tanggal_peminjaman" example:"2002-09-19"`
+	JamPeminjaman     string     `json:"jam_peminjaman" example:"13:00"`
+	JamSelesai        string     `json:"jam_selesai" example:"16:00"`
+	LabSlotID         *uint      `json:"lab_slot_id,omitempty" example:"2"`
+	Reason            string     `json:"reason" example:"Bentrok dengan ujian"`
+	Status            string     `json:"status" example:"pending"`
+	DecidedByID       *uint      `json:"decided_by_id,omitempty" example:"1"`
+	DecisionNote      string     `json:"decision_note,omitempty"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	CreatedAt         time.Time  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
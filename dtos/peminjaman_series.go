@@ -0,0 +1,36 @@
+package dtos
+
+import "time"
+
+type RecurrenceInput struct {
+	Frequency string  `form:"frequency" json:"frequency" example:"weekly"`
+	Until     *string `form:"until" json:"until,omitempty" example:"2024-12-20"`
+	Count     int     `form:"count" json:"count,omitempty" example:"14"`
+}
+
+type PeminjamanSeriesStatusInput struct {
+	Status string `form:"status" json:"status" example:"accept"`
+	Reason string `form:"reason" json:"reason"`
+}
+
+type PeminjamanSeriesOccurrence struct {
+	PeminjamanID      uint   `json:"peminjaman_id,omitempty" example:"1"`
+	TanggalPeminjaman string `json:"tanggal_peminjaman" example:"2002-09-12"`
+	Status            string `json:"status" example:"request"`
+	Message           string `json:"message,omitempty"`
+}
+
+type PeminjamanSeriesResponse struct {
+	SeriesID      uint                         `json:"series_id" example:"1"`
+	Frequency     string                       `json:"frequency" example:"weekly"`
+	TanggalMulai  string                       `json:"tanggal_mulai" example:"2002-09-12"`
+	Until         string                       `json:"until,omitempty" example:"2002-12-12"`
+	Count         int                          `json:"count,omitempty" example:"14"`
+	JamPeminjaman string                       `json:"jam_peminjaman" example:"09:00"`
+	JamSelesai    string                       `json:"jam_selesai" example:"12:00"`
+	Description   string                       `json:"description"`
+	Lab           LabByIDResponses             `json:"lab"`
+	Occurrences   []PeminjamanSeriesOccurrence `json:"occurrences"`
+	CreatedAt     time.Time                    `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+	UpdatedAt     time.Time                    `json:"updated_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
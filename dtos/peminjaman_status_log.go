@@ -0,0 +1,12 @@
+package dtos
+
+import "time"
+
+type PeminjamanStatusLogResponse struct {
+	ActorID    *uint     `json:"actor_id,omitempty" example:"1"`
+	ActorRole  string    `json:"actor_role" example:"admin"`
+	FromStatus string    `json:"from_status" example:"request"`
+	ToStatus   string    `json:"to_status" example:"accept"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
@@ -0,0 +1,26 @@
+package dtos
+
+import "time"
+
+type PeminjamanWaitlistInput struct {
+	LabID             int     `form:"lab_id" json:"lab_id" example:"1"`
+	TanggalPeminjaman *string `form:"tanggal_peminjaman" json:"tanggal_peminjaman,omitempty" example:"2002-09-12"`
+	JamPeminjaman     string  `form:"jam_peminjaman" json:"jam_peminjaman" example:"09:00"`
+	LabSlotID         *uint   `form:"lab_slot_id" json:"lab_slot_id,omitempty" example:"1"`
+	Description       string  `form:"description" json:"description"`
+}
+
+type PeminjamanWaitlistResponse struct {
+	WaitlistID        uint             `json:"waitlist_id" example:"1"`
+	TanggalPeminjaman string           `json:"tanggal_peminjaman" example:"2002-09-12"`
+	JamPeminjaman     string           `json:"jam_peminjaman" example:"09:00"`
+	JamSelesai        string           `json:"jam_selesai" example:"12:00"`
+	LabSlotID         *uint            `json:"lab_slot_id,omitempty" example:"1"`
+	Description       string           `json:"description"`
+	Status            string           `json:"status" example:"waiting"`
+	Position          int              `json:"position,omitempty" example:"1"`
+	PeminjamanID      *uint            `json:"peminjaman_id,omitempty" example:"1"`
+	PromotedAt        *time.Time       `json:"promoted_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	Lab               LabByIDResponses `json:"lab"`
+	CreatedAt         time.Time        `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
@@ -0,0 +1,51 @@
+package dtos
+
+import "time"
+
+// PenaltyInput dipakai admin untuk menambah poin penalti manual, ExpiresAt kosong berarti memakai masa berlaku default
+type PenaltyInput struct {
+	UserID    uint    `form:"user_id" json:"user_id" example:"1"`
+	Points    int     `form:"points" json:"points" example:"2"`
+	Reason    string  `form:"reason" json:"reason" example:"Meninggalkan lab dalam keadaan kotor"`
+	ExpiresAt *string `form:"expires_at" json:"expires_at" example:"2023-08-17"`
+}
+
+type PenaltyWaiveInput struct {
+	Note string `form:"note" json:"note" example:"Sudah dikonfirmasi dengan dosen pembimbing"`
+}
+
+type PenaltyAppealInput struct {
+	Reason string `form:"reason" json:"reason" example:"Saya sakit dan sudah mengirim surat keterangan"`
+}
+
+type PenaltyAppealDecisionInput struct {
+	Decision string `form:"decision" json:"decision" example:"approve"`
+	Note     string `form:"note" json:"note"`
+}
+
+type PenaltyResponse struct {
+	PenaltyID       uint       `json:"penalty_id" example:"1"`
+	UserID          uint       `json:"user_id" example:"1"`
+	Source          string     `json:"source" example:"no_show"`
+	Points          int        `json:"points" example:"3"`
+	Reason          string     `json:"reason"`
+	PeminjamanID    *uint      `json:"peminjaman_id,omitempty" example:"1"`
+	EquipmentLoanID *uint      `json:"equipment_loan_id,omitempty" example:"1"`
+	DamageCaseID    *uint      `json:"damage_case_id,omitempty" example:"1"`
+	Status          string     `json:"status" example:"active"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" example:"2023-08-17T15:07:16.504+07:00"`
+	Expired         bool       `json:"expired" example:"false"`
+	AppealReason    string     `json:"appeal_reason,omitempty"`
+	AppealedAt      *time.Time `json:"appealed_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	ResolutionNote  string     `json:"resolution_note,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty" example:"2023-05-17T15:07:16.504+07:00"`
+	CreatedAt       time.Time  `json:"created_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type PenaltySummaryResponse struct {
+	UserID      uint              `json:"user_id" example:"1"`
+	TotalPoints int               `json:"total_points" example:"4"`
+	Threshold   int               `json:"threshold" example:"10"`
+	Suspended   bool              `json:"suspended" example:"false"`
+	Penalties   []PenaltyResponse `json:"penalties"`
+}
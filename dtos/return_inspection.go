@@ -0,0 +1,37 @@
+package dtos
+
+import "time"
+
+// ReturnInspectionInput adalah hasil pemeriksaan yang dicatat admin. Untuk peminjaman alat,
+// kondisi setiap unit diisi lewat Items dan kondisi keseluruhan mengikuti unit terburuk.
+type ReturnInspectionInput struct {
+	Condition             string                       `form:"condition" json:"condition" example:"good"`
+	Notes                 string                       `form:"notes" json:"notes"`
+	Items                 []ReturnInspectionItemInput  `form:"items" json:"items"`
+	ReturnInspectionImage []ReturnInspectionImageInput `form:"return_inspection_image" json:"return_inspection_image"`
+}
+
+type ReturnInspectionItemInput struct {
+	EquipmentUnitID uint   `form:"equipment_unit_id" json:"equipment_unit_id" example:"1"`
+	Condition       string `form:"condition" json:"condition" example:"damaged"`
+	Notes           string `form:"notes" json:"notes"`
+}
+
+type ReturnInspectionResponse struct {
+	ReturnInspectionID    uint                            `json:"return_inspection_id" example:"1"`
+	PeminjamanID          *uint                           `json:"peminjaman_id,omitempty" example:"1"`
+	EquipmentLoanID       *uint                           `json:"equipment_loan_id,omitempty" example:"1"`
+	InspectorID           uint                            `json:"inspector_id" example:"1"`
+	Condition             string                          `json:"condition" example:"good"`
+	Notes                 string                          `json:"notes"`
+	Items                 []ReturnInspectionItemResponse  `json:"items,omitempty"`
+	ReturnInspectionImage []ReturnInspectionImageResponse `json:"return_inspection_image"`
+	DamageCases           []DamageCaseResponse            `json:"damage_cases,omitempty"`
+	InspectedAt           time.Time                       `json:"inspected_at" example:"2023-05-17T15:07:16.504+07:00"`
+}
+
+type ReturnInspectionItemResponse struct {
+	EquipmentUnit EquipmentUnitResponse `json:"equipment_unit"`
+	Condition     string                `json:"condition" example:"damaged"`
+	Notes         string                `json:"notes"`
+}
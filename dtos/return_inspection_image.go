@@ -0,0 +1,10 @@
+package dtos
+
+type ReturnInspectionImageInput struct {
+	ReturnInspectionImageUrl string `form:"return_inspection_image_url" json:"return_inspection_image_url"`
+}
+
+type ReturnInspectionImageResponse struct {
+	ReturnInspectionID       uint   `form:"return_inspection_id" json:"return_inspection_id"`
+	ReturnInspectionImageUrl string `form:"return_inspection_image_url" json:"return_inspection_image_url"`
+}
@@ -0,0 +1,31 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrSlotConflict              = errors.New("slot lab sudah dipinjam atau terjadwal pada tanggal dan jam tersebut")
+	ErrInvalidStatusTransition   = errors.New("perubahan status peminjaman tidak diizinkan")
+	ErrStatusTransitionForbidden = errors.New("anda tidak berhak melakukan perubahan status ini")
+	ErrInvalidCheckinToken       = errors.New("token check-in tidak valid atau sudah kedaluwarsa")
+	ErrStockInsufficient         = errors.New("stok alat tidak mencukupi untuk tanggal tersebut")
+	ErrBorrowingRestricted       = errors.New("akun anda sedang tidak dapat mengajukan peminjaman")
+	ErrQuotaExceeded             = errors.New("kuota peminjaman anda sudah habis")
+	ErrLabClosed                 = errors.New("lab tutup pada tanggal dan jam tersebut")
+	ErrLabCapacityExceeded       = errors.New("jumlah peserta melebihi kapasitas lab")
+)
+
+// GetStatusCode memetakan error usecase ke status code HTTP, selain itu fallback dipakai
+func GetStatusCode(err error, fallback int) int {
+	switch {
+	case errors.Is(err, ErrSlotConflict), errors.Is(err, ErrStockInsufficient), errors.Is(err, ErrLabClosed):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidStatusTransition), errors.Is(err, ErrLabCapacityExceeded), errors.Is(err, ErrQuotaExceeded):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrStatusTransitionForbidden), errors.Is(err, ErrInvalidCheckinToken), errors.Is(err, ErrBorrowingRestricted):
+		return http.StatusForbidden
+	}
+	return fallback
+}
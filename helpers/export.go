@@ -0,0 +1,188 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Format file export yang didukung
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// TableWriter menulis tabel baris demi baris ke writer tujuan tanpa menampung seluruh data di memori.
+// Close wajib dipanggil agar sisa buffer dan penutup file ikut tertulis.
+type TableWriter interface {
+	WriteRow(row []string) error
+	Close() error
+}
+
+// NewTableWriter membuat TableWriter sesuai format, format kosong berarti CSV
+func NewTableWriter(w io.Writer, format, sheetName string) (TableWriter, error) {
+	switch strings.ToLower(format) {
+	case "", ExportFormatCSV:
+		return NewCSVTableWriter(w)
+	case ExportFormatXLSX:
+		return NewXLSXTableWriter(w, sheetName)
+	}
+	return nil, errors.New("format export harus csv atau xlsx")
+}
+
+// ExportContentType mengembalikan Content-Type untuk format export
+func ExportContentType(format string) string {
+	if strings.ToLower(format) == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type csvTableWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVTableWriter menulis BOM UTF-8 di awal agar Excel membaca nama dengan aksen dengan benar
+func NewCSVTableWriter(w io.Writer) (TableWriter, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvTableWriter{csv.NewWriter(w)}, nil
+}
+
+// WriteRow menulis baris CSV. Sel yang diawali karakter rumus diberi awalan ' agar isian bebas dari
+// user, misalnya nama atau keperluan, tidak dijalankan sebagai rumus saat file dibuka di spreadsheet.
+func (t *csvTableWriter) WriteRow(row []string) error {
+	escaped := make([]string, len(row))
+	for i, cell := range row {
+		escaped[i] = escapeCSVFormula(cell)
+	}
+	return t.writer.Write(escaped)
+}
+
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func (t *csvTableWriter) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// xlsxTableWriter menulis workbook satu sheet. Bagian statis ditulis lebih dulu, kemudian sheet
+// ditulis sebagai entry zip terakhir sehingga baris bisa langsung dialirkan ke writer tujuan.
+type xlsxTableWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+}
+
+func NewXLSXTableWriter(w io.Writer, sheetName string) (TableWriter, error) {
+	archive := zip.NewWriter(w)
+
+	var escapedName strings.Builder
+	if err := xml.EscapeText(&escapedName, []byte(xlsxSheetName(sheetName))); err != nil {
+		return nil, err
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + escapedName.String() + `" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`</Relationships>`},
+		{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+			`<borders count="1"><border/></borders>` +
+			`<cellStyleXfs count="1"><xf/></cellStyleXfs>` +
+			`<cellXfs count="1"><xf/></cellXfs>` +
+			`</styleSheet>`},
+	}
+	for _, part := range parts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(entry)
+	if _, err := sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+
+	return &xlsxTableWriter{archive, sheet}, nil
+}
+
+// WriteRow menulis setiap sel sebagai inline string agar tidak perlu tabel shared string di memori
+func (t *xlsxTableWriter) WriteRow(row []string) error {
+	if _, err := t.sheet.WriteString("<row>"); err != nil {
+		return err
+	}
+	for _, cell := range row {
+		if _, err := t.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(t.sheet, []byte(cell)); err != nil {
+			return err
+		}
+		if _, err := t.sheet.WriteString("</t></is></c>"); err != nil {
+			return err
+		}
+	}
+	_, err := t.sheet.WriteString("</row>")
+	return err
+}
+
+func (t *xlsxTableWriter) Close() error {
+	if _, err := t.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	if err := t.sheet.Flush(); err != nil {
+		return err
+	}
+	return t.archive.Close()
+}
+
+// xlsxSheetName membuang karakter yang dilarang Excel pada nama sheet dan membatasi 31 karakter
+func xlsxSheetName(name string) string {
+	name = strings.NewReplacer(":", "", "\\", "", "/", "", "?", "", "*", "", "[", "", "]", "").Replace(name)
+	if name == "" {
+		name = "Sheet1"
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	return name
+}
@@ -0,0 +1,131 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSVTableWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewCSVTableWriter(&buf)
+	if err != nil {
+		t.Fatalf("gagal membuat writer: %v", err)
+	}
+
+	row := []string{"=HYPERLINK(\"http://x\")", "+62", "-1", "@SUM(A1)", "\tcmd", "Budi", "", "08:00"}
+	if err := writer.WriteRow(row); err != nil {
+		t.Fatalf("gagal menulis baris: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("gagal menutup writer: %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV tidak valid: %v", err)
+	}
+	want := []string{"'=HYPERLINK(\"http://x\")", "'+62", "'-1", "'@SUM(A1)", "'\tcmd", "Budi", "", "08:00"}
+	if len(records) != 1 || strings.Join(records[0], "|") != strings.Join(want, "|") {
+		t.Fatalf("baris CSV %q, seharusnya %q", records, want)
+	}
+	if row[0] != "=HYPERLINK(\"http://x\")" {
+		t.Fatalf("baris asli pemanggil tidak boleh diubah, didapat %q", row[0])
+	}
+}
+
+func TestXLSXTableWriterWritesWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewXLSXTableWriter(&buf, "Peminjaman: [2030/01]")
+	if err != nil {
+		t.Fatalf("gagal membuat writer: %v", err)
+	}
+
+	rows := [][]string{{"ID", "Nama"}, {"1", "Budi & <Ani>"}}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("gagal menulis baris: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("gagal menutup writer: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("xlsx bukan zip yang valid: %v", err)
+	}
+	entries := map[string]string{}
+	for _, file := range archive.File {
+		content, err := readZipEntry(file)
+		if err != nil {
+			t.Fatalf("gagal membaca %s: %v", file.Name, err)
+		}
+		entries[file.Name] = content
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		content, ok := entries[name]
+		if !ok {
+			t.Fatalf("entry %s tidak ada di xlsx", name)
+		}
+		if err := xml.Unmarshal([]byte(content), new(interface{})); err != nil {
+			t.Fatalf("entry %s bukan XML yang valid: %v", name, err)
+		}
+	}
+	if !strings.Contains(entries["xl/workbook.xml"], `name="Peminjaman 203001"`) {
+		t.Fatalf("nama sheet seharusnya dibersihkan dari karakter terlarang: %s", entries["xl/workbook.xml"])
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Type string `xml:"t,attr"`
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal([]byte(entries["xl/worksheets/sheet1.xml"]), &sheet); err != nil {
+		t.Fatalf("gagal membaca sheet: %v", err)
+	}
+	if len(sheet.Rows) != len(rows) {
+		t.Fatalf("sheet berisi %d baris, seharusnya %d", len(sheet.Rows), len(rows))
+	}
+	for i, row := range rows {
+		for j, want := range row {
+			cell := sheet.Rows[i].Cells[j]
+			if cell.Type != "inlineStr" || cell.Text != want {
+				t.Fatalf("sel %d,%d berisi %q (%s), seharusnya %q", i, j, cell.Text, cell.Type, want)
+			}
+		}
+	}
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Jadwal":           "Jadwal",
+		"":                 "Sheet1",
+		"a/b\\c?d*e[f]g:h": "abcdefgh",
+		"Peminjaman Laboratorium Elektronika 2030": "Peminjaman Laboratorium Elektro",
+	}
+	for name, want := range tests {
+		if got := xlsxSheetName(name); got != want {
+			t.Errorf("xlsxSheetName(%q) = %q, seharusnya %q", name, got, want)
+		}
+	}
+}
+
+func readZipEntry(file *zip.File) (string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	return string(content), err
+}
@@ -0,0 +1,238 @@
+package helpers
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICSTimezone adalah zona waktu yang dipakai saat menulis feed kalender
+const ICSTimezone = "Asia/Jakarta"
+
+// Status VEVENT yang dipakai saat menulis feed kalender
+const (
+	ICSStatusConfirmed = "CONFIRMED"
+	ICSStatusCancelled = "CANCELLED"
+)
+
+// ICSEvent adalah VEVENT pada file iCalendar, baik hasil parsing maupun yang ditulis ke feed. Untuk
+// event seharian, End bersifat eksklusif sesuai RFC 5545 (libur tanggal 1 memiliki End tanggal 2).
+type ICSEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Stamp       time.Time
+}
+
+// ParseICSEvents membaca VEVENT dari file .ics. Hanya properti UID, SUMMARY, DTSTART, dan DTEND
+// yang dipakai, properti lain diabaikan.
+func ParseICSEvents(r io.Reader) ([]ICSEvent, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// Baris yang diawali spasi atau tab adalah lanjutan baris sebelumnya (line folding)
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		events  []ICSEvent
+		current *ICSEvent
+		hasEnd  bool
+	)
+	for _, line := range lines {
+		name, params, value, ok := splitICSLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &ICSEvent{}
+			hasEnd = false
+		case name == "END" && value == "VEVENT":
+			if current == nil {
+				continue
+			}
+			if current.Start.IsZero() {
+				return nil, errors.New("event " + current.UID + " tidak memiliki DTSTART")
+			}
+			if !hasEnd {
+				current.End = current.Start
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescapeICSText(value)
+		case name == "DTSTART", name == "DTEND":
+			parsed, allDay, err := parseICSTime(params, value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "DTSTART" {
+				current.Start = parsed
+				current.AllDay = allDay
+			} else {
+				current.End = parsed
+				hasEnd = true
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func splitICSLine(line string) (name string, params map[string]string, value string, ok bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params = map[string]string{}
+	for _, param := range parts[1:] {
+		if eq := strings.Index(param, "="); eq >= 0 {
+			params[strings.ToUpper(param[:eq])] = strings.Trim(param[eq+1:], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+func parseICSTime(params map[string]string, value string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		parsed, err := time.ParseInLocation("20060102", value, time.Local)
+		return parsed, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		parsed, err := time.Parse("20060102T150405Z", value)
+		return parsed.In(time.Local), false, err
+	}
+
+	location := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	parsed, err := time.ParseInLocation("20060102T150405", value, location)
+	return parsed.In(time.Local), false, err
+}
+
+func unescapeICSText(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+// JakartaLocation mengembalikan zona Asia/Jakarta, atau WIB tetap jika tzdata tidak tersedia
+func JakartaLocation() *time.Location {
+	if location, err := time.LoadLocation(ICSTimezone); err == nil {
+		return location
+	}
+	return time.FixedZone("WIB", 7*60*60)
+}
+
+// WriteICSCalendar menulis VCALENDAR berisi events. Waktu ditulis dengan TZID Asia/Jakarta dan
+// DTSTAMP diambil dari Stamp agar klien kalender bisa mengenali perubahan event.
+func WriteICSCalendar(w io.Writer, name string, events []ICSEvent) error {
+	location := JakartaLocation()
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Sistem Peminjaman Lab//ID",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escapeICSText(name),
+		"X-WR-TIMEZONE:" + ICSTimezone,
+		"BEGIN:VTIMEZONE",
+		"TZID:" + ICSTimezone,
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:+0700",
+		"TZOFFSETTO:+0700",
+		"TZNAME:WIB",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	}
+
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+event.Stamp.UTC().Format("20060102T150405Z"),
+		)
+		if event.AllDay {
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+event.Start.Format("20060102"),
+				"DTEND;VALUE=DATE:"+event.End.Format("20060102"),
+			)
+		} else {
+			lines = append(lines,
+				"DTSTART;TZID="+ICSTimezone+":"+event.Start.In(location).Format("20060102T150405"),
+				"DTEND;TZID="+ICSTimezone+":"+event.End.In(location).Format("20060102T150405"),
+			)
+		}
+		lines = append(lines, "SUMMARY:"+escapeICSText(event.Summary))
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escapeICSText(event.Description))
+		}
+		if event.Location != "" {
+			lines = append(lines, "LOCATION:"+escapeICSText(event.Location))
+		}
+		if event.Status != "" {
+			lines = append(lines, "STATUS:"+event.Status)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICSLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foldICSLine memecah baris lebih dari 75 oktet sesuai RFC 5545 tanpa memotong karakter UTF-8
+func foldICSLine(line string) string {
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	return folded.String()
+}
+
+func escapeICSText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseICSEvents(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:libur-1",
+		"SUMMARY:Libur Nasional\\, Hari Raya",
+		"DTSTART;VALUE=DATE:20300101",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:rapat-1",
+		"SUMMARY:Rapat dosen yang judulnya panjang sehingga",
+		"  dilipat ke baris berikutnya",
+		"DTSTART;TZID=Asia/Jakarta:20300102T080000",
+		"DTEND;TZID=Asia/Jakarta:20300102T100000",
+		"LOCATION:Ruang Rapat",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:utc-1",
+		"DTSTART:20300103T010000Z",
+		"DTEND:20300103T020000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := ParseICSEvents(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("gagal parsing ics: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("dapat %d event, seharusnya 3", len(events))
+	}
+
+	allDay := events[0]
+	if allDay.UID != "libur-1" || allDay.Summary != "Libur Nasional, Hari Raya" || !allDay.AllDay {
+		t.Fatalf("event seharian tidak sesuai: %+v", allDay)
+	}
+	wantStart := time.Date(2030, 1, 1, 0, 0, 0, 0, time.Local)
+	if !allDay.Start.Equal(wantStart) || !allDay.End.Equal(wantStart.AddDate(0, 0, 1)) {
+		t.Fatalf("event seharian tanpa DTEND seharusnya berakhir esok harinya, didapat %s - %s", allDay.Start, allDay.End)
+	}
+
+	folded := events[1]
+	if folded.Summary != "Rapat dosen yang judulnya panjang sehingga dilipat ke baris berikutnya" {
+		t.Fatalf("baris yang dilipat tidak digabung: %q", folded.Summary)
+	}
+	jakarta := JakartaLocation()
+	if !folded.Start.Equal(time.Date(2030, 1, 2, 8, 0, 0, 0, jakarta)) || !folded.End.Equal(time.Date(2030, 1, 2, 10, 0, 0, 0, jakarta)) || folded.AllDay {
+		t.Fatalf("waktu event ber-TZID tidak sesuai: %s - %s", folded.Start, folded.End)
+	}
+
+	utc := events[2]
+	if !utc.Start.Equal(time.Date(2030, 1, 3, 1, 0, 0, 0, time.UTC)) || !utc.End.Equal(time.Date(2030, 1, 3, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("waktu event UTC tidak sesuai: %s - %s", utc.Start, utc.End)
+	}
+}
+
+func TestParseICSEventsRequiresStart(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:tanpa-mulai\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	if _, err := ParseICSEvents(strings.NewReader(ics)); err == nil {
+		t.Fatalf("event tanpa DTSTART seharusnya ditolak")
+	}
+}
+
+func TestParseICSEventsInvalidTime(t *testing.T) {
+	ics := "BEGIN:VEVENT\r\nUID:rusak\r\nDTSTART:2030-01-01T08:00\r\nEND:VEVENT\r\n"
+	if _, err := ParseICSEvents(strings.NewReader(ics)); err == nil {
+		t.Fatalf("DTSTART yang formatnya salah seharusnya ditolak")
+	}
+}
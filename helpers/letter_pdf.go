@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// LetterPDF berisi isi surat yang sudah diisi placeholder-nya dan siap dicetak
+type LetterPDF struct {
+	InstitutionName    string
+	InstitutionAddress string
+	Title              string
+	Number             string
+	Body               string
+	Closing            string
+	PlaceDate          string
+	SignatoryName      string
+	SignatoryTitle     string
+	QRCode             []byte
+	VerifyCode         string
+	VerifyURL          string
+}
+
+// RenderLetterPDF membuat surat A4 dengan kop, isi, tanda tangan, dan QR verifikasi di kiri bawah
+func RenderLetterPDF(letter LetterPDF) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(25, 20, 25)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentWidth := pageWidth - left - right
+
+	// Kop surat
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth, 7, tr(strings.ToUpper(letter.InstitutionName)), "", 1, "C", false, 0, "")
+	if letter.InstitutionAddress != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(contentWidth, 5, tr(letter.InstitutionAddress), "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(2)
+	pdf.SetLineWidth(0.6)
+	pdf.Line(left, pdf.GetY(), pageWidth-right, pdf.GetY())
+	pdf.Ln(8)
+
+	// Judul dan nomor surat
+	pdf.SetFont("Helvetica", "BU", 12)
+	pdf.CellFormat(contentWidth, 6, tr(letter.Title), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentWidth, 5, tr("Nomor: "+letter.Number), "", 1, "C", false, 0, "")
+	pdf.Ln(8)
+
+	// Isi surat
+	pdf.SetFont("Helvetica", "", 11)
+	for _, paragraph := range strings.Split(letter.Body, "\n") {
+		pdf.MultiCell(contentWidth, 6, tr(paragraph), "", "J", false)
+	}
+	if letter.Closing != "" {
+		pdf.Ln(4)
+		pdf.MultiCell(contentWidth, 6, tr(letter.Closing), "", "J", false)
+	}
+	pdf.Ln(10)
+
+	// Blok tanda tangan di kanan dan QR verifikasi di kiri
+	signatureX := left + contentWidth/2 + 10
+	signatureWidth := contentWidth/2 - 10
+	blockY := pdf.GetY()
+	if len(letter.QRCode) > 0 {
+		pdf.RegisterImageOptionsReader("verify-qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(letter.QRCode))
+		pdf.ImageOptions("verify-qr", left, blockY, 32, 32, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, letter.VerifyURL)
+		pdf.SetXY(left, blockY+33)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.CellFormat(contentWidth/2, 4, tr("Kode verifikasi: "+letter.VerifyCode), "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.SetXY(signatureX, blockY)
+	pdf.CellFormat(signatureWidth, 6, tr(letter.PlaceDate), "", 2, "L", false, 0, "")
+	pdf.CellFormat(signatureWidth, 6, tr(letter.SignatoryTitle), "", 2, "L", false, 0, "")
+	pdf.Ln(18)
+	pdf.SetX(signatureX)
+	pdf.SetFont("Helvetica", "BU", 11)
+	pdf.CellFormat(signatureWidth, 6, tr(letter.SignatoryName), "", 1, "L", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,19 +1,23 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
 	"sistem_peminjaman_be/configs"
 	"sistem_peminjaman_be/routes"
+	"sistem_peminjaman_be/scheduler"
+	"syscall"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
 func main() {
-	// create a new echo instance
-	e := echo.New()
-
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
+	// mode "all" menjalankan API dan scheduler, "api" hanya API, "worker" hanya scheduler
+	mode := flag.String("mode", "all", "run mode: all, api or worker")
+	flag.Parse()
 
 	db, err := configs.ConnectDB()
 	if err != nil {
@@ -25,6 +29,24 @@ func main() {
 		panic(err)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *mode == "worker" {
+		routes.InitScheduler(db, scheduler.RealClock{}).Start(ctx)
+		return
+	}
+
+	if *mode == "all" {
+		go routes.InitScheduler(db, scheduler.RealClock{}).Start(ctx)
+	}
+
+	// create a new echo instance
+	e := echo.New()
+
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+
 	routes.Init(e, db)
 
 	e.Logger.Fatal(e.Start(":8000"))
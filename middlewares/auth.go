@@ -114,7 +114,8 @@ func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-func RoleMiddleware(role string) echo.MiddlewareFunc {
+// RoleMiddleware hanya meneruskan request jika role pada token termasuk salah satu roles
+func RoleMiddleware(roles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			user := c.Get("user").(*jwt.Token)
@@ -124,8 +125,15 @@ func RoleMiddleware(role string) echo.MiddlewareFunc {
 			// fmt.Println("1", role)
 			// fmt.Println("2", userRole)
 
-			// Check if the user's role matches the required role
-			if userRole != role {
+			// Check if the user's role matches one of the required roles
+			allowed := false
+			for _, role := range roles {
+				if userRole == role {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
 				// Return an error response indicating unauthorized access
 				errorResponse := helpers.ErrorResponse{
 					StatusCode: http.StatusForbidden,
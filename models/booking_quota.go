@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// BookingQuota membatasi jumlah peminjaman user. Role kosong berarti berlaku untuk semua role dan
+// LabID kosong berarti dihitung di semua lab. Batas yang nil tidak dibatasi.
+type BookingQuota struct {
+	gorm.Model
+	Role               string `gorm:"type:VARCHAR(20)" form:"role" json:"role"`
+	LabID              *uint  `form:"lab_id" json:"lab_id"`
+	Lab                *Lab   `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	MaxActiveRequests  *int   `form:"max_active_requests" json:"max_active_requests"`
+	MaxBookingsPerWeek *int   `form:"max_bookings_per_week" json:"max_bookings_per_week"`
+	MaxHoursPerMonth   *int   `form:"max_hours_per_month" json:"max_hours_per_month"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeed menyimpan token rahasia feed kalender milik user. Token dipakai sebagai pengganti
+// login karena aplikasi kalender tidak bisa mengirim header Authorization.
+type CalendarFeed struct {
+	gorm.Model
+	UserID    uint       `gorm:"uniqueIndex" form:"user_id" json:"user_id"`
+	User      User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Token     string     `gorm:"type:VARCHAR(64);uniqueIndex" form:"token" json:"token"`
+	RotatedAt *time.Time `form:"rotated_at" json:"rotated_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status kasus kerusakan
+const (
+	DamageCaseStatusOpen     = "open"
+	DamageCaseStatusResolved = "resolved"
+	DamageCaseStatusWaived   = "waived"
+)
+
+// DamageCase adalah tindak lanjut dari pemeriksaan pengembalian yang menemukan kerusakan atau
+// kehilangan. Selama masih open, peminjam tidak bisa mengajukan peminjaman baru.
+type DamageCase struct {
+	gorm.Model
+	ReturnInspectionID uint             `form:"return_inspection_id" json:"return_inspection_id"`
+	ReturnInspection   ReturnInspection `gorm:"foreignKey:ReturnInspectionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID             uint             `form:"user_id" json:"user_id"`
+	User               User             `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	LabID              uint             `form:"lab_id" json:"lab_id"`
+	Lab                Lab              `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	EquipmentUnitID    *uint            `form:"equipment_unit_id" json:"equipment_unit_id"`
+	EquipmentUnit      *EquipmentUnit   `gorm:"foreignKey:EquipmentUnitID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Condition          string           `gorm:"type:ENUM('damaged', 'missing')" form:"condition" json:"condition"`
+	Description        string           `form:"description" json:"description"`
+	Status             string           `gorm:"type:ENUM('open', 'resolved', 'waived');default:'open'" form:"status" json:"status"`
+	Resolution         string           `form:"resolution" json:"resolution"`
+	ResolvedByID       *uint            `form:"resolved_by_id" json:"resolved_by_id"`
+	ResolvedAt         *time.Time       `form:"resolved_at" json:"resolved_at"`
+}
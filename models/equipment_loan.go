@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status peminjaman alat
+const (
+	EquipmentLoanStatusRequest    = "request"
+	EquipmentLoanStatusAccept     = "accept"
+	EquipmentLoanStatusReject     = "reject"
+	EquipmentLoanStatusCancelled  = "cancelled"
+	EquipmentLoanStatusHandedOver = "handed_over"
+	EquipmentLoanStatusReturned   = "returned"
+)
+
+// EquipmentLoan adalah peminjaman alat dari satu lab untuk rentang tanggal tertentu.
+// Jumlah per jenis alat disimpan di Items, unit yang benar-benar diserahkan dicatat di Units.
+type EquipmentLoan struct {
+	gorm.Model
+	UserID         uint                `form:"user_id" json:"user_id"`
+	User           User                `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	LabID          uint                `form:"lab_id" json:"lab_id"`
+	Lab            Lab                 `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TanggalPinjam  *time.Time          `gorm:"type:DATE"`
+	TanggalKembali *time.Time          `gorm:"type:DATE"`
+	Description    string              `form:"description" json:"description"`
+	Status         string              `gorm:"type:ENUM('request', 'accept', 'reject', 'cancelled', 'handed_over', 'returned')"`
+	AdminNote      string              `form:"admin_note" json:"admin_note"`
+	DecidedByID    *uint               `form:"decided_by_id" json:"decided_by_id"`
+	DecidedAt      *time.Time          `form:"decided_at" json:"decided_at"`
+	HandedOverByID *uint               `form:"handed_over_by_id" json:"handed_over_by_id"`
+	HandedOverAt   *time.Time          `form:"handed_over_at" json:"handed_over_at"`
+	ReturnedByID   *uint               `form:"returned_by_id" json:"returned_by_id"`
+	ReturnedAt     *time.Time          `form:"returned_at" json:"returned_at"`
+	Items          []EquipmentLoanItem `gorm:"foreignKey:EquipmentLoanID"`
+	Units          []EquipmentLoanUnit `gorm:"foreignKey:EquipmentLoanID"`
+}
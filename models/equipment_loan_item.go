@@ -0,0 +1,11 @@
+package models
+
+import "gorm.io/gorm"
+
+type EquipmentLoanItem struct {
+	gorm.Model
+	EquipmentLoanID uint          `form:"equipment_loan_id" json:"equipment_loan_id"`
+	EquipmentTypeID uint          `form:"equipment_type_id" json:"equipment_type_id"`
+	EquipmentType   EquipmentType `gorm:"foreignKey:EquipmentTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Quantity        int           `form:"quantity" json:"quantity"`
+}
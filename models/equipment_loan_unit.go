@@ -0,0 +1,11 @@
+package models
+
+import "gorm.io/gorm"
+
+// EquipmentLoanUnit mencatat unit ber-nomor seri yang diserahkan pada sebuah peminjaman alat
+type EquipmentLoanUnit struct {
+	gorm.Model
+	EquipmentLoanID uint          `form:"equipment_loan_id" json:"equipment_loan_id"`
+	EquipmentUnitID uint          `form:"equipment_unit_id" json:"equipment_unit_id"`
+	EquipmentUnit   EquipmentUnit `gorm:"foreignKey:EquipmentUnitID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
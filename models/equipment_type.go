@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+// EquipmentType adalah jenis alat yang bisa dipinjam, misalnya osiloskop atau multimeter.
+// Stok dihitung dari unit ber-nomor seri milik setiap lab.
+type EquipmentType struct {
+	gorm.Model
+	Name        string `gorm:"type:VARCHAR(100)" form:"name" json:"name"`
+	Category    string `gorm:"type:VARCHAR(100)" form:"category" json:"category"`
+	Description string `form:"description" json:"description"`
+	ImageUrl    string `form:"image_url" json:"image_url"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "gorm.io/gorm"
+
+// Status ketersediaan unit alat
+const (
+	EquipmentUnitStatusAvailable   = "available"
+	EquipmentUnitStatusLoaned      = "loaned"
+	EquipmentUnitStatusMaintenance = "maintenance"
+	EquipmentUnitStatusRetired     = "retired"
+)
+
+// Kondisi fisik unit alat
+const (
+	EquipmentConditionGood    = "good"
+	EquipmentConditionDamaged = "damaged"
+	EquipmentConditionMissing = "missing"
+)
+
+type EquipmentUnit struct {
+	gorm.Model
+	EquipmentTypeID uint          `form:"equipment_type_id" json:"equipment_type_id"`
+	EquipmentType   EquipmentType `gorm:"foreignKey:EquipmentTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	LabID           uint          `form:"lab_id" json:"lab_id"`
+	Lab             Lab           `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SerialNumber    string        `gorm:"type:VARCHAR(100);index" form:"serial_number" json:"serial_number"`
+	Condition       string        `gorm:"type:ENUM('good', 'damaged', 'missing');default:'good'" form:"condition" json:"condition"`
+	Status          string        `gorm:"type:ENUM('available', 'loaned', 'maintenance', 'retired');default:'available'" form:"status" json:"status"`
+	Notes           string        `form:"notes" json:"notes"`
+}
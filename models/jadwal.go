@@ -6,12 +6,30 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	JadwalStatusNotUsed   = "notused"
+	JadwalStatusInUsed    = "inused"
+	JadwalStatusFinished  = "finished"
+	JadwalStatusCancelled = "cancelled"
+)
+
 type Jadwal struct {
 	gorm.Model
   	TanggalJadwal          *time.Time `gorm:"type:DATE"`
-	WaktuJadwal            string    `gorm:"type:ENUM('09:00', '12:00', '15:00')"`
+	WaktuJadwal            string    `gorm:"type:VARCHAR(5)"`
+	WaktuSelesai           string    `gorm:"type:VARCHAR(5)"`
+	LabSlotID              *uint     `form:"lab_slot_id" json:"lab_slot_id"`
+	LabSlot                *LabSlot  `gorm:"foreignKey:LabSlotID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	LabID                  *uint     `form:"lab_id" json:"lab_id"`
+	Lab                    *Lab      `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	UserID                 *uint     `form:"user_id" json:"user_id"`
+	User                   *User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	PeminjamanID           *uint     `gorm:"index" form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman             *Peminjaman `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ActualStartAt          *time.Time `form:"actual_start_at" json:"actual_start_at"`
+	ActualEndAt            *time.Time `form:"actual_end_at" json:"actual_end_at"`
 	NameUser               string    `form:"name_user" json:"name_user"`
   	NameLaboratorium       string    `form:"name_lab" json:"name_lab"`
-	Status                 string    `gorm:"type:ENUM('notused', 'inused', 'finished')"`
+	Status                 string    `gorm:"type:ENUM('notused', 'inused', 'finished', 'cancelled')"`
 	
 }
\ No newline at end of file
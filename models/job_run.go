@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	JobRunStatusSuccess = "success"
+	JobRunStatusFailed  = "failed"
+)
+
+// JobRun mencatat setiap eksekusi job scheduler beserta jumlah data yang diproses
+type JobRun struct {
+	gorm.Model
+	JobName    string    `form:"job_name" json:"job_name"`
+	StartedAt  time.Time `form:"started_at" json:"started_at"`
+	FinishedAt time.Time `form:"finished_at" json:"finished_at"`
+	Status     string    `gorm:"type:ENUM('success', 'failed')"`
+	Affected   int       `form:"affected" json:"affected"`
+	Message    string    `form:"message" json:"message"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// LabApprovalStep adalah satu tahap persetujuan peminjaman pada lab. Tahap dijalankan berurutan
+// sesuai StepOrder, approver bisa berupa role (ApproverRole) atau user tertentu (ApproverUserID).
+type LabApprovalStep struct {
+	gorm.Model
+	LabID          uint   `form:"lab_id" json:"lab_id"`
+	Lab            Lab    `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StepOrder      int    `form:"step_order" json:"step_order"`
+	Name           string `form:"name" json:"name"`
+	ApproverRole   string `gorm:"type:ENUM('admin', 'dosen', 'kepala_lab')"`
+	ApproverUserID *uint  `form:"approver_user_id" json:"approver_user_id"`
+	ApproverUser   *User  `gorm:"foreignKey:ApproverUserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
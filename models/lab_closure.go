@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	LabClosureSourceManual = "manual"
+	LabClosureSourceICS    = "ics"
+)
+
+// LabClosure adalah tanggal atau rentang tanggal ketika lab tidak bisa dipinjam, misalnya libur
+// nasional, minggu ujian, atau perawatan. LabID kosong berarti berlaku untuk semua lab. Jika
+// JamMulai dan JamSelesai diisi, penutupan hanya berlaku pada jam tersebut di setiap tanggalnya.
+type LabClosure struct {
+	gorm.Model
+	LabID          *uint      `gorm:"index" form:"lab_id" json:"lab_id"`
+	Lab            *Lab       `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TanggalMulai   *time.Time `gorm:"type:DATE;index" form:"tanggal_mulai" json:"tanggal_mulai"`
+	TanggalSelesai *time.Time `gorm:"type:DATE;index" form:"tanggal_selesai" json:"tanggal_selesai"`
+	JamMulai       string     `gorm:"type:VARCHAR(5)" form:"jam_mulai" json:"jam_mulai"`
+	JamSelesai     string     `gorm:"type:VARCHAR(5)" form:"jam_selesai" json:"jam_selesai"`
+	Reason         string     `form:"reason" json:"reason"`
+	Source         string     `gorm:"type:ENUM('manual', 'ics');default:'manual'" form:"source" json:"source"`
+	ExternalUID    string     `gorm:"type:VARCHAR(255);index" form:"external_uid" json:"external_uid"`
+}
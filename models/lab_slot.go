@@ -0,0 +1,38 @@
+package models
+
+import "gorm.io/gorm"
+
+// defaultLabSlotTimes adalah jam bawaan yang sebelumnya di-hardcode sebagai ENUM
+var defaultLabSlotTimes = [][2]string{
+	{"09:00", "12:00"},
+	{"12:00", "15:00"},
+	{"15:00", "18:00"},
+}
+
+type LabSlot struct {
+	gorm.Model
+	LabID      uint   `form:"lab_id" json:"lab_id"`
+	Lab        Lab    `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Weekday    int    `form:"weekday" json:"weekday"` // 0 = Minggu sampai 6 = Sabtu, mengikuti time.Weekday
+	JamMulai   string `gorm:"type:VARCHAR(5)" form:"jam_mulai" json:"jam_mulai"`
+	JamSelesai string `gorm:"type:VARCHAR(5)" form:"jam_selesai" json:"jam_selesai"`
+	IsActive   bool   `gorm:"default:true" form:"is_active" json:"is_active"`
+}
+
+// DefaultLabSlots membuat slot bawaan untuk setiap hari pada sebuah lab,
+// dipakai untuk lab baru dan migrasi lab lama yang belum memiliki konfigurasi slot
+func DefaultLabSlots(labID uint) []LabSlot {
+	var labSlots []LabSlot
+	for weekday := 0; weekday < 7; weekday++ {
+		for _, slotTime := range defaultLabSlotTimes {
+			labSlots = append(labSlots, LabSlot{
+				LabID:      labID,
+				Weekday:    weekday,
+				JamMulai:   slotTime[0],
+				JamSelesai: slotTime[1],
+				IsActive:   true,
+			})
+		}
+	}
+	return labSlots
+}
@@ -6,5 +6,6 @@ type Lab struct {
 	gorm.Model
 	Name        string `form:"name" json:"name"`
 	Description string `form:"description" json:"description"`
-	
+	// Capacity adalah jumlah maksimal peserta dalam satu peminjaman, 0 berarti tidak dibatasi
+	Capacity    int    `gorm:"default:0" form:"capacity" json:"capacity"`
 }
\ No newline at end of file
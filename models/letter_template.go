@@ -0,0 +1,19 @@
+package models
+
+import "gorm.io/gorm"
+
+// LetterTemplate adalah template surat peminjaman yang dikelola admin. Body boleh berisi placeholder
+// seperti {{nama}}, {{nim}}, {{lab}}, {{tanggal}}, {{jam_mulai}}, {{jam_selesai}}, {{approver}},
+// {{nomor}}, dan {{keperluan}}. Hanya satu template yang aktif dalam satu waktu.
+type LetterTemplate struct {
+	gorm.Model
+	Name               string `form:"name" json:"name"`
+	InstitutionName    string `form:"institution_name" json:"institution_name"`
+	InstitutionAddress string `form:"institution_address" json:"institution_address"`
+	Title              string `form:"title" json:"title"`
+	Body               string `gorm:"type:TEXT" form:"body" json:"body"`
+	Closing            string `gorm:"type:TEXT" form:"closing" json:"closing"`
+	SignatoryName      string `form:"signatory_name" json:"signatory_name"`
+	SignatoryTitle     string `form:"signatory_title" json:"signatory_title"`
+	IsActive           bool   `gorm:"default:false" form:"is_active" json:"is_active"`
+}
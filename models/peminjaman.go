@@ -6,6 +6,18 @@ import (
 	"gorm.io/gorm"
 )
 
+// Status peminjaman, perpindahan antar status diatur oleh usecase peminjaman
+const (
+	PeminjamanStatusRequest   = "request"
+	PeminjamanStatusAccept    = "accept"
+	PeminjamanStatusReject    = "reject"
+	PeminjamanStatusCancelled = "cancelled"
+	PeminjamanStatusInUse     = "in_use"
+	PeminjamanStatusFinished  = "finished"
+	PeminjamanStatusNoShow    = "no_show"
+	PeminjamanStatusExpired   = "expired"
+)
+
 type Peminjaman struct {
 	gorm.Model
 	UserID         	       uint      `form:"user_id" json:"user_id"`
@@ -13,8 +25,22 @@ type Peminjaman struct {
 	LabID          		   uint      `form:"lab_id" json:"lab_id"`
 	Lab            		   Lab       `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	TanggalPeminjaman      *time.Time `gorm:"type:DATE"`
-	JamPeminjaman          string    `gorm:"type:ENUM('09:00', '12:00', '15:00')"`
+	JamPeminjaman          string    `gorm:"type:VARCHAR(5)"`
+	JamSelesai             string    `gorm:"type:VARCHAR(5)"`
+	LabSlotID              *uint     `form:"lab_slot_id" json:"lab_slot_id"`
+	LabSlot                *LabSlot  `gorm:"foreignKey:LabSlotID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	Description    		   string    `form:"description" json:"description"`
-	Status         		   string    `gorm:"type:ENUM('request', 'accept', 'reject')"`
+	PeminjamanSeriesID     *uint     `form:"peminjaman_series_id" json:"peminjaman_series_id"`
+	PeminjamanSeries       *PeminjamanSeries `gorm:"foreignKey:PeminjamanSeriesID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Status         		   string    `gorm:"type:ENUM('request', 'accept', 'reject', 'cancelled', 'in_use', 'finished', 'no_show', 'expired')"`
+	CancelReason           string     `form:"cancel_reason" json:"cancel_reason"`
+	CancelledAt            *time.Time `form:"cancelled_at" json:"cancelled_at"`
+	LateCancellation       bool       `gorm:"default:false" form:"late_cancellation" json:"late_cancellation"`
+	ActualStartAt          *time.Time `form:"actual_start_at" json:"actual_start_at"`
+	ActualEndAt            *time.Time `form:"actual_end_at" json:"actual_end_at"`
+	CheckedInByID          *uint      `form:"checked_in_by_id" json:"checked_in_by_id"`
+	CheckedOutByID         *uint      `form:"checked_out_by_id" json:"checked_out_by_id"`
+	Approvals              []PeminjamanApproval `gorm:"foreignKey:PeminjamanID"`
+	Participants           []PeminjamanParticipant `gorm:"foreignKey:PeminjamanID"`
 }
 
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+)
+
+// PeminjamanApproval adalah salinan tahap persetujuan lab saat peminjaman dibuat,
+// sehingga perubahan konfigurasi lab tidak mengubah peminjaman yang sedang berjalan.
+// Tahap dengan PeminjamanRescheduleID terisi milik rantai persetujuan usulan jadwal ulang.
+type PeminjamanApproval struct {
+	gorm.Model
+	PeminjamanID           uint                  `form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman             Peminjaman            `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	PeminjamanRescheduleID *uint                 `gorm:"index" form:"peminjaman_reschedule_id" json:"peminjaman_reschedule_id"`
+	PeminjamanReschedule   *PeminjamanReschedule `gorm:"foreignKey:PeminjamanRescheduleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StepOrder              int                   `form:"step_order" json:"step_order"`
+	Name                   string                `form:"name" json:"name"`
+	ApproverRole           string                `gorm:"type:ENUM('admin', 'dosen', 'kepala_lab')"`
+	ApproverUserID         *uint                 `form:"approver_user_id" json:"approver_user_id"`
+	Status                 string                `gorm:"type:ENUM('pending', 'approved', 'rejected');default:'pending'"`
+	DecidedByID            *uint                 `form:"decided_by_id" json:"decided_by_id"`
+	Comment                string                `form:"comment" json:"comment"`
+	DecidedAt              *time.Time            `form:"decided_at" json:"decided_at"`
+}
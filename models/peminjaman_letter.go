@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PeminjamanLetter mencatat surat peminjaman yang pernah diterbitkan. Code dicetak sebagai QR
+// dan dipakai endpoint publik untuk memastikan surat asli.
+type PeminjamanLetter struct {
+	gorm.Model
+	PeminjamanID     uint            `gorm:"uniqueIndex" form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman       Peminjaman      `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Code             string          `gorm:"type:VARCHAR(32);uniqueIndex" form:"code" json:"code"`
+	Number           string          `form:"number" json:"number"`
+	LetterTemplateID *uint           `form:"letter_template_id" json:"letter_template_id"`
+	LetterTemplate   *LetterTemplate `gorm:"foreignKey:LetterTemplateID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	IssuedAt         *time.Time      `form:"issued_at" json:"issued_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// PeminjamanParticipant adalah peserta peminjaman kelompok. Peserta terdaftar dicocokkan lewat NIM
+// sehingga UserID terisi, sedangkan tamu hanya dicatat namanya.
+type PeminjamanParticipant struct {
+	gorm.Model
+	PeminjamanID uint       `gorm:"index" form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman   Peminjaman `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID       *uint      `gorm:"index" form:"user_id" json:"user_id"`
+	User         *User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	NIMNIP       string     `form:"nim_nip" json:"nim_nip"`
+	Name         string     `form:"name" json:"name"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RescheduleStatusPending         = "pending"
+	RescheduleStatusWaitingApproval = "waiting_approval"
+	RescheduleStatusApproved        = "approved"
+	RescheduleStatusRejected        = "rejected"
+)
+
+// PeminjamanReschedule adalah usulan perubahan tanggal/slot dari peminjam. Tanggal dan jam lama
+// disalin agar riwayat usulan tetap utuh setelah peminjaman dipindahkan. Usulan untuk lab dengan
+// rantai persetujuan berstatus waiting_approval sampai tahap terakhir rantainya disetujui.
+type PeminjamanReschedule struct {
+	gorm.Model
+	PeminjamanID      uint       `gorm:"index" form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman        Peminjaman `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	RequestedByID     uint       `form:"requested_by_id" json:"requested_by_id"`
+	FromTanggal       *time.Time `gorm:"type:DATE"`
+	FromJamPeminjaman string     `gorm:"type:VARCHAR(5)"`
+	FromJamSelesai    string     `gorm:"type:VARCHAR(5)"`
+	FromLabSlotID     *uint      `form:"from_lab_slot_id" json:"from_lab_slot_id"`
+	TanggalPeminjaman *time.Time `gorm:"type:DATE"`
+	JamPeminjaman     string     `gorm:"type:VARCHAR(5)"`
+	JamSelesai        string     `gorm:"type:VARCHAR(5)"`
+	LabSlotID         *uint      `form:"lab_slot_id" json:"lab_slot_id"`
+	Reason            string     `form:"reason" json:"reason"`
+	Status            string     `gorm:"type:ENUM('pending', 'waiting_approval', 'approved', 'rejected');default:'pending'"`
+	DecidedByID       *uint      `form:"decided_by_id" json:"decided_by_id"`
+	DecisionNote      string     `form:"decision_note" json:"decision_note"`
+	DecidedAt         *time.Time `form:"decided_at" json:"decided_at"`
+}
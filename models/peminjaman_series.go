@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PeminjamanSeries struct {
+	gorm.Model
+	UserID        uint       `form:"user_id" json:"user_id"`
+	User          User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	LabID         uint       `form:"lab_id" json:"lab_id"`
+	Lab           Lab        `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Frequency     string     `gorm:"type:ENUM('weekly', 'biweekly')"`
+	TanggalMulai  *time.Time `gorm:"type:DATE"`
+	Until         *time.Time `gorm:"type:DATE"`
+	Count         int
+	JamPeminjaman string `gorm:"type:VARCHAR(5)"`
+	JamSelesai    string `gorm:"type:VARCHAR(5)"`
+	Description   string `form:"description" json:"description"`
+}
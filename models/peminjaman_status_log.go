@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+type PeminjamanStatusLog struct {
+	gorm.Model
+	PeminjamanID uint       `form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman   Peminjaman `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ActorID      *uint      `form:"actor_id" json:"actor_id"`
+	ActorRole    string     `gorm:"type:ENUM('user', 'admin', 'approver', 'system')"`
+	FromStatus   string     `form:"from_status" json:"from_status"`
+	ToStatus     string     `form:"to_status" json:"to_status"`
+	Reason       string     `form:"reason" json:"reason"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusPromoted  = "promoted"
+	WaitlistStatusCancelled = "cancelled"
+	WaitlistStatusExpired   = "expired"
+)
+
+// PeminjamanWaitlist adalah antrean user untuk slot lab yang sedang dipakai orang lain.
+// Saat slot terbebas, antrean paling awal dipromosikan menjadi peminjaman berstatus request.
+type PeminjamanWaitlist struct {
+	gorm.Model
+	UserID            uint        `form:"user_id" json:"user_id"`
+	User              User        `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	LabID             uint        `form:"lab_id" json:"lab_id"`
+	Lab               Lab         `gorm:"foreignKey:LabID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TanggalPeminjaman *time.Time  `gorm:"type:DATE"`
+	JamPeminjaman     string      `gorm:"type:VARCHAR(5)"`
+	JamSelesai        string      `gorm:"type:VARCHAR(5)"`
+	LabSlotID         *uint       `form:"lab_slot_id" json:"lab_slot_id"`
+	LabSlot           *LabSlot    `gorm:"foreignKey:LabSlotID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Description       string      `form:"description" json:"description"`
+	Status            string      `gorm:"type:ENUM('waiting', 'promoted', 'cancelled', 'expired');default:'waiting'"`
+	PeminjamanID      *uint       `form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman        *Peminjaman `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	PromotedAt        *time.Time  `form:"promoted_at" json:"promoted_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sumber dan status poin penalti
+const (
+	PenaltySourceNoShow     = "no_show"
+	PenaltySourceLateReturn = "late_return"
+	PenaltySourceDamage     = "damage"
+	PenaltySourceManual     = "manual"
+
+	PenaltyStatusActive   = "active"
+	PenaltyStatusAppealed = "appealed"
+	PenaltyStatusWaived   = "waived"
+)
+
+// Penalty adalah satu baris ledger poin penalti user. Poin berstatus active atau appealed yang
+// belum kedaluwarsa dijumlahkan untuk menentukan apakah user ditangguhkan dari peminjaman.
+type Penalty struct {
+	gorm.Model
+	UserID          uint       `gorm:"index" form:"user_id" json:"user_id"`
+	User            User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Source          string     `gorm:"type:ENUM('no_show', 'late_return', 'damage', 'manual')" form:"source" json:"source"`
+	Points          int        `form:"points" json:"points"`
+	Reason          string     `form:"reason" json:"reason"`
+	PeminjamanID    *uint      `form:"peminjaman_id" json:"peminjaman_id"`
+	EquipmentLoanID *uint      `form:"equipment_loan_id" json:"equipment_loan_id"`
+	DamageCaseID    *uint      `form:"damage_case_id" json:"damage_case_id"`
+	Status          string     `gorm:"type:ENUM('active', 'appealed', 'waived');default:'active'" form:"status" json:"status"`
+	ExpiresAt       *time.Time `form:"expires_at" json:"expires_at"`
+	IssuedByID      *uint      `form:"issued_by_id" json:"issued_by_id"`
+	AppealReason    string     `form:"appeal_reason" json:"appeal_reason"`
+	AppealedAt      *time.Time `form:"appealed_at" json:"appealed_at"`
+	ResolutionNote  string     `form:"resolution_note" json:"resolution_note"`
+	ResolvedByID    *uint      `form:"resolved_by_id" json:"resolved_by_id"`
+	ResolvedAt      *time.Time `form:"resolved_at" json:"resolved_at"`
+}
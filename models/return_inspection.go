@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReturnInspection adalah hasil pemeriksaan kondisi saat ruangan atau alat dikembalikan.
+// Salah satu dari PeminjamanID atau EquipmentLoanID terisi sesuai jenis peminjamannya.
+type ReturnInspection struct {
+	gorm.Model
+	PeminjamanID    *uint                  `form:"peminjaman_id" json:"peminjaman_id"`
+	Peminjaman      *Peminjaman            `gorm:"foreignKey:PeminjamanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	EquipmentLoanID *uint                  `form:"equipment_loan_id" json:"equipment_loan_id"`
+	EquipmentLoan   *EquipmentLoan         `gorm:"foreignKey:EquipmentLoanID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	InspectorID     uint                   `form:"inspector_id" json:"inspector_id"`
+	Condition       string                 `gorm:"type:ENUM('good', 'damaged', 'missing')" form:"condition" json:"condition"`
+	Notes           string                 `form:"notes" json:"notes"`
+	Items           []ReturnInspectionItem `gorm:"foreignKey:ReturnInspectionID"`
+	InspectedAt     time.Time              `form:"inspected_at" json:"inspected_at"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "gorm.io/gorm"
+
+type ReturnInspectionImage struct {
+	gorm.Model
+	ReturnInspectionID       uint             `form:"return_inspection_id" json:"return_inspection_id"`
+	ReturnInspection         ReturnInspection `gorm:"foreignKey:ReturnInspectionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ReturnInspectionImageUrl string           `form:"return_inspection_image_url" json:"return_inspection_image_url"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+// ReturnInspectionItem mencatat kondisi setiap unit alat pada pemeriksaan pengembalian
+type ReturnInspectionItem struct {
+	gorm.Model
+	ReturnInspectionID uint          `form:"return_inspection_id" json:"return_inspection_id"`
+	EquipmentUnitID    uint          `form:"equipment_unit_id" json:"equipment_unit_id"`
+	EquipmentUnit      EquipmentUnit `gorm:"foreignKey:EquipmentUnitID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Condition          string        `gorm:"type:ENUM('good', 'damaged', 'missing')" form:"condition" json:"condition"`
+	Notes              string        `form:"notes" json:"notes"`
+}
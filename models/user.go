@@ -2,6 +2,15 @@ package models
 
 import "gorm.io/gorm"
 
+// Role user, dosen dan kepala_lab tetap bisa meminjam lab seperti user biasa
+// dan sekaligus bisa menjadi approver peminjaman
+const (
+	UserRoleUser      = "user"
+	UserRoleAdmin     = "admin"
+	UserRoleDosen     = "dosen"
+	UserRoleKepalaLab = "kepala_lab"
+)
+
 type User struct {
 	gorm.Model
 	FullName       string
@@ -10,7 +19,7 @@ type User struct {
 	NIMNIP         string
 	KartuIdentitas string
 	ProfilePicture string
-	Role           string `gorm:"type:ENUM('user','admin')"`
+	Role           string `gorm:"type:ENUM('user','admin','dosen','kepala_lab')"`
 }
 
 
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type ApprovalRepository interface {
+	GetLabApprovalSteps(labID uint) ([]models.LabApprovalStep, error)
+	ReplaceLabApprovalSteps(labID uint, steps []models.LabApprovalStep) ([]models.LabApprovalStep, error)
+	GetPeminjamanApprovals(peminjamanID uint) ([]models.PeminjamanApproval, error)
+	GetRescheduleApprovals(rescheduleID uint) ([]models.PeminjamanApproval, error)
+	GetPendingApprovalsForApprover(userID uint, role string, page, limit int) ([]models.PeminjamanApproval, int, error)
+	DecidePeminjamanApproval(approval models.PeminjamanApproval) (models.PeminjamanApproval, error)
+}
+
+type approvalRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalRepository(db *gorm.DB) ApprovalRepository {
+	return &approvalRepository{db}
+}
+
+func (r *approvalRepository) GetLabApprovalSteps(labID uint) ([]models.LabApprovalStep, error) {
+	var steps []models.LabApprovalStep
+	err := r.db.Where("lab_id = ?", labID).Order("step_order ASC").Find(&steps).Error
+	return steps, err
+}
+
+// ReplaceLabApprovalSteps mengganti seluruh rantai persetujuan lab dalam satu transaksi
+func (r *approvalRepository) ReplaceLabApprovalSteps(labID uint, steps []models.LabApprovalStep) ([]models.LabApprovalStep, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("lab_id = ?", labID).Delete(&models.LabApprovalStep{}).Error; err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return nil
+		}
+		return tx.Create(&steps).Error
+	})
+	return steps, err
+}
+
+// GetPeminjamanApprovals mengambil rantai persetujuan peminjaman, tanpa rantai milik usulan jadwal ulang
+func (r *approvalRepository) GetPeminjamanApprovals(peminjamanID uint) ([]models.PeminjamanApproval, error) {
+	var approvals []models.PeminjamanApproval
+	err := r.db.Where("peminjaman_id = ? AND peminjaman_reschedule_id IS NULL", peminjamanID).Order("step_order ASC").Find(&approvals).Error
+	return approvals, err
+}
+
+func (r *approvalRepository) GetRescheduleApprovals(rescheduleID uint) ([]models.PeminjamanApproval, error) {
+	var approvals []models.PeminjamanApproval
+	err := r.db.Where("peminjaman_reschedule_id = ?", rescheduleID).Order("step_order ASC").Find(&approvals).Error
+	return approvals, err
+}
+
+// GetPendingApprovalsForApprover mengambil tahap yang sedang menunggu keputusan approver, yaitu tahap pending
+// yang semua tahap sebelumnya pada rantai yang sama sudah disetujui. Rantai peminjaman hanya diambil selama
+// peminjamannya berstatus request, rantai jadwal ulang selama usulannya berstatus waiting_approval.
+func (r *approvalRepository) GetPendingApprovalsForApprover(userID uint, role string, page, limit int) ([]models.PeminjamanApproval, int, error) {
+	var (
+		approvals []models.PeminjamanApproval
+		count     int64
+	)
+
+	query := r.db.Model(&models.PeminjamanApproval{}).
+		Joins("JOIN peminjamen ON peminjamen.id = peminjaman_approvals.peminjaman_id AND peminjamen.deleted_at IS NULL").
+		Joins("LEFT JOIN peminjaman_reschedules ON peminjaman_reschedules.id = peminjaman_approvals.peminjaman_reschedule_id").
+		Where("peminjaman_approvals.status = ?", models.ApprovalStatusPending).
+		Where("(peminjaman_approvals.peminjaman_reschedule_id IS NULL AND peminjamen.status = ?) OR peminjaman_reschedules.status = ?", models.PeminjamanStatusRequest, models.RescheduleStatusWaitingApproval).
+		Where("peminjaman_approvals.approver_user_id = ? OR (peminjaman_approvals.approver_user_id IS NULL AND peminjaman_approvals.approver_role = ?)", userID, role).
+		Where("NOT EXISTS (SELECT 1 FROM peminjaman_approvals prev WHERE prev.peminjaman_id = peminjaman_approvals.peminjaman_id " +
+			"AND COALESCE(prev.peminjaman_reschedule_id, 0) = COALESCE(peminjaman_approvals.peminjaman_reschedule_id, 0) " +
+			"AND prev.step_order < peminjaman_approvals.step_order AND prev.status <> 'approved' AND prev.deleted_at IS NULL)").
+		Session(&gorm.Session{})
+
+	if err := query.Count(&count).Error; err != nil {
+		return approvals, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Peminjaman").Preload("PeminjamanReschedule").Order("peminjamen.tanggal_peminjaman ASC, peminjamen.jam_peminjaman ASC").
+		Limit(limit).Offset(offset).Find(&approvals).Error
+
+	return approvals, int(count), err
+}
+
+// DecidePeminjamanApproval menyimpan keputusan hanya jika tahap masih pending,
+// sehingga satu tahap tidak bisa diputuskan dua kali
+func (r *approvalRepository) DecidePeminjamanApproval(approval models.PeminjamanApproval) (models.PeminjamanApproval, error) {
+	err := decidePeminjamanApproval(r.db, approval)
+	return approval, err
+}
+
+func decidePeminjamanApproval(tx *gorm.DB, approval models.PeminjamanApproval) error {
+	result := tx.Model(&models.PeminjamanApproval{}).
+		Where("id = ? AND status = ?", approval.ID, models.ApprovalStatusPending).
+		Updates(map[string]interface{}{
+			"status":        approval.Status,
+			"decided_by_id": approval.DecidedByID,
+			"comment":       approval.Comment,
+			"decided_at":    approval.DecidedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 type BeritaAcaraImageRepository interface {
 	GetAllBeritaAcaraImages(page, limit int) ([]models.BeritaAcaraImage, int, error)
 	GetAllBeritaAcaraImageByID(id uint) ([]models.BeritaAcaraImage, error)
+	GetBeritaAcaraImagesByJadwalIDs(jadwalIDs []uint) ([]models.BeritaAcaraImage, error)
 	GetBeritaAcaraImageByID(id uint) (models.BeritaAcaraImage, error)
 	CreateBeritaAcaraImage(beritaacaraImage models.BeritaAcaraImage) (models.BeritaAcaraImage, error)
 	UpdateBeritaAcaraImage(beritaacaraImage models.BeritaAcaraImage) (models.BeritaAcaraImage, error)
@@ -69,3 +70,13 @@ func (r *beritaAcaraImageRepository) DeleteBeritaAcaraImage(id uint) error {
 	err := r.db.Unscoped().Where("jadwal_id = ?", id).Delete(&beritaAcaraImage).Error
 	return err
 }
+
+// GetBeritaAcaraImagesByJadwalIDs mengambil berita acara beberapa jadwal dalam satu query
+func (r *beritaAcaraImageRepository) GetBeritaAcaraImagesByJadwalIDs(jadwalIDs []uint) ([]models.BeritaAcaraImage, error) {
+	var beritaAcaraImages []models.BeritaAcaraImage
+	if len(jadwalIDs) == 0 {
+		return beritaAcaraImages, nil
+	}
+	err := r.db.Where("jadwal_id IN ?", jadwalIDs).Order("id ASC").Find(&beritaAcaraImages).Error
+	return beritaAcaraImages, err
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type BookingQuotaRepository interface {
+	GetBookingQuotas() ([]models.BookingQuota, error)
+	GetBookingQuotasForRole(role string, labID uint) ([]models.BookingQuota, error)
+	GetBookingQuotaByID(id uint) (models.BookingQuota, error)
+	CreateBookingQuota(bookingQuota models.BookingQuota) (models.BookingQuota, error)
+	UpdateBookingQuota(bookingQuota models.BookingQuota) (models.BookingQuota, error)
+	DeleteBookingQuota(id uint) error
+	CountActiveRequests(userID, labID uint) (int, error)
+	GetBookedPeminjamans(userID, labID uint, from, to time.Time) ([]models.Peminjaman, error)
+}
+
+// QuotaCheck memeriksa kuota user memakai bookingQuotaRepo yang terikat ke transaksi pemanggil, sehingga
+// hitungan kuota dibaca lewat koneksi yang sama dengan kunci baris user dan lab
+type QuotaCheck func(bookingQuotaRepo BookingQuotaRepository) error
+
+type bookingQuotaRepository struct {
+	db *gorm.DB
+}
+
+func NewBookingQuotaRepository(db *gorm.DB) BookingQuotaRepository {
+	return &bookingQuotaRepository{db}
+}
+
+func (r *bookingQuotaRepository) GetBookingQuotas() ([]models.BookingQuota, error) {
+	var bookingQuotas []models.BookingQuota
+	err := r.db.Preload("Lab").Order("id ASC").Find(&bookingQuotas).Error
+	return bookingQuotas, err
+}
+
+// GetBookingQuotasForRole mengambil kuota yang berlaku untuk role tersebut, baik kuota umum maupun
+// kuota lab. labID 0 berarti kuota semua lab ikut diambil.
+func (r *bookingQuotaRepository) GetBookingQuotasForRole(role string, labID uint) ([]models.BookingQuota, error) {
+	var bookingQuotas []models.BookingQuota
+	query := r.db.Preload("Lab").Where("role = ? OR role = ''", role)
+	if labID != 0 {
+		query = query.Where("lab_id IS NULL OR lab_id = ?", labID)
+	}
+	err := query.Order("id ASC").Find(&bookingQuotas).Error
+	return bookingQuotas, err
+}
+
+func (r *bookingQuotaRepository) GetBookingQuotaByID(id uint) (models.BookingQuota, error) {
+	var bookingQuota models.BookingQuota
+	err := r.db.Preload("Lab").Where("id = ?", id).First(&bookingQuota).Error
+	return bookingQuota, err
+}
+
+func (r *bookingQuotaRepository) CreateBookingQuota(bookingQuota models.BookingQuota) (models.BookingQuota, error) {
+	err := r.db.Omit("Lab").Create(&bookingQuota).Error
+	return bookingQuota, err
+}
+
+func (r *bookingQuotaRepository) UpdateBookingQuota(bookingQuota models.BookingQuota) (models.BookingQuota, error) {
+	err := r.db.Omit("Lab").Save(&bookingQuota).Error
+	return bookingQuota, err
+}
+
+func (r *bookingQuotaRepository) DeleteBookingQuota(id uint) error {
+	var bookingQuota models.BookingQuota
+	err := r.db.Where("id = ?", id).Delete(&bookingQuota).Error
+	return err
+}
+
+// CountActiveRequests menghitung peminjaman user yang masih menunggu keputusan, labID 0 untuk semua lab
+func (r *bookingQuotaRepository) CountActiveRequests(userID, labID uint) (int, error) {
+	var count int64
+	query := r.db.Model(&models.Peminjaman{}).Where("user_id = ? AND status = ?", userID, models.PeminjamanStatusRequest)
+	if labID != 0 {
+		query = query.Where("lab_id = ?", labID)
+	}
+	err := query.Count(&count).Error
+	return int(count), err
+}
+
+// GetBookedPeminjamans mengambil peminjaman user yang memakai kuota pada rentang tanggal, labID 0 untuk semua lab
+func (r *bookingQuotaRepository) GetBookedPeminjamans(userID, labID uint, from, to time.Time) ([]models.Peminjaman, error) {
+	var peminjamans []models.Peminjaman
+	query := r.db.Where("user_id = ? AND tanggal_peminjaman BETWEEN ? AND ?", userID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("status IN ?", []string{models.PeminjamanStatusRequest, models.PeminjamanStatusAccept, models.PeminjamanStatusInUse, models.PeminjamanStatusFinished})
+	if labID != 0 {
+		query = query.Where("lab_id = ?", labID)
+	}
+	err := query.Find(&peminjamans).Error
+	return peminjamans, err
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type CalendarFeedRepository interface {
+	GetCalendarFeedByUserID(userID uint) (models.CalendarFeed, error)
+	GetCalendarFeedByToken(token string) (models.CalendarFeed, error)
+	CreateCalendarFeed(calendarFeed models.CalendarFeed) (models.CalendarFeed, error)
+	UpdateCalendarFeed(calendarFeed models.CalendarFeed) (models.CalendarFeed, error)
+}
+
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db}
+}
+
+func (r *calendarFeedRepository) GetCalendarFeedByUserID(userID uint) (models.CalendarFeed, error) {
+	var calendarFeed models.CalendarFeed
+	err := r.db.Where("user_id = ?", userID).First(&calendarFeed).Error
+	return calendarFeed, err
+}
+
+func (r *calendarFeedRepository) GetCalendarFeedByToken(token string) (models.CalendarFeed, error) {
+	var calendarFeed models.CalendarFeed
+	err := r.db.Where("token = ?", token).First(&calendarFeed).Error
+	return calendarFeed, err
+}
+
+func (r *calendarFeedRepository) CreateCalendarFeed(calendarFeed models.CalendarFeed) (models.CalendarFeed, error) {
+	err := r.db.Create(&calendarFeed).Error
+	return calendarFeed, err
+}
+
+func (r *calendarFeedRepository) UpdateCalendarFeed(calendarFeed models.CalendarFeed) (models.CalendarFeed, error) {
+	err := r.db.Omit("User").Save(&calendarFeed).Error
+	return calendarFeed, err
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DamageCaseRepository interface {
+	GetDamageCases(page, limit int, userID uint, status string) ([]models.DamageCase, int, error)
+	GetDamageCasesByReturnInspectionID(returnInspectionID uint) ([]models.DamageCase, error)
+	GetDamageCaseByID(id uint) (models.DamageCase, error)
+	UpdateDamageCase(damageCase models.DamageCase) (models.DamageCase, error)
+	CountOpenDamageCasesByUserID(userID uint) (int, error)
+	GetDamageCaseSummary(from, to time.Time) ([]dtos.DamageCaseSummary, error)
+}
+
+type damageCaseRepository struct {
+	db *gorm.DB
+}
+
+func NewDamageCaseRepository(db *gorm.DB) DamageCaseRepository {
+	return &damageCaseRepository{db}
+}
+
+// GetDamageCases mengambil kasus kerusakan, userID 0 berarti semua user (admin)
+func (r *damageCaseRepository) GetDamageCases(page, limit int, userID uint, status string) ([]models.DamageCase, int, error) {
+	var (
+		damageCases []models.DamageCase
+		count       int64
+	)
+
+	query := r.db.Model(&models.DamageCase{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return damageCases, int(count), err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("EquipmentUnit.EquipmentType").Order("id DESC").Limit(limit).Offset(offset).Find(&damageCases).Error
+	return damageCases, int(count), err
+}
+
+func (r *damageCaseRepository) GetDamageCasesByReturnInspectionID(returnInspectionID uint) ([]models.DamageCase, error) {
+	var damageCases []models.DamageCase
+	err := r.db.Preload("EquipmentUnit.EquipmentType").Where("return_inspection_id = ?", returnInspectionID).Find(&damageCases).Error
+	return damageCases, err
+}
+
+func (r *damageCaseRepository) GetDamageCaseByID(id uint) (models.DamageCase, error) {
+	var damageCase models.DamageCase
+	err := r.db.Preload("EquipmentUnit.EquipmentType").Where("id = ?", id).First(&damageCase).Error
+	return damageCase, err
+}
+
+func (r *damageCaseRepository) UpdateDamageCase(damageCase models.DamageCase) (models.DamageCase, error) {
+	err := r.db.Omit("ReturnInspection", "User", "Lab", "EquipmentUnit").Save(&damageCase).Error
+	return damageCase, err
+}
+
+func (r *damageCaseRepository) CountOpenDamageCasesByUserID(userID uint) (int, error) {
+	var count int64
+	err := r.db.Model(&models.DamageCase{}).Where("user_id = ? AND status = ?", userID, models.DamageCaseStatusOpen).Count(&count).Error
+	return int(count), err
+}
+
+// GetDamageCaseSummary menghitung kasus kerusakan per lab, kondisi, dan status pada rentang tanggal dibuat
+func (r *damageCaseRepository) GetDamageCaseSummary(from, to time.Time) ([]dtos.DamageCaseSummary, error) {
+	var summaries []dtos.DamageCaseSummary
+	err := r.db.Model(&models.DamageCase{}).
+		Select("lab_id, `condition`, status, COUNT(*) AS total").
+		Where("DATE(created_at) BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("lab_id, `condition`, status").
+		Scan(&summaries).Error
+	return summaries, err
+}
@@ -10,7 +10,7 @@ import (
 
 type DashboardRepository interface {
 	DashboardGetAll() (int, int, int, int, int, []models.Peminjaman, []models.User, []models.Jadwal, int, int, []dtos.UserTeraktifMeminjam, error)
-	DashboardGetByMonth(month, year int) (int, int, int, int, int, int, int, int, int, int, error)
+	DashboardGetByMonth(month, year int) (int, int, int, int, []dtos.DashboardLabCount, []dtos.DashboardLabCount, error)
 	
 }
 
@@ -119,7 +119,7 @@ func (r *dashboardRepository) DashboardGetAll() (int, int, int, int, int, []mode
 
 
 
-func (r *dashboardRepository) DashboardGetByMonth(month, year int) (int, int, int, int, int, int, int, int, int, int, error) {
+func (r *dashboardRepository) DashboardGetByMonth(month, year int) (int, int, int, int, []dtos.DashboardLabCount, []dtos.DashboardLabCount, error) {
 	var countUser int64
 	var countLab int64
 	var countPeminjaman int64
@@ -128,67 +128,49 @@ func (r *dashboardRepository) DashboardGetByMonth(month, year int) (int, int, in
 	// Count total users
 	err := r.db.Model(&models.User{}).Where("role = 'user' AND EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", month, year).Count(&countUser).Error
 	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
+		return 0, 0, 0, 0, nil, nil, err
 	}
 
 	// Count total labs
 	err = r.db.Model(&models.Lab{}).Count(&countLab).Error
 	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
+		return 0, 0, 0, 0, nil, nil, err
 	}
 
 	// Count total peminjaman
 	err = r.db.Model(&models.Peminjaman{}).Where("EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", month, year).Count(&countPeminjaman).Error
 	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
+		return 0, 0, 0, 0, nil, nil, err
 	}
 
 	// Count total jadwal
 	err = r.db.Model(&models.Jadwal{}).Where("EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", month, year).Count(&countJadwal).Error
 	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
+		return 0, 0, 0, 0, nil, nil, err
 	}
 
-	// Count peminjaman per lab
-	var countPeminjamanLabElektronika int64
-	var countPeminjamanLabTransmisi int64
-	var countPeminjamanLabJaringan int64
-
-	err = r.db.Model(&models.Peminjaman{}).Where("lab_id = ? AND EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", 1, month, year).Count(&countPeminjamanLabElektronika).Error
-	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
-	}
-
-	err = r.db.Model(&models.Peminjaman{}).Where("lab_id = ? AND EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", 2, month, year).Count(&countPeminjamanLabTransmisi).Error
+	// Count peminjaman dan jadwal per lab, lab tanpa data tetap muncul dengan total 0
+	peminjamanPerLab, err := r.countPerLab("peminjamen", month, year)
 	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
+		return 0, 0, 0, 0, nil, nil, err
 	}
 
-	err = r.db.Model(&models.Peminjaman{}).Where("lab_id = ? AND EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", 3, month, year).Count(&countPeminjamanLabJaringan).Error
-	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
-	}
-
-	// Count jadwal per lab
-	var countJadwalLabElektronika int64
-	var countJadwalLabTransmisi int64
-	var countJadwalLabJaringan int64
-
-	err = r.db.Model(&models.Jadwal{}).Where("name_laboratorium = ? AND EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", "Laboratorium Elektronika", month, year).Count(&countJadwalLabElektronika).Error
+	jadwalPerLab, err := r.countPerLab("jadwals", month, year)
 	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
+		return 0, 0, 0, 0, nil, nil, err
 	}
 
-	err = r.db.Model(&models.Jadwal{}).Where("name_laboratorium = ? AND EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", "Laboratorium Transmisi", month, year).Count(&countJadwalLabTransmisi).Error
-	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
-	}
-
-	err = r.db.Model(&models.Jadwal{}).Where("name_laboratorium = ? AND EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(YEAR FROM created_at) = ?", "Laboratorium Jaringan", month, year).Count(&countJadwalLabJaringan).Error
-	if err != nil {
-		return 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, err
-	}
-
-	return int(countUser), int(countLab), int(countPeminjaman), int(countJadwal), int(countPeminjamanLabElektronika), int(countPeminjamanLabTransmisi), int(countPeminjamanLabJaringan), int(countJadwalLabElektronika), int(countJadwalLabTransmisi), int(countJadwalLabJaringan), nil
+	return int(countUser), int(countLab), int(countPeminjaman), int(countJadwal), peminjamanPerLab, jadwalPerLab, nil
 }
 
+// countPerLab menghitung baris table per lab_id pada bulan dan tahun tertentu dalam satu query
+func (r *dashboardRepository) countPerLab(table string, month, year int) ([]dtos.DashboardLabCount, error) {
+	var counts []dtos.DashboardLabCount
+	err := r.db.Model(&models.Lab{}).
+		Select("labs.id AS lab_id, labs.name AS name, COUNT(t.id) AS total").
+		Joins("LEFT JOIN "+table+" t ON t.lab_id = labs.id AND t.deleted_at IS NULL AND EXTRACT(MONTH FROM t.created_at) = ? AND EXTRACT(YEAR FROM t.created_at) = ?", month, year).
+		Group("labs.id, labs.name").
+		Order("labs.id ASC").
+		Scan(&counts).Error
+	return counts, err
+}
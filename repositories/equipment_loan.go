@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EquipmentLoanRepository interface {
+	GetEquipmentLoans(page, limit int, userID uint, status string) ([]models.EquipmentLoan, int, error)
+	GetEquipmentLoanByID(id, userID uint) (models.EquipmentLoan, error)
+	GetBookableQuantity(labID, equipmentTypeID uint, from, to time.Time, excludeLoanID uint) (int, error)
+	CreateEquipmentLoan(equipmentLoan models.EquipmentLoan) (models.EquipmentLoan, error)
+	AcceptEquipmentLoan(equipmentLoan models.EquipmentLoan, columns ...string) (models.EquipmentLoan, error)
+	UpdateEquipmentLoanStatus(equipmentLoan models.EquipmentLoan, fromStatus string, columns ...string) (models.EquipmentLoan, error)
+	HandOverEquipmentLoan(equipmentLoan models.EquipmentLoan, unitIDs []uint) (models.EquipmentLoan, error)
+	ReturnEquipmentLoan(equipmentLoan models.EquipmentLoan) (models.EquipmentLoan, error)
+}
+
+type equipmentLoanRepository struct {
+	db *gorm.DB
+}
+
+func NewEquipmentLoanRepository(db *gorm.DB) EquipmentLoanRepository {
+	return &equipmentLoanRepository{db}
+}
+
+// GetEquipmentLoans mengambil peminjaman alat, userID 0 berarti semua user (admin)
+func (r *equipmentLoanRepository) GetEquipmentLoans(page, limit int, userID uint, status string) ([]models.EquipmentLoan, int, error) {
+	var (
+		equipmentLoans []models.EquipmentLoan
+		count          int64
+	)
+
+	query := r.db.Model(&models.EquipmentLoan{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return equipmentLoans, int(count), err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Items.EquipmentType").Order("id DESC").Limit(limit).Offset(offset).Find(&equipmentLoans).Error
+	return equipmentLoans, int(count), err
+}
+
+func (r *equipmentLoanRepository) GetEquipmentLoanByID(id, userID uint) (models.EquipmentLoan, error) {
+	var equipmentLoan models.EquipmentLoan
+	query := r.db.Preload("Items.EquipmentType").Preload("Units.EquipmentUnit.EquipmentType").Where("id = ?", id)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	err := query.First(&equipmentLoan).Error
+	return equipmentLoan, err
+}
+
+func (r *equipmentLoanRepository) GetBookableQuantity(labID, equipmentTypeID uint, from, to time.Time, excludeLoanID uint) (int, error) {
+	return bookableQuantity(r.db, labID, equipmentTypeID, from, to, excludeLoanID)
+}
+
+// bookableQuantity menghitung unit layak pakai dikurangi jumlah yang sudah dialokasikan untuk
+// peminjaman alat lain yang diterima/sedang dipinjam pada rentang tanggal yang beririsan
+func bookableQuantity(db *gorm.DB, labID, equipmentTypeID uint, from, to time.Time, excludeLoanID uint) (int, error) {
+	var units int64
+	err := db.Model(&models.EquipmentUnit{}).
+		Where("lab_id = ? AND equipment_type_id = ?", labID, equipmentTypeID).
+		Where("`condition` = ? AND status IN ?", models.EquipmentConditionGood, []string{models.EquipmentUnitStatusAvailable, models.EquipmentUnitStatusLoaned}).
+		Count(&units).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var reserved int64
+	err = db.Model(&models.EquipmentLoanItem{}).
+		Select("COALESCE(SUM(equipment_loan_items.quantity), 0)").
+		Joins("JOIN equipment_loans ON equipment_loans.id = equipment_loan_items.equipment_loan_id AND equipment_loans.deleted_at IS NULL").
+		Where("equipment_loans.lab_id = ? AND equipment_loan_items.equipment_type_id = ?", labID, equipmentTypeID).
+		Where("equipment_loans.status IN ?", []string{models.EquipmentLoanStatusAccept, models.EquipmentLoanStatusHandedOver}).
+		Where("equipment_loans.tanggal_pinjam <= ? AND equipment_loans.tanggal_kembali >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Where("equipment_loans.id <> ?", excludeLoanID).
+		Scan(&reserved).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(units - reserved), nil
+}
+
+func (r *equipmentLoanRepository) CreateEquipmentLoan(equipmentLoan models.EquipmentLoan) (models.EquipmentLoan, error) {
+	err := r.db.Omit("User", "Lab", "Items.EquipmentType").Create(&equipmentLoan).Error
+	return equipmentLoan, err
+}
+
+// AcceptEquipmentLoan menerima peminjaman alat hanya jika stok setiap item masih cukup.
+// Baris lab dikunci agar dua penerimaan bersamaan tidak memakai stok yang sama.
+func (r *equipmentLoanRepository) AcceptEquipmentLoan(equipmentLoan models.EquipmentLoan, columns ...string) (models.EquipmentLoan, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var lab models.Lab
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", equipmentLoan.LabID).First(&lab).Error
+		if err != nil {
+			return err
+		}
+
+		for _, item := range equipmentLoan.Items {
+			bookable, err := bookableQuantity(tx, equipmentLoan.LabID, item.EquipmentTypeID, *equipmentLoan.TanggalPinjam, *equipmentLoan.TanggalKembali, equipmentLoan.ID)
+			if err != nil {
+				return err
+			}
+			if bookable < item.Quantity {
+				return helpers.ErrStockInsufficient
+			}
+		}
+
+		equipmentLoan.Status = models.EquipmentLoanStatusAccept
+		return updateEquipmentLoanStatus(tx, equipmentLoan, models.EquipmentLoanStatusRequest, columns...)
+	})
+	return equipmentLoan, err
+}
+
+func (r *equipmentLoanRepository) UpdateEquipmentLoanStatus(equipmentLoan models.EquipmentLoan, fromStatus string, columns ...string) (models.EquipmentLoan, error) {
+	err := updateEquipmentLoanStatus(r.db, equipmentLoan, fromStatus, columns...)
+	return equipmentLoan, err
+}
+
+// updateEquipmentLoanStatus hanya berhasil jika status di database masih fromStatus
+func updateEquipmentLoanStatus(db *gorm.DB, equipmentLoan models.EquipmentLoan, fromStatus string, columns ...string) error {
+	result := db.Model(&models.EquipmentLoan{}).
+		Where("id = ? AND status = ?", equipmentLoan.ID, fromStatus).
+		Select("status", toInterfaces(columns)...).
+		Updates(&equipmentLoan)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return helpers.ErrInvalidStatusTransition
+	}
+	return nil
+}
+
+// HandOverEquipmentLoan menyerahkan unit ke peminjam. Jika unitIDs kosong, unit yang tersedia
+// dipilih otomatis sesuai jumlah setiap item. Unit dikunci selama transaksi.
+func (r *equipmentLoanRepository) HandOverEquipmentLoan(equipmentLoan models.EquipmentLoan, unitIDs []uint) (models.EquipmentLoan, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var units []models.EquipmentUnit
+		availableUnits := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("lab_id = ? AND status = ? AND `condition` = ?", equipmentLoan.LabID, models.EquipmentUnitStatusAvailable, models.EquipmentConditionGood)
+
+		if len(unitIDs) == 0 {
+			for _, item := range equipmentLoan.Items {
+				var itemUnits []models.EquipmentUnit
+				err := availableUnits.Session(&gorm.Session{}).
+					Where("equipment_type_id = ?", item.EquipmentTypeID).
+					Order("id ASC").Limit(item.Quantity).Find(&itemUnits).Error
+				if err != nil {
+					return err
+				}
+				if len(itemUnits) < item.Quantity {
+					return helpers.ErrStockInsufficient
+				}
+				units = append(units, itemUnits...)
+			}
+		} else {
+			if err := availableUnits.Where("id IN ?", unitIDs).Find(&units).Error; err != nil {
+				return err
+			}
+			if len(units) != len(unitIDs) || !unitsMatchItems(units, equipmentLoan.Items) {
+				return errors.New("unit yang diserahkan harus tersedia dan sesuai dengan jenis serta jumlah item peminjaman")
+			}
+		}
+
+		if err := updateEquipmentLoanStatus(tx, equipmentLoan, models.EquipmentLoanStatusAccept, "handed_over_by_id", "handed_over_at"); err != nil {
+			return err
+		}
+
+		var loanUnits []models.EquipmentLoanUnit
+		var ids []uint
+		for _, unit := range units {
+			loanUnits = append(loanUnits, models.EquipmentLoanUnit{EquipmentLoanID: equipmentLoan.ID, EquipmentUnitID: unit.ID})
+			ids = append(ids, unit.ID)
+		}
+		if err := tx.Omit("EquipmentUnit").Create(&loanUnits).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.EquipmentUnit{}).Where("id IN ?", ids).Update("status", models.EquipmentUnitStatusLoaned).Error
+	})
+	return equipmentLoan, err
+}
+
+func unitsMatchItems(units []models.EquipmentUnit, items []models.EquipmentLoanItem) bool {
+	counts := map[uint]int{}
+	for _, unit := range units {
+		counts[unit.EquipmentTypeID]++
+	}
+	for _, item := range items {
+		if counts[item.EquipmentTypeID] != item.Quantity {
+			return false
+		}
+		delete(counts, item.EquipmentTypeID)
+	}
+	return len(counts) == 0
+}
+
+// ReturnEquipmentLoan menutup peminjaman alat dan mengembalikan unit yang masih dipinjam menjadi tersedia
+func (r *equipmentLoanRepository) ReturnEquipmentLoan(equipmentLoan models.EquipmentLoan) (models.EquipmentLoan, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateEquipmentLoanStatus(tx, equipmentLoan, models.EquipmentLoanStatusHandedOver, "returned_by_id", "returned_at"); err != nil {
+			return err
+		}
+
+		loanUnits := tx.Model(&models.EquipmentLoanUnit{}).Select("equipment_unit_id").Where("equipment_loan_id = ?", equipmentLoan.ID)
+		return tx.Model(&models.EquipmentUnit{}).
+			Where("id IN (?) AND status = ?", loanUnits, models.EquipmentUnitStatusLoaned).
+			Update("status", models.EquipmentUnitStatusAvailable).Error
+	})
+	return equipmentLoan, err
+}
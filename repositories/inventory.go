@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type InventoryRepository interface {
+	GetEquipmentTypes(search string) ([]models.EquipmentType, error)
+	GetEquipmentTypeByID(id uint) (models.EquipmentType, error)
+	CreateEquipmentType(equipmentType models.EquipmentType) (models.EquipmentType, error)
+	UpdateEquipmentType(equipmentType models.EquipmentType) (models.EquipmentType, error)
+	DeleteEquipmentType(id uint) error
+	GetEquipmentUnits(labID, equipmentTypeID uint) ([]models.EquipmentUnit, error)
+	GetEquipmentUnitByID(id uint) (models.EquipmentUnit, error)
+	GetEquipmentUnitBySerialNumber(serialNumber string) (models.EquipmentUnit, error)
+	CreateEquipmentUnit(equipmentUnit models.EquipmentUnit) (models.EquipmentUnit, error)
+	UpdateEquipmentUnit(equipmentUnit models.EquipmentUnit) (models.EquipmentUnit, error)
+	DeleteEquipmentUnit(id uint) error
+	GetEquipmentStock(labID uint) ([]dtos.EquipmentStock, error)
+}
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) InventoryRepository {
+	return &inventoryRepository{db}
+}
+
+func (r *inventoryRepository) GetEquipmentTypes(search string) ([]models.EquipmentType, error) {
+	var equipmentTypes []models.EquipmentType
+	query := r.db
+	if search != "" {
+		query = query.Where("name LIKE ? OR category LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	err := query.Order("name ASC").Find(&equipmentTypes).Error
+	return equipmentTypes, err
+}
+
+func (r *inventoryRepository) GetEquipmentTypeByID(id uint) (models.EquipmentType, error) {
+	var equipmentType models.EquipmentType
+	err := r.db.Where("id = ?", id).First(&equipmentType).Error
+	return equipmentType, err
+}
+
+func (r *inventoryRepository) CreateEquipmentType(equipmentType models.EquipmentType) (models.EquipmentType, error) {
+	err := r.db.Create(&equipmentType).Error
+	return equipmentType, err
+}
+
+func (r *inventoryRepository) UpdateEquipmentType(equipmentType models.EquipmentType) (models.EquipmentType, error) {
+	err := r.db.Save(&equipmentType).Error
+	return equipmentType, err
+}
+
+func (r *inventoryRepository) DeleteEquipmentType(id uint) error {
+	var equipmentType models.EquipmentType
+	return r.db.Where("id = ?", id).Delete(&equipmentType).Error
+}
+
+// GetEquipmentUnits mengambil unit alat, labID atau equipmentTypeID bernilai 0 berarti tanpa filter
+func (r *inventoryRepository) GetEquipmentUnits(labID, equipmentTypeID uint) ([]models.EquipmentUnit, error) {
+	var equipmentUnits []models.EquipmentUnit
+	query := r.db.Preload("EquipmentType")
+	if labID != 0 {
+		query = query.Where("lab_id = ?", labID)
+	}
+	if equipmentTypeID != 0 {
+		query = query.Where("equipment_type_id = ?", equipmentTypeID)
+	}
+	err := query.Order("equipment_type_id ASC, serial_number ASC").Find(&equipmentUnits).Error
+	return equipmentUnits, err
+}
+
+func (r *inventoryRepository) GetEquipmentUnitByID(id uint) (models.EquipmentUnit, error) {
+	var equipmentUnit models.EquipmentUnit
+	err := r.db.Preload("EquipmentType").Where("id = ?", id).First(&equipmentUnit).Error
+	return equipmentUnit, err
+}
+
+func (r *inventoryRepository) GetEquipmentUnitBySerialNumber(serialNumber string) (models.EquipmentUnit, error) {
+	var equipmentUnit models.EquipmentUnit
+	err := r.db.Where("serial_number = ?", serialNumber).First(&equipmentUnit).Error
+	return equipmentUnit, err
+}
+
+func (r *inventoryRepository) CreateEquipmentUnit(equipmentUnit models.EquipmentUnit) (models.EquipmentUnit, error) {
+	err := r.db.Omit("EquipmentType", "Lab").Create(&equipmentUnit).Error
+	return equipmentUnit, err
+}
+
+func (r *inventoryRepository) UpdateEquipmentUnit(equipmentUnit models.EquipmentUnit) (models.EquipmentUnit, error) {
+	err := r.db.Omit("EquipmentType", "Lab").Save(&equipmentUnit).Error
+	return equipmentUnit, err
+}
+
+func (r *inventoryRepository) DeleteEquipmentUnit(id uint) error {
+	var equipmentUnit models.EquipmentUnit
+	return r.db.Where("id = ?", id).Delete(&equipmentUnit).Error
+}
+
+// GetEquipmentStock menghitung stok unit per lab dan jenis alat dalam satu query, labID 0 berarti semua lab
+func (r *inventoryRepository) GetEquipmentStock(labID uint) ([]dtos.EquipmentStock, error) {
+	var stocks []dtos.EquipmentStock
+	query := r.db.Model(&models.EquipmentUnit{}).
+		Select("lab_id, equipment_type_id, " +
+			"SUM(CASE WHEN status <> 'retired' THEN 1 ELSE 0 END) AS total, " +
+			"SUM(CASE WHEN status = 'available' AND `condition` = 'good' THEN 1 ELSE 0 END) AS available, " +
+			"SUM(CASE WHEN status = 'loaned' THEN 1 ELSE 0 END) AS loaned, " +
+			"SUM(CASE WHEN status = 'maintenance' THEN 1 ELSE 0 END) AS maintenance")
+	if labID != 0 {
+		query = query.Where("lab_id = ?", labID)
+	}
+	err := query.Group("lab_id, equipment_type_id").Order("lab_id ASC, equipment_type_id ASC").Scan(&stocks).Error
+	return stocks, err
+}
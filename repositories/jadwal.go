@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"sistem_peminjaman_be/dtos"
 	"sistem_peminjaman_be/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -15,6 +17,11 @@ type JadwalRepository interface {
 	UpdateJadwal(jadwal models.Jadwal) (models.Jadwal, error)
 	DeleteJadwal(id uint) error
 	SearchJadwalAvailable(page, limit int, name_laboratorium string) ([]models.Jadwal, int, error)
+	GetSlotUsageByLab(labID uint, from, to time.Time) ([]dtos.LabSlotUsage, error)
+	StartDueJadwals(now time.Time) (int64, error)
+	FinishDueJadwals(now time.Time) (int64, error)
+	GetCalendarJadwals(labID uint, from time.Time) ([]models.Jadwal, error)
+	ExportJadwals(filter dtos.JadwalExportFilter, fn func(row dtos.JadwalExportRow) error) error
 }
 
 type jadwalRepository struct {
@@ -32,27 +39,27 @@ func (r *jadwalRepository) GetAllJadwals(page, limit int) ([]models.Jadwal, int,
 		jadwals []models.Jadwal
 		count  int64
 	)
-	err := r.db.Find(&jadwals).Count(&count).Error
+	err := r.db.Model(&models.Jadwal{}).Count(&count).Error
 	if err != nil {
 		return jadwals, int(count), err
 	}
 
 	offset := (page - 1) * limit
 
-	err = r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&jadwals).Error
+	err = r.db.Preload("Lab").Preload("User").Preload("Peminjaman").Order("id DESC").Limit(limit).Offset(offset).Find(&jadwals).Error
 
 	return jadwals, int(count), err
 }
 
 func (r *jadwalRepository) GetJadwalByID(id uint) (models.Jadwal, error) {
 	var jadwal models.Jadwal
-	err := r.db.Where("id = ?", id).First(&jadwal).Error
+	err := r.db.Preload("Lab").Preload("User").Preload("Peminjaman").Where("id = ?", id).First(&jadwal).Error
 	return jadwal, err
 }
 
 func (r *jadwalRepository) GetJadwalByID2(id uint) (models.Jadwal, error) {
 	var jadwal models.Jadwal
-	err := r.db.Unscoped().Where("id = ?", id).First(&jadwal).Error
+	err := r.db.Unscoped().Preload("Lab").Preload("User").Preload("Peminjaman").Where("id = ?", id).First(&jadwal).Error
 	return jadwal, err
 }
 
@@ -67,12 +74,12 @@ func (r *jadwalRepository) GetJadwalByID3(id uint, userID uint) (models.Jadwal,
 }
 
 func (r *jadwalRepository) CreateJadwal(jadwal models.Jadwal) (models.Jadwal, error) {
-	err := r.db.Create(&jadwal).Error
+	err := r.db.Omit("Lab", "User", "Peminjaman").Create(&jadwal).Error
 	return jadwal, err
 }
 
 func (r *jadwalRepository) UpdateJadwal(jadwal models.Jadwal) (models.Jadwal, error) {
-	err := r.db.Save(&jadwal).Error
+	err := r.db.Omit("Lab", "User", "Peminjaman").Save(&jadwal).Error
 	return jadwal, err
 }
 
@@ -90,10 +97,10 @@ func (r *jadwalRepository) SearchJadwalAvailable(page, limit int, name_laborator
 	)
 
 	if  name_laboratorium == "" {
-		err = r.db.Find(&jadwals).Count(&count).Error
+		err = r.db.Model(&models.Jadwal{}).Count(&count).Error
 	}
 	if  name_laboratorium != "" {
-		err = r.db.Where("name_laboratorium LIKE ?", "%"+name_laboratorium+"%").Find(&jadwals).Count(&count).Error
+		err = r.db.Model(&models.Jadwal{}).Joins("LEFT JOIN labs ON labs.id = jadwals.lab_id").Where("labs.name LIKE ? OR (jadwals.lab_id IS NULL AND jadwals.name_laboratorium LIKE ?)", "%"+name_laboratorium+"%", "%"+name_laboratorium+"%").Count(&count).Error
 	}
 
 	if err != nil {
@@ -103,12 +110,99 @@ func (r *jadwalRepository) SearchJadwalAvailable(page, limit int, name_laborator
 	offset := (page - 1) * limit
 
 	if  name_laboratorium == "" {
-		err = r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&jadwals).Error
+		err = r.db.Preload("Lab").Preload("User").Preload("Peminjaman").Order("id DESC").Limit(limit).Offset(offset).Find(&jadwals).Error
 	}
 	if  name_laboratorium != "" {
-		err = r.db.Where("name_laboratorium LIKE ?", "%"+name_laboratorium+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&jadwals).Error
+		err = r.db.Preload("Lab").Preload("User").Preload("Peminjaman").Joins("LEFT JOIN labs ON labs.id = jadwals.lab_id").Where("labs.name LIKE ? OR (jadwals.lab_id IS NULL AND jadwals.name_laboratorium LIKE ?)", "%"+name_laboratorium+"%", "%"+name_laboratorium+"%").Order("jadwals.id DESC").Limit(limit).Offset(offset).Find(&jadwals).Error
 	}
 
 	return jadwals, int(count), err
 
-}
\ No newline at end of file
+}
+
+// GetSlotUsageByLab menghitung jumlah jadwal lab per tanggal dan jam dalam satu query.
+// Jadwal hasil peminjaman tidak dihitung karena peminjamannya sudah terhitung sebagai booked.
+func (r *jadwalRepository) GetSlotUsageByLab(labID uint, from, to time.Time) ([]dtos.LabSlotUsage, error) {
+	var usages []dtos.LabSlotUsage
+	err := r.db.Model(&models.Jadwal{}).
+		Select("DATE_FORMAT(tanggal_jadwal, '%Y-%m-%d') AS tanggal, waktu_jadwal AS jam, waktu_selesai AS jam_selesai, COUNT(*) AS scheduled").
+		Where("lab_id = ? AND tanggal_jadwal BETWEEN ? AND ?", labID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("status <> ? AND peminjaman_id IS NULL", models.JadwalStatusCancelled).
+		Group("tanggal_jadwal, waktu_jadwal, waktu_selesai").
+		Scan(&usages).Error
+	return usages, err
+}
+
+// StartDueJadwals mengubah jadwal notused menjadi inused ketika waktunya sedang berlangsung.
+// Jadwal hasil peminjaman dilewati karena statusnya mengikuti check-in peminjaman.
+func (r *jadwalRepository) StartDueJadwals(now time.Time) (int64, error) {
+	current := now.Format("2006-01-02 15:04")
+	result := r.db.Model(&models.Jadwal{}).
+		Where("status = ? AND peminjaman_id IS NULL", models.JadwalStatusNotUsed).
+		Where("CONCAT(tanggal_jadwal, ' ', waktu_jadwal) <= ? AND CONCAT(tanggal_jadwal, ' ', waktu_selesai) > ?", current, current).
+		Update("status", models.JadwalStatusInUsed)
+	return result.RowsAffected, result.Error
+}
+
+// FinishDueJadwals mengubah jadwal yang waktu selesainya sudah lewat menjadi finished.
+// Jadwal hasil peminjaman dilewati karena statusnya mengikuti check-out peminjaman.
+func (r *jadwalRepository) FinishDueJadwals(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Jadwal{}).
+		Where("status IN ? AND peminjaman_id IS NULL", []string{models.JadwalStatusNotUsed, models.JadwalStatusInUsed}).
+		Where("CONCAT(tanggal_jadwal, ' ', waktu_selesai) <= ?", now.Format("2006-01-02 15:04")).
+		Update("status", models.JadwalStatusFinished)
+	return result.RowsAffected, result.Error
+}
+
+// GetCalendarJadwals mengambil jadwal lab untuk feed kalender. Jadwal hasil peminjaman dilewati
+// karena peminjamannya sudah masuk feed sebagai event tersendiri.
+func (r *jadwalRepository) GetCalendarJadwals(labID uint, from time.Time) ([]models.Jadwal, error) {
+	var jadwals []models.Jadwal
+	err := r.db.Preload("Lab").
+		Where("lab_id = ? AND peminjaman_id IS NULL AND tanggal_jadwal >= ?", labID, from.Format("2006-01-02")).
+		Order("tanggal_jadwal ASC, waktu_jadwal ASC").
+		Find(&jadwals).Error
+	return jadwals, err
+}
+
+// ExportJadwals membaca jadwal baris demi baris untuk export. Nama lab mengikuti lab terhubung,
+// atau name_laboratorium untuk jadwal lama yang belum memiliki lab_id.
+func (r *jadwalRepository) ExportJadwals(filter dtos.JadwalExportFilter, fn func(row dtos.JadwalExportRow) error) error {
+	query := r.db.Table("jadwals").
+		Select("jadwals.id, COALESCE(users.full_name, jadwals.name_user, '') AS full_name, COALESCE(users.nimn_ip, '') AS nimn_ip, " +
+			"COALESCE(labs.name, jadwals.name_laboratorium, '') AS lab_name, COALESCE(DATE_FORMAT(jadwals.tanggal_jadwal, '%Y-%m-%d'), '') AS tanggal_jadwal, " +
+			"COALESCE(jadwals.waktu_jadwal, '') AS waktu_jadwal, COALESCE(jadwals.waktu_selesai, '') AS waktu_selesai, COALESCE(jadwals.status, '') AS status, COALESCE(jadwals.peminjaman_id, 0) AS peminjaman_id, " +
+			"DATE_FORMAT(jadwals.created_at, '%Y-%m-%d %H:%i:%s') AS created_at, " +
+			"COALESCE((SELECT DATE_FORMAT(MAX(peminjaman_status_logs.created_at), '%Y-%m-%d %H:%i:%s') FROM peminjaman_status_logs " +
+			"WHERE peminjaman_status_logs.peminjaman_id = jadwals.peminjaman_id AND peminjaman_status_logs.to_status = 'accept' " +
+			"AND peminjaman_status_logs.deleted_at IS NULL), '') AS accepted_at").
+		Joins("LEFT JOIN users ON users.id = jadwals.user_id").
+		Joins("LEFT JOIN labs ON labs.id = jadwals.lab_id").
+		Where("jadwals.deleted_at IS NULL")
+	if filter.NameLaboratorium != "" {
+		query = query.Where("(labs.name LIKE ? OR (jadwals.lab_id IS NULL AND jadwals.name_laboratorium LIKE ?))", "%"+filter.NameLaboratorium+"%", "%"+filter.NameLaboratorium+"%")
+	}
+	if filter.From != nil {
+		query = query.Where("jadwals.tanggal_jadwal >= ?", filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		query = query.Where("jadwals.tanggal_jadwal <= ?", filter.To.Format("2006-01-02"))
+	}
+
+	rows, err := query.Order("jadwals.tanggal_jadwal ASC, jadwals.waktu_jadwal ASC, jadwals.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row dtos.JadwalExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"database/sql"
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type JobRunRepository interface {
+	CreateJobRun(jobRun models.JobRun) (models.JobRun, error)
+	WithJobLock(jobName string, fn func()) (bool, error)
+}
+
+type jobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) JobRunRepository {
+	return &jobRunRepository{db}
+}
+
+func (r *jobRunRepository) CreateJobRun(jobRun models.JobRun) (models.JobRun, error) {
+	err := r.db.Create(&jobRun).Error
+	return jobRun, err
+}
+
+// WithJobLock menjalankan fn hanya jika lock job berhasil diambil dan mengembalikan false jika worker lain
+// sedang memegangnya. Lock memakai GET_LOCK MySQL pada satu koneksi sehingga ikut lepas jika worker mati.
+func (r *jobRunRepository) WithJobLock(jobName string, fn func()) (bool, error) {
+	lockName := "scheduler:" + jobName
+	locked := false
+
+	err := r.db.Connection(func(conn *gorm.DB) error {
+		var acquired sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, 0)", lockName).Row().Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired.Int64 != 1 {
+			return nil
+		}
+
+		locked = true
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+		fn()
+		return nil
+	})
+	return locked, err
+}
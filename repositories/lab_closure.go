@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"errors"
+	"sistem_peminjaman_be/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LabClosureRepository interface {
+	GetLabClosures(labID uint, from, to *time.Time) ([]models.LabClosure, error)
+	GetClosuresForLab(labID uint, from, to time.Time) ([]models.LabClosure, error)
+	GetLabClosureByID(id uint) (models.LabClosure, error)
+	CreateLabClosure(labClosure models.LabClosure) (models.LabClosure, error)
+	UpdateLabClosure(labClosure models.LabClosure) (models.LabClosure, error)
+	DeleteLabClosure(id uint) error
+	UpsertLabClosureByUID(labClosure models.LabClosure) (models.LabClosure, bool, error)
+}
+
+type labClosureRepository struct {
+	db *gorm.DB
+}
+
+func NewLabClosureRepository(db *gorm.DB) LabClosureRepository {
+	return &labClosureRepository{db}
+}
+
+// GetLabClosures dipakai admin untuk melihat kalender penutupan. labID 0 berarti semua penutupan,
+// from dan to opsional untuk membatasi rentang tanggal.
+func (r *labClosureRepository) GetLabClosures(labID uint, from, to *time.Time) ([]models.LabClosure, error) {
+	var labClosures []models.LabClosure
+	query := r.db.Preload("Lab")
+	if labID != 0 {
+		query = query.Where("lab_id IS NULL OR lab_id = ?", labID)
+	}
+	if from != nil {
+		query = query.Where("tanggal_selesai >= ?", from.Format("2006-01-02"))
+	}
+	if to != nil {
+		query = query.Where("tanggal_mulai <= ?", to.Format("2006-01-02"))
+	}
+	err := query.Order("tanggal_mulai ASC").Find(&labClosures).Error
+	return labClosures, err
+}
+
+// GetClosuresForLab mengambil penutupan umum dan penutupan lab yang beririsan dengan rentang tanggal
+func (r *labClosureRepository) GetClosuresForLab(labID uint, from, to time.Time) ([]models.LabClosure, error) {
+	var labClosures []models.LabClosure
+	err := r.db.Where("lab_id IS NULL OR lab_id = ?", labID).
+		Where("tanggal_mulai <= ? AND tanggal_selesai >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("tanggal_mulai ASC").
+		Find(&labClosures).Error
+	return labClosures, err
+}
+
+func (r *labClosureRepository) GetLabClosureByID(id uint) (models.LabClosure, error) {
+	var labClosure models.LabClosure
+	err := r.db.Preload("Lab").Where("id = ?", id).First(&labClosure).Error
+	return labClosure, err
+}
+
+func (r *labClosureRepository) CreateLabClosure(labClosure models.LabClosure) (models.LabClosure, error) {
+	err := r.db.Omit("Lab").Create(&labClosure).Error
+	return labClosure, err
+}
+
+func (r *labClosureRepository) UpdateLabClosure(labClosure models.LabClosure) (models.LabClosure, error) {
+	err := r.db.Omit("Lab").Save(&labClosure).Error
+	return labClosure, err
+}
+
+func (r *labClosureRepository) DeleteLabClosure(id uint) error {
+	var labClosure models.LabClosure
+	err := r.db.Where("id = ?", id).Delete(&labClosure).Error
+	return err
+}
+
+// UpsertLabClosureByUID memperbarui penutupan hasil impor dengan UID dan lab yang sama, atau membuat
+// yang baru jika belum ada. Nilai bool bernilai true jika baris baru dibuat.
+func (r *labClosureRepository) UpsertLabClosureByUID(labClosure models.LabClosure) (models.LabClosure, bool, error) {
+	var existing models.LabClosure
+	query := r.db.Where("external_uid = ?", labClosure.ExternalUID)
+	if labClosure.LabID == nil {
+		query = query.Where("lab_id IS NULL")
+	} else {
+		query = query.Where("lab_id = ?", *labClosure.LabID)
+	}
+
+	err := query.First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.db.Omit("Lab").Create(&labClosure).Error
+		return labClosure, true, err
+	}
+	if err != nil {
+		return labClosure, false, err
+	}
+
+	labClosure.ID = existing.ID
+	labClosure.CreatedAt = existing.CreatedAt
+	err = r.db.Omit("Lab").Save(&labClosure).Error
+	return labClosure, false, err
+}
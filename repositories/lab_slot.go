@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type LabSlotRepository interface {
+	GetLabSlotsByLabID(labID uint) ([]models.LabSlot, error)
+	GetLabSlotByID(id uint) (models.LabSlot, error)
+	GetActiveLabSlot(labID uint, weekday int, jamMulai string) (models.LabSlot, error)
+	CreateLabSlot(labSlot models.LabSlot) (models.LabSlot, error)
+	CreateLabSlots(labSlots []models.LabSlot) error
+	UpdateLabSlot(labSlot models.LabSlot) (models.LabSlot, error)
+	DeleteLabSlot(id uint) error
+}
+
+type labSlotRepository struct {
+	db *gorm.DB
+}
+
+func NewLabSlotRepository(db *gorm.DB) LabSlotRepository {
+	return &labSlotRepository{db}
+}
+
+func (r *labSlotRepository) GetLabSlotsByLabID(labID uint) ([]models.LabSlot, error) {
+	var labSlots []models.LabSlot
+	err := r.db.Where("lab_id = ?", labID).Order("weekday ASC, jam_mulai ASC").Find(&labSlots).Error
+	return labSlots, err
+}
+
+func (r *labSlotRepository) GetLabSlotByID(id uint) (models.LabSlot, error) {
+	var labSlot models.LabSlot
+	err := r.db.Where("id = ?", id).First(&labSlot).Error
+	return labSlot, err
+}
+
+func (r *labSlotRepository) GetActiveLabSlot(labID uint, weekday int, jamMulai string) (models.LabSlot, error) {
+	var labSlot models.LabSlot
+	err := r.db.Where("lab_id = ? AND weekday = ? AND jam_mulai = ? AND is_active = ?", labID, weekday, jamMulai, true).First(&labSlot).Error
+	return labSlot, err
+}
+
+func (r *labSlotRepository) CreateLabSlot(labSlot models.LabSlot) (models.LabSlot, error) {
+	err := r.db.Create(&labSlot).Error
+	return labSlot, err
+}
+
+func (r *labSlotRepository) CreateLabSlots(labSlots []models.LabSlot) error {
+	return r.db.Create(&labSlots).Error
+}
+
+func (r *labSlotRepository) UpdateLabSlot(labSlot models.LabSlot) (models.LabSlot, error) {
+	err := r.db.Save(&labSlot).Error
+	return labSlot, err
+}
+
+func (r *labSlotRepository) DeleteLabSlot(id uint) error {
+	var labSlot models.LabSlot
+	err := r.db.Where("id = ?", id).Delete(&labSlot).Error
+	return err
+}
@@ -10,6 +10,8 @@ type LabRepository interface {
 	GetAllLabs(page, limit int) ([]models.Lab, int, error)
 	GetLabByID(id uint) (models.Lab, error)
 	GetLabByID2(id uint) (models.Lab, error)
+	GetLabsByIDs(ids []uint) ([]models.Lab, error)
+	GetLabByName(name string) (models.Lab, error)
 	CreateLab(Lab models.Lab) (models.Lab, error)
 	UpdateLab(Lab models.Lab) (models.Lab, error)
 	DeleteLab(id uint) error
@@ -31,7 +33,7 @@ func (r *labRepository) GetAllLabs(page, limit int) ([]models.Lab, int, error) {
 		labs []models.Lab
 		count  int64
 	)
-	err := r.db.Find(&labs).Count(&count).Error
+	err := r.db.Model(&models.Lab{}).Count(&count).Error
 	if err != nil {
 		return labs, int(count), err
 	}
@@ -55,6 +57,12 @@ func (r *labRepository) GetLabByID2(id uint) (models.Lab, error) {
 	return lab, err
 }
 
+func (r *labRepository) GetLabByName(name string) (models.Lab, error) {
+	var lab models.Lab
+	err := r.db.Where("name = ?", name).First(&lab).Error
+	return lab, err
+}
+
 func (r *labRepository) CreateLab(lab models.Lab) (models.Lab, error) {
 	err := r.db.Create(&lab).Error
 	return lab, err
@@ -79,10 +87,10 @@ func (r *labRepository) SearchLabAvailable(page, limit int, name string) ([]mode
 	)
 
 	if  name == "" {
-		err = r.db.Find(&labs).Count(&count).Error
+		err = r.db.Model(&models.Lab{}).Count(&count).Error
 	}
 	if  name != "" {
-		err = r.db.Where("name LIKE ?", "%"+name+"%").Find(&labs).Count(&count).Error
+		err = r.db.Model(&models.Lab{}).Where("name LIKE ?", "%"+name+"%").Count(&count).Error
 	}
 
 	if err != nil {
@@ -101,3 +109,13 @@ func (r *labRepository) SearchLabAvailable(page, limit int, name string) ([]mode
 	return labs, int(count), err
 
 }
+
+// GetLabsByIDs mengambil beberapa lab sekaligus, termasuk lab yang sudah dihapus seperti GetLabByID2
+func (r *labRepository) GetLabsByIDs(ids []uint) ([]models.Lab, error) {
+	var labs []models.Lab
+	if len(ids) == 0 {
+		return labs, nil
+	}
+	err := r.db.Unscoped().Where("id IN ?", ids).Find(&labs).Error
+	return labs, err
+}
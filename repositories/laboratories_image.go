@@ -9,6 +9,7 @@ import (
 type LabImageRepository interface {
 	GetAllLabImages(page, limit int) ([]models.LabImage, int, error)
 	GetAllLabImageByID(id uint) ([]models.LabImage, error)
+	GetLabImagesByLabIDs(labIDs []uint) ([]models.LabImage, error)
 	GetLabImageByID(id uint) (models.LabImage, error)
 	CreateLabImage(labImage models.LabImage) (models.LabImage, error)
 	UpdateLabImage(labImage models.LabImage) (models.LabImage, error)
@@ -69,3 +70,13 @@ func (r *labImageRepository) DeleteLabImage(id uint) error {
 	err := r.db.Unscoped().Where("lab_id = ?", id).Delete(&labImage).Error
 	return err
 }
+
+// GetLabImagesByLabIDs mengambil gambar beberapa lab dalam satu query
+func (r *labImageRepository) GetLabImagesByLabIDs(labIDs []uint) ([]models.LabImage, error) {
+	var labImages []models.LabImage
+	if len(labIDs) == 0 {
+		return labImages, nil
+	}
+	err := r.db.Where("lab_id IN ?", labIDs).Order("id ASC").Find(&labImages).Error
+	return labImages, err
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type LetterTemplateRepository interface {
+	GetLetterTemplates() ([]models.LetterTemplate, error)
+	GetLetterTemplateByID(id uint) (models.LetterTemplate, error)
+	GetActiveLetterTemplate() (models.LetterTemplate, error)
+	CreateLetterTemplate(letterTemplate models.LetterTemplate) (models.LetterTemplate, error)
+	UpdateLetterTemplate(letterTemplate models.LetterTemplate) (models.LetterTemplate, error)
+	DeleteLetterTemplate(id uint) error
+}
+
+type letterTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewLetterTemplateRepository(db *gorm.DB) LetterTemplateRepository {
+	return &letterTemplateRepository{db}
+}
+
+func (r *letterTemplateRepository) GetLetterTemplates() ([]models.LetterTemplate, error) {
+	var letterTemplates []models.LetterTemplate
+	err := r.db.Order("id ASC").Find(&letterTemplates).Error
+	return letterTemplates, err
+}
+
+func (r *letterTemplateRepository) GetLetterTemplateByID(id uint) (models.LetterTemplate, error) {
+	var letterTemplate models.LetterTemplate
+	err := r.db.Where("id = ?", id).First(&letterTemplate).Error
+	return letterTemplate, err
+}
+
+func (r *letterTemplateRepository) GetActiveLetterTemplate() (models.LetterTemplate, error) {
+	var letterTemplate models.LetterTemplate
+	err := r.db.Where("is_active = ?", true).Order("id DESC").First(&letterTemplate).Error
+	return letterTemplate, err
+}
+
+// CreateLetterTemplate menyimpan template baru, jika template aktif maka template lain dinonaktifkan
+func (r *letterTemplateRepository) CreateLetterTemplate(letterTemplate models.LetterTemplate) (models.LetterTemplate, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&letterTemplate).Error; err != nil {
+			return err
+		}
+		return deactivateOtherLetterTemplates(tx, letterTemplate)
+	})
+	return letterTemplate, err
+}
+
+func (r *letterTemplateRepository) UpdateLetterTemplate(letterTemplate models.LetterTemplate) (models.LetterTemplate, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&letterTemplate).Error; err != nil {
+			return err
+		}
+		return deactivateOtherLetterTemplates(tx, letterTemplate)
+	})
+	return letterTemplate, err
+}
+
+func (r *letterTemplateRepository) DeleteLetterTemplate(id uint) error {
+	return r.db.Delete(&models.LetterTemplate{}, id).Error
+}
+
+func deactivateOtherLetterTemplates(tx *gorm.DB, letterTemplate models.LetterTemplate) error {
+	if !letterTemplate.IsActive {
+		return nil
+	}
+	return tx.Model(&models.LetterTemplate{}).Where("id <> ? AND is_active = ?", letterTemplate.ID, true).Update("is_active", false).Error
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) models.User {
+	t.Helper()
+
+	user := models.User{FullName: email, Email: email, NIMNIP: "nim-" + email, Role: models.UserRoleUser}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("gagal membuat user: %v", err)
+	}
+	return user
+}
+
+func createTestLab(t *testing.T, db *gorm.DB, name string) models.Lab {
+	t.Helper()
+
+	lab := models.Lab{Name: name}
+	if err := db.Create(&lab).Error; err != nil {
+		t.Fatalf("gagal membuat lab: %v", err)
+	}
+	return lab
+}
+
+func testDate(days int) *time.Time {
+	tanggal := time.Now().AddDate(0, 0, days).Truncate(24 * time.Hour)
+	return &tanggal
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+
+	"sistem_peminjaman_be/configs"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/testutil"
+)
+
+// TestMigrateDBWidensEnumColumns memastikan database yang dibuat dengan daftar ENUM lama ikut
+// menerima status baru setelah migrasi
+func TestMigrateDBWidensEnumColumns(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+
+	legacyColumns := []struct {
+		model  interface{}
+		table  string
+		column string
+		legacy string
+		want   string
+	}{
+		{&models.Peminjaman{}, "peminjamen", "status", "ENUM('request','accept','reject')", "'no_show'"},
+		{&models.User{}, "users", "role", "ENUM('user','admin')", "'kepala_lab'"},
+		{&models.PeminjamanStatusLog{}, "peminjaman_status_logs", "actor_role", "ENUM('user','admin','system')", "'approver'"},
+		{&models.Jadwal{}, "jadwals", "status", "ENUM('notused','inused','finished')", "'cancelled'"},
+	}
+
+	for _, legacy := range legacyColumns {
+		if err := db.Exec("ALTER TABLE " + legacy.table + " MODIFY COLUMN " + legacy.column + " " + legacy.legacy).Error; err != nil {
+			t.Fatalf("gagal menyiapkan kolom lama %s.%s: %v", legacy.table, legacy.column, err)
+		}
+	}
+
+	if err := configs.MigrateDB(db); err != nil {
+		t.Fatalf("gagal migrasi database: %v", err)
+	}
+
+	for _, legacy := range legacyColumns {
+		columnTypes, err := db.Migrator().ColumnTypes(legacy.model)
+		if err != nil {
+			t.Fatalf("gagal membaca kolom %s: %v", legacy.table, err)
+		}
+		found := false
+		for _, columnType := range columnTypes {
+			if columnType.Name() != legacy.column {
+				continue
+			}
+			found = true
+			current, _ := columnType.ColumnType()
+			if !strings.Contains(current, legacy.want) {
+				t.Fatalf("kolom %s.%s masih %s, seharusnya berisi %s", legacy.table, legacy.column, current, legacy.want)
+			}
+		}
+		if !found {
+			t.Fatalf("kolom %s.%s tidak ditemukan", legacy.table, legacy.column)
+		}
+	}
+}
@@ -2,21 +2,38 @@ package repositories
 
 import (
     "errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
 	"sistem_peminjaman_be/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PeminjamanRepository interface {
-	GetPeminjamans(page, limit int, userID uint, status string) ([]models.Peminjaman, int, error)
+	GetPeminjamans(page, limit int, filter dtos.PeminjamanFilter) ([]models.Peminjaman, int, error)
 	GetPeminjamanByStatusAndID(id, userID uint, status string) (models.Peminjaman, error)
 	GetPeminjamanByID(id, userID uint) (models.Peminjaman, error)
 	GetPeminjamanID2(id, userID uint) (models.Peminjaman, error)
 	
 	GetPeminjamanID(peminjamanId uint) (models.Peminjaman, error)
+	GetPeminjamansByIDs(ids []uint) ([]models.Peminjaman, error)
 	CreatePeminjaman(peminjaman models.Peminjaman) (models.Peminjaman, error)
+	CreatePeminjamanIfAvailable(peminjaman models.Peminjaman, checkQuota QuotaCheck) (models.Peminjaman, error)
+	IsSlotAvailable(labID uint, tanggal time.Time, jamMulai, jamSelesai string, excludeID uint) (bool, error)
+	GetSlotUsageByLab(labID uint, from, to time.Time) ([]dtos.LabSlotUsage, error)
 	UpdatePeminjaman(peminjaman models.Peminjaman) (models.Peminjaman, error)
+	UpdatePeminjamanStatus(peminjaman models.Peminjaman, statusLog models.PeminjamanStatusLog, columns ...string) (models.Peminjaman, error)
+	UpdatePeminjamanStatusWithApproval(peminjaman models.Peminjaman, statusLog models.PeminjamanStatusLog, approval models.PeminjamanApproval) (models.Peminjaman, error)
+	UpdatePeminjamanStatuses(peminjamans []models.Peminjaman, statusLogs []models.PeminjamanStatusLog) ([]models.Peminjaman, error)
+	GetPeminjamansStartedBefore(status string, now time.Time) ([]models.Peminjaman, error)
+	GetPeminjamansEndedBefore(status string, now time.Time) ([]models.Peminjaman, error)
+	GetCalendarPeminjamans(userID, labID uint, statuses []string, from time.Time) ([]models.Peminjaman, error)
+	ExportPeminjamans(filter dtos.PeminjamanFilter, fn func(row dtos.PeminjamanExportRow) error) error
 }
 
 type peminjamanRepository struct {
@@ -27,48 +44,89 @@ func NewPeminjamanRepository(db *gorm.DB) PeminjamanRepository {
 	return &peminjamanRepository{db}
 }
 
-func (r *peminjamanRepository) GetPeminjamans(page, limit int, userID uint, status string) ([]models.Peminjaman, int, error) {
+// peminjamanSortColumns memetakan field sort yang boleh dipakai klien ke kolom SQL
+var peminjamanSortColumns = map[string]string{
+	"created_at":         "peminjamen.created_at",
+	"tanggal_peminjaman": "peminjamen.tanggal_peminjaman",
+	"jam_peminjaman":     "peminjamen.jam_peminjaman",
+	"status":             "peminjamen.status",
+	"lab":                "labs.name",
+	"user":               "users.full_name",
+}
+
+// GetPeminjamans mengambil satu halaman peminjaman sesuai filter. Filter, urutan, dan pagination
+// dijalankan dalam satu query sehingga jumlah data dan total selalu akurat.
+func (r *peminjamanRepository) GetPeminjamans(page, limit int, filter dtos.PeminjamanFilter) ([]models.Peminjaman, int, error) {
 	var (
 		peminjamans []models.Peminjaman
 		count       int64
-		err         error
 	)
-	if userID == 1 {
-		if status == "" {
-			err = r.db.Find(&peminjamans).Count(&count).Error
-		} else {
-			err = r.db.Where("status = ?", status).Find(&peminjamans).Count(&count).Error
-		}
-	} else {
-		if status == "" {
-			err = r.db.Where("user_id = ?", userID).Find(&peminjamans).Count(&count).Error
-		} else {
-			err = r.db.Where("user_id = ? AND status = ?", userID, status).Find(&peminjamans).Count(&count).Error
+
+	sortColumn := "peminjamen.created_at"
+	if filter.Sort != "" {
+		column, ok := peminjamanSortColumns[filter.Sort]
+		if !ok {
+			return peminjamans, 0, fmt.Errorf("sort %s tidak didukung", filter.Sort)
 		}
+		sortColumn = column
 	}
-	if err != nil {
-		return peminjamans, int(count), err
+	sortOrder := "DESC"
+	if strings.EqualFold(filter.Order, "asc") {
+		sortOrder = "ASC"
 	}
 
-	offset := (page - 1) * limit
+	query := r.db.Model(&models.Peminjaman{}).
+		Joins("LEFT JOIN labs ON labs.id = peminjamen.lab_id").
+		Joins("LEFT JOIN users ON users.id = peminjamen.user_id")
+	query = r.applyPeminjamanFilter(query, filter)
 
-	if userID == 1 {
-		if status == "" {
-			err = r.db.Find(&peminjamans).Count(&count).Error
-		} else {
-			err = r.db.Where("status = ?", status).Limit(limit).Offset(offset).Find(&peminjamans).Error
-		}
-	} else {
-		if status == "" {
-			err = r.db.Where("user_id = ?", userID).Limit(limit).Offset(offset).Find(&peminjamans).Error
-		} else {
-			err = r.db.Where("user_id = ? AND status = ?", userID, status).Limit(limit).Offset(offset).Find(&peminjamans).Error
-		}
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return peminjamans, 0, err
 	}
 
+	offset := (page - 1) * limit
+	err := query.Select("peminjamen.*").
+		Order(sortColumn + " " + sortOrder).
+		Order("peminjamen.id " + sortOrder).
+		Limit(limit).
+		Offset(offset).
+		Find(&peminjamans).Error
+
 	return peminjamans, int(count), err
 }
 
+// applyPeminjamanFilter menambahkan kondisi WHERE dari filter ke query yang sudah join labs dan users,
+// dipakai bersama oleh daftar dan export peminjaman agar keduanya selalu memfilter dengan cara yang sama
+func (r *peminjamanRepository) applyPeminjamanFilter(query *gorm.DB, filter dtos.PeminjamanFilter) *gorm.DB {
+	if filter.UserID != 0 {
+		query = query.Where("(peminjamen.user_id = ? OR peminjamen.id IN (?))", filter.UserID, r.participantPeminjamanIDs(filter.UserID))
+	}
+	if filter.LabID != 0 {
+		query = query.Where("peminjamen.lab_id = ?", filter.LabID)
+	}
+	if filter.LabName != "" {
+		query = query.Where("labs.name LIKE ?", "%"+filter.LabName+"%")
+	}
+	if filter.User != "" {
+		query = query.Where("(users.full_name LIKE ? OR users.nimn_ip LIKE ?)", "%"+filter.User+"%", "%"+filter.User+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("peminjamen.status = ?", filter.Status)
+	}
+	if filter.From != "" {
+		query = query.Where("peminjamen.tanggal_peminjaman >= ?", filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where("peminjamen.tanggal_peminjaman <= ?", filter.To)
+	}
+	return query
+}
+
+// participantPeminjamanIDs adalah subquery ID peminjaman kelompok yang mencatat user sebagai peserta
+func (r *peminjamanRepository) participantPeminjamanIDs(userID uint) *gorm.DB {
+	return r.db.Model(&models.PeminjamanParticipant{}).Select("peminjaman_id").Where("user_id = ?", userID)
+}
+
 func (r *peminjamanRepository) GetPeminjamanByStatusAndID(id, userID uint, status string) (models.Peminjaman, error) {
 	var peminjaman models.Peminjaman
 	if userID == 1 {
@@ -106,6 +164,12 @@ func (r *peminjamanRepository) GetPeminjamanID(peminjamanId uint) (models.Peminj
 	return peminjaman, err
 }
 
+func (r *peminjamanRepository) GetPeminjamansByIDs(ids []uint) ([]models.Peminjaman, error) {
+	var peminjamans []models.Peminjaman
+	err := r.db.Where("id IN ?", ids).Find(&peminjamans).Error
+	return peminjamans, err
+}
+
 func (r *peminjamanRepository) GetPeminjamanID2(id, userID uint) (models.Peminjaman, error) {
 	var peminjaman models.Peminjaman
 	if userID == 1 {
@@ -121,6 +185,100 @@ func (r *peminjamanRepository) CreatePeminjaman(peminjaman models.Peminjaman) (m
 	return peminjaman, err
 }
 
+// CreatePeminjamanIfAvailable menyimpan peminjaman hanya jika slot lab masih kosong.
+// Baris lab dikunci selama transaksi sehingga permintaan bersamaan untuk lab yang sama
+// diproses bergantian dan tidak bisa lolos pengecekan secara bersamaan. Baris user juga dikunci
+// sebelum checkQuota dijalankan agar pengajuan paralel milik user yang sama tidak melewati kuota.
+func (r *peminjamanRepository) CreatePeminjamanIfAvailable(peminjaman models.Peminjaman, checkQuota QuotaCheck) (models.Peminjaman, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, peminjaman.UserID); err != nil {
+			return err
+		}
+
+		var lab models.Lab
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", peminjaman.LabID).First(&lab).Error
+		if err != nil {
+			return err
+		}
+
+		available, err := slotAvailable(tx, lab, *peminjaman.TanggalPeminjaman, peminjaman.JamPeminjaman, peminjaman.JamSelesai, 0)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return helpers.ErrSlotConflict
+		}
+
+		if checkQuota != nil {
+			if err := checkQuota(NewBookingQuotaRepository(tx)); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&peminjaman).Error
+	})
+	return peminjaman, err
+}
+
+// lockUser mengunci baris user sampai transaksi selesai sehingga pengecekan kuota milik user yang sama
+// dijalankan bergantian
+func lockUser(tx *gorm.DB, userID uint) error {
+	var user models.User
+	return tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error
+}
+
+func (r *peminjamanRepository) IsSlotAvailable(labID uint, tanggal time.Time, jamMulai, jamSelesai string, excludeID uint) (bool, error) {
+	var lab models.Lab
+	err := r.db.Where("id = ?", labID).First(&lab).Error
+	if err != nil {
+		return false, err
+	}
+	return slotAvailable(r.db, lab, tanggal, jamMulai, jamSelesai, excludeID)
+}
+
+// slotAvailable mengecek apakah rentang jam lab pada tanggal tertentu belum beririsan dengan
+// peminjaman yang masih diajukan/diterima/berlangsung maupun dengan jadwal manual lab tersebut.
+// Jadwal yang dibuat dari peminjaman diabaikan karena peminjamannya sendiri sudah ikut dicek.
+func slotAvailable(db *gorm.DB, lab models.Lab, tanggal time.Time, jamMulai, jamSelesai string, excludeID uint) (bool, error) {
+	var countPeminjaman int64
+	err := db.Model(&models.Peminjaman{}).
+		Where("lab_id = ? AND tanggal_peminjaman = ? AND status IN ?", lab.ID, tanggal.Format("2006-01-02"), []string{models.PeminjamanStatusRequest, models.PeminjamanStatusAccept, models.PeminjamanStatusInUse}).
+		Where("jam_peminjaman < ? AND jam_selesai > ?", jamSelesai, jamMulai).
+		Where("id <> ?", excludeID).
+		Count(&countPeminjaman).Error
+	if err != nil {
+		return false, err
+	}
+	if countPeminjaman > 0 {
+		return false, nil
+	}
+
+	var countJadwal int64
+	err = db.Model(&models.Jadwal{}).
+		Where("lab_id = ? AND tanggal_jadwal = ?", lab.ID, tanggal.Format("2006-01-02")).
+		Where("status <> ? AND peminjaman_id IS NULL", models.JadwalStatusCancelled).
+		Where("waktu_jadwal < ? AND waktu_selesai > ?", jamSelesai, jamMulai).
+		Count(&countJadwal).Error
+	if err != nil {
+		return false, err
+	}
+
+	return countJadwal == 0, nil
+}
+
+// GetSlotUsageByLab menghitung jumlah peminjaman request/accept per tanggal dan jam dalam satu query
+func (r *peminjamanRepository) GetSlotUsageByLab(labID uint, from, to time.Time) ([]dtos.LabSlotUsage, error) {
+	var usages []dtos.LabSlotUsage
+	err := r.db.Model(&models.Peminjaman{}).
+		Select("DATE_FORMAT(tanggal_peminjaman, '%Y-%m-%d') AS tanggal, jam_peminjaman AS jam, jam_selesai, "+
+			"SUM(CASE WHEN status = 'request' THEN 1 ELSE 0 END) AS requested, "+
+			"SUM(CASE WHEN status IN ('accept', 'in_use') THEN 1 ELSE 0 END) AS booked").
+		Where("lab_id = ? AND tanggal_peminjaman BETWEEN ? AND ?", labID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("tanggal_peminjaman, jam_peminjaman, jam_selesai").
+		Scan(&usages).Error
+	return usages, err
+}
+
 func (r *peminjamanRepository) UpdatePeminjaman(peminjaman models.Peminjaman) (models.Peminjaman, error) {
 	err := r.db.Save(&peminjaman).Error
 	if err != nil {
@@ -129,5 +287,141 @@ func (r *peminjamanRepository) UpdatePeminjaman(peminjaman models.Peminjaman) (m
 	return peminjaman, nil
 }
 
+// UpdatePeminjamanStatus memindahkan status peminjaman dan mencatat log-nya dalam satu transaksi.
+// Update hanya berhasil jika status di database masih sama dengan FromStatus, sehingga dua perubahan
+// bersamaan tidak bisa saling menimpa. Kolom tambahan pada columns ikut disimpan dari nilai peminjaman.
+func (r *peminjamanRepository) UpdatePeminjamanStatus(peminjaman models.Peminjaman, statusLog models.PeminjamanStatusLog, columns ...string) (models.Peminjaman, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return updatePeminjamanStatus(tx, &peminjaman, statusLog, columns...)
+	})
+	return peminjaman, err
+}
 
+// UpdatePeminjamanStatusWithApproval menyimpan keputusan tahap persetujuan dan perpindahan status peminjaman
+// dalam satu transaksi. Tahap yang sudah diputuskan menghasilkan gorm.ErrRecordNotFound.
+func (r *peminjamanRepository) UpdatePeminjamanStatusWithApproval(peminjaman models.Peminjaman, statusLog models.PeminjamanStatusLog, approval models.PeminjamanApproval) (models.Peminjaman, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := decidePeminjamanApproval(tx, approval); err != nil {
+			return err
+		}
+		return updatePeminjamanStatus(tx, &peminjaman, statusLog)
+	})
+	return peminjaman, err
+}
+
+// UpdatePeminjamanStatuses memindahkan status banyak peminjaman sekaligus. Jika salah satu gagal
+// seluruh perubahan dibatalkan, statusLogs harus berurutan sama dengan peminjamans.
+func (r *peminjamanRepository) UpdatePeminjamanStatuses(peminjamans []models.Peminjaman, statusLogs []models.PeminjamanStatusLog) ([]models.Peminjaman, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range peminjamans {
+			if err := updatePeminjamanStatus(tx, &peminjamans[i], statusLogs[i]); err != nil {
+				return fmt.Errorf("peminjaman %d: %w", peminjamans[i].ID, err)
+			}
+		}
+		return nil
+	})
+	return peminjamans, err
+}
+
+func updatePeminjamanStatus(tx *gorm.DB, peminjaman *models.Peminjaman, statusLog models.PeminjamanStatusLog, columns ...string) error {
+	peminjaman.Status = statusLog.ToStatus
 
+	result := tx.Model(&models.Peminjaman{}).
+		Where("id = ? AND status = ?", peminjaman.ID, statusLog.FromStatus).
+		Select("status", toInterfaces(columns)...).
+		Updates(peminjaman)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return helpers.ErrInvalidStatusTransition
+	}
+
+	statusLog.PeminjamanID = peminjaman.ID
+	if err := tx.Create(&statusLog).Error; err != nil {
+		return err
+	}
+
+	return syncJadwalForStatus(tx, *peminjaman, statusLog.ToStatus)
+}
+
+func toInterfaces(columns []string) []interface{} {
+	values := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		values = append(values, column)
+	}
+	return values
+}
+
+// GetPeminjamansStartedBefore mengambil peminjaman berstatus tertentu yang jam mulainya sudah lewat
+func (r *peminjamanRepository) GetPeminjamansStartedBefore(status string, now time.Time) ([]models.Peminjaman, error) {
+	var peminjamans []models.Peminjaman
+	err := r.db.Where("status = ? AND CONCAT(tanggal_peminjaman, ' ', jam_peminjaman) <= ?", status, now.Format("2006-01-02 15:04")).
+		Find(&peminjamans).Error
+	return peminjamans, err
+}
+
+// GetPeminjamansEndedBefore mengambil peminjaman berstatus tertentu yang jam selesainya sudah lewat
+func (r *peminjamanRepository) GetPeminjamansEndedBefore(status string, now time.Time) ([]models.Peminjaman, error) {
+	var peminjamans []models.Peminjaman
+	err := r.db.Where("status = ? AND CONCAT(tanggal_peminjaman, ' ', jam_selesai) <= ?", status, now.Format("2006-01-02 15:04")).
+		Find(&peminjamans).Error
+	return peminjamans, err
+}
+
+// GetCalendarPeminjamans mengambil peminjaman untuk feed kalender mulai tanggal from. userID 0 berarti
+// semua user (termasuk peminjaman kelompok yang diikuti), labID 0 berarti semua lab.
+func (r *peminjamanRepository) GetCalendarPeminjamans(userID, labID uint, statuses []string, from time.Time) ([]models.Peminjaman, error) {
+	var peminjamans []models.Peminjaman
+	query := r.db.Preload("Lab").Where("status IN ? AND tanggal_peminjaman >= ?", statuses, from.Format("2006-01-02"))
+	if userID != 0 {
+		query = query.Where("user_id = ? OR id IN (?)", userID, r.participantPeminjamanIDs(userID))
+	}
+	if labID != 0 {
+		query = query.Where("lab_id = ?", labID)
+	}
+	err := query.Order("tanggal_peminjaman ASC, jam_peminjaman ASC").Find(&peminjamans).Error
+	return peminjamans, err
+}
+
+// ExportPeminjamans membaca peminjaman baris demi baris dan meneruskannya ke fn, sehingga export
+// data setahun tidak perlu dimuat sekaligus ke memori. Waktu persetujuan diambil dari riwayat status.
+func (r *peminjamanRepository) ExportPeminjamans(filter dtos.PeminjamanFilter, fn func(row dtos.PeminjamanExportRow) error) error {
+	query := r.db.Table("peminjamen").
+		Select("peminjamen.id, COALESCE(users.full_name, '') AS full_name, COALESCE(users.nimn_ip, '') AS nimn_ip, " +
+			"COALESCE(labs.name, '') AS lab_name, COALESCE(DATE_FORMAT(peminjamen.tanggal_peminjaman, '%Y-%m-%d'), '') AS tanggal_peminjaman, " +
+			"COALESCE(peminjamen.jam_peminjaman, '') AS jam_peminjaman, COALESCE(peminjamen.jam_selesai, '') AS jam_selesai, " +
+			"COALESCE(peminjamen.status, '') AS status, COALESCE(peminjamen.description, '') AS description, " +
+			"DATE_FORMAT(peminjamen.created_at, '%Y-%m-%d %H:%i:%s') AS requested_at, " +
+			statusLogTimeColumn(models.PeminjamanStatusAccept, "accepted_at") + ", " +
+			statusLogTimeColumn(models.PeminjamanStatusReject, "rejected_at") + ", " +
+			"COALESCE(DATE_FORMAT(peminjamen.cancelled_at, '%Y-%m-%d %H:%i:%s'), '') AS cancelled_at").
+		Joins("LEFT JOIN users ON users.id = peminjamen.user_id").
+		Joins("LEFT JOIN labs ON labs.id = peminjamen.lab_id").
+		Where("peminjamen.deleted_at IS NULL")
+	query = r.applyPeminjamanFilter(query, filter)
+
+	rows, err := query.Order("peminjamen.tanggal_peminjaman ASC, peminjamen.jam_peminjaman ASC, peminjamen.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row dtos.PeminjamanExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// statusLogTimeColumn adalah kolom waktu terakhir peminjaman berpindah ke status tertentu
+func statusLogTimeColumn(status, alias string) string {
+	return fmt.Sprintf("COALESCE((SELECT DATE_FORMAT(MAX(peminjaman_status_logs.created_at), '%%Y-%%m-%%d %%H:%%i:%%s') FROM peminjaman_status_logs "+
+		"WHERE peminjaman_status_logs.peminjaman_id = peminjamen.id AND peminjaman_status_logs.to_status = '%s' "+
+		"AND peminjaman_status_logs.deleted_at IS NULL), '') AS %s", status, alias)
+}
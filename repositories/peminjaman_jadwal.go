@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"errors"
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+// syncJadwalForStatus menyesuaikan jadwal lab dengan status baru peminjaman di dalam transaksi yang sama,
+// sehingga status dan jadwalnya selalu tersimpan atau batal bersama. Check-in dan check-out peminjaman
+// ikut disalin ke jadwalnya, sedangkan peminjaman no_show membatalkan jadwal yang tidak terpakai.
+func syncJadwalForStatus(tx *gorm.DB, peminjaman models.Peminjaman, toStatus string) error {
+	switch toStatus {
+	case models.PeminjamanStatusAccept:
+		return createJadwalForPeminjaman(tx, peminjaman)
+	case models.PeminjamanStatusInUse:
+		return updateJadwalUsage(tx, peminjaman.ID, []string{models.JadwalStatusNotUsed}, map[string]interface{}{
+			"status":          models.JadwalStatusInUsed,
+			"actual_start_at": peminjaman.ActualStartAt,
+		})
+	case models.PeminjamanStatusFinished:
+		return updateJadwalUsage(tx, peminjaman.ID, []string{models.JadwalStatusNotUsed, models.JadwalStatusInUsed}, map[string]interface{}{
+			"status":        models.JadwalStatusFinished,
+			"actual_end_at": peminjaman.ActualEndAt,
+		})
+	case models.PeminjamanStatusReject, models.PeminjamanStatusCancelled, models.PeminjamanStatusNoShow:
+		_, err := cancelJadwalsByPeminjamanID(tx, peminjaman.ID)
+		return err
+	}
+	return nil
+}
+
+// updateJadwalUsage memperbarui jadwal milik peminjaman yang statusnya masih salah satu dari fromStatuses
+func updateJadwalUsage(tx *gorm.DB, peminjamanID uint, fromStatuses []string, values map[string]interface{}) error {
+	return tx.Model(&models.Jadwal{}).
+		Where("peminjaman_id = ? AND status IN ?", peminjamanID, fromStatuses).
+		Updates(values).Error
+}
+
+// createJadwalForPeminjaman membuat jadwal lab dari peminjaman yang baru diterima sehingga admin
+// tidak perlu mengetik ulang sesi yang sama. Jadwal yang masih aktif tidak dibuat dua kali.
+func createJadwalForPeminjaman(tx *gorm.DB, peminjaman models.Peminjaman) error {
+	var existing models.Jadwal
+	err := tx.Where("peminjaman_id = ? AND status <> ?", peminjaman.ID, models.JadwalStatusCancelled).First(&existing).Error
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var lab models.Lab
+	if err := tx.Unscoped().Where("id = ?", peminjaman.LabID).First(&lab).Error; err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := tx.Unscoped().Where("id = ?", peminjaman.UserID).First(&user).Error; err != nil {
+		return err
+	}
+
+	jadwal := models.Jadwal{
+		TanggalJadwal:    peminjaman.TanggalPeminjaman,
+		WaktuJadwal:      peminjaman.JamPeminjaman,
+		WaktuSelesai:     peminjaman.JamSelesai,
+		LabSlotID:        peminjaman.LabSlotID,
+		LabID:            &lab.ID,
+		UserID:           &user.ID,
+		PeminjamanID:     &peminjaman.ID,
+		NameUser:         user.FullName,
+		NameLaboratorium: lab.Name,
+		Status:           models.JadwalStatusNotUsed,
+	}
+	return tx.Omit("Lab", "User", "Peminjaman").Create(&jadwal).Error
+}
+
+// cancelJadwalsByPeminjamanID membatalkan jadwal milik peminjaman yang belum selesai
+func cancelJadwalsByPeminjamanID(tx *gorm.DB, peminjamanID uint) (int64, error) {
+	result := tx.Model(&models.Jadwal{}).
+		Where("peminjaman_id = ? AND status IN ?", peminjamanID, []string{models.JadwalStatusNotUsed, models.JadwalStatusInUsed}).
+		Update("status", models.JadwalStatusCancelled)
+	return result.RowsAffected, result.Error
+}
+
+// moveJadwalForPeminjaman memindahkan jadwal aktif milik peminjaman ke tanggal dan slot hasil jadwal ulang
+func moveJadwalForPeminjaman(tx *gorm.DB, peminjamanID uint, reschedule models.PeminjamanReschedule) error {
+	return tx.Model(&models.Jadwal{}).
+		Where("peminjaman_id = ? AND status <> ?", peminjamanID, models.JadwalStatusCancelled).
+		Updates(map[string]interface{}{
+			"tanggal_jadwal": reschedule.TanggalPeminjaman.Format("2006-01-02"),
+			"waktu_jadwal":   reschedule.JamPeminjaman,
+			"waktu_selesai":  reschedule.JamSelesai,
+			"lab_slot_id":    reschedule.LabSlotID,
+		}).Error
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type PeminjamanLetterRepository interface {
+	GetLetterByPeminjamanID(peminjamanID uint) (models.PeminjamanLetter, error)
+	GetLetterByCode(code string) (models.PeminjamanLetter, error)
+	CreateLetter(letter models.PeminjamanLetter) (models.PeminjamanLetter, error)
+	UpdateLetter(letter models.PeminjamanLetter) (models.PeminjamanLetter, error)
+}
+
+type peminjamanLetterRepository struct {
+	db *gorm.DB
+}
+
+func NewPeminjamanLetterRepository(db *gorm.DB) PeminjamanLetterRepository {
+	return &peminjamanLetterRepository{db}
+}
+
+func (r *peminjamanLetterRepository) GetLetterByPeminjamanID(peminjamanID uint) (models.PeminjamanLetter, error) {
+	var letter models.PeminjamanLetter
+	err := r.db.Where("peminjaman_id = ?", peminjamanID).First(&letter).Error
+	return letter, err
+}
+
+func (r *peminjamanLetterRepository) GetLetterByCode(code string) (models.PeminjamanLetter, error) {
+	var letter models.PeminjamanLetter
+	err := r.db.Preload("Peminjaman.User").Preload("Peminjaman.Lab").Where("code = ?", code).First(&letter).Error
+	return letter, err
+}
+
+func (r *peminjamanLetterRepository) CreateLetter(letter models.PeminjamanLetter) (models.PeminjamanLetter, error) {
+	err := r.db.Create(&letter).Error
+	return letter, err
+}
+
+func (r *peminjamanLetterRepository) UpdateLetter(letter models.PeminjamanLetter) (models.PeminjamanLetter, error) {
+	err := r.db.Omit("Peminjaman", "LetterTemplate").Save(&letter).Error
+	return letter, err
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type PeminjamanParticipantRepository interface {
+	GetParticipantsByPeminjamanID(peminjamanID uint) ([]models.PeminjamanParticipant, error)
+	IsParticipant(peminjamanID, userID uint) (bool, error)
+}
+
+type peminjamanParticipantRepository struct {
+	db *gorm.DB
+}
+
+func NewPeminjamanParticipantRepository(db *gorm.DB) PeminjamanParticipantRepository {
+	return &peminjamanParticipantRepository{db}
+}
+
+func (r *peminjamanParticipantRepository) GetParticipantsByPeminjamanID(peminjamanID uint) ([]models.PeminjamanParticipant, error) {
+	var participants []models.PeminjamanParticipant
+	err := r.db.Preload("User").Where("peminjaman_id = ?", peminjamanID).Order("id ASC").Find(&participants).Error
+	return participants, err
+}
+
+func (r *peminjamanParticipantRepository) IsParticipant(peminjamanID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.PeminjamanParticipant{}).Where("peminjaman_id = ? AND user_id = ?", peminjamanID, userID).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PeminjamanRescheduleRepository interface {
+	GetReschedules(page, limit int, status string) ([]models.PeminjamanReschedule, int, error)
+	GetReschedulesByPeminjamanID(peminjamanID uint) ([]models.PeminjamanReschedule, error)
+	GetRescheduleByID(id uint) (models.PeminjamanReschedule, error)
+	CountPendingReschedules(peminjamanID uint) (int, error)
+	CreateReschedule(reschedule models.PeminjamanReschedule) (models.PeminjamanReschedule, error)
+	StartRescheduleApproval(reschedule models.PeminjamanReschedule, approvals []models.PeminjamanApproval) error
+	ApplyReschedule(reschedule models.PeminjamanReschedule, decidedByID uint, note string, approval *models.PeminjamanApproval, checkQuota QuotaCheck) (models.Peminjaman, error)
+	RejectReschedule(reschedule models.PeminjamanReschedule, decidedByID uint, note string, approval *models.PeminjamanApproval) error
+}
+
+type peminjamanRescheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewPeminjamanRescheduleRepository(db *gorm.DB) PeminjamanRescheduleRepository {
+	return &peminjamanRescheduleRepository{db}
+}
+
+func (r *peminjamanRescheduleRepository) GetReschedules(page, limit int, status string) ([]models.PeminjamanReschedule, int, error) {
+	var (
+		reschedules []models.PeminjamanReschedule
+		count       int64
+	)
+
+	query := r.db.Model(&models.PeminjamanReschedule{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return reschedules, int(count), err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Peminjaman.Lab").Order("id DESC").Limit(limit).Offset(offset).Find(&reschedules).Error
+	return reschedules, int(count), err
+}
+
+func (r *peminjamanRescheduleRepository) GetReschedulesByPeminjamanID(peminjamanID uint) ([]models.PeminjamanReschedule, error) {
+	var reschedules []models.PeminjamanReschedule
+	err := r.db.Where("peminjaman_id = ?", peminjamanID).Order("created_at ASC, id ASC").Find(&reschedules).Error
+	return reschedules, err
+}
+
+func (r *peminjamanRescheduleRepository) GetRescheduleByID(id uint) (models.PeminjamanReschedule, error) {
+	var reschedule models.PeminjamanReschedule
+	err := r.db.Preload("Peminjaman.Lab").Where("id = ?", id).First(&reschedule).Error
+	return reschedule, err
+}
+
+func (r *peminjamanRescheduleRepository) CountPendingReschedules(peminjamanID uint) (int, error) {
+	var count int64
+	err := r.db.Model(&models.PeminjamanReschedule{}).
+		Where("peminjaman_id = ? AND status IN ?", peminjamanID, []string{models.RescheduleStatusPending, models.RescheduleStatusWaitingApproval}).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *peminjamanRescheduleRepository) CreateReschedule(reschedule models.PeminjamanReschedule) (models.PeminjamanReschedule, error) {
+	err := r.db.Create(&reschedule).Error
+	return reschedule, err
+}
+
+// StartRescheduleApproval memulai rantai persetujuan usulan jadwal ulang. Peminjaman tidak diubah sama sekali
+// sehingga tetap berlaku pada tanggal, slot, dan status lamanya sampai tahap terakhir disetujui.
+func (r *peminjamanRescheduleRepository) StartRescheduleApproval(reschedule models.PeminjamanReschedule, approvals []models.PeminjamanApproval) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PeminjamanReschedule{}).
+			Where("id = ? AND status = ?", reschedule.ID, models.RescheduleStatusPending).
+			Update("status", models.RescheduleStatusWaitingApproval)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return helpers.ErrInvalidStatusTransition
+		}
+
+		for i := range approvals {
+			approvals[i].PeminjamanID = reschedule.PeminjamanID
+			approvals[i].PeminjamanRescheduleID = &reschedule.ID
+		}
+		return tx.Omit("Peminjaman", "PeminjamanReschedule").Create(&approvals).Error
+	})
+}
+
+// ApplyReschedule memindahkan peminjaman ke tanggal dan slot usulan lalu menandai usulan approved dalam satu
+// transaksi. Baris user dan lab dikunci seperti CreatePeminjamanIfAvailable dan peminjaman itu sendiri tidak
+// dihitung sebagai bentrok, sehingga usulan yang beririsan dengan slot lamanya tetap bisa disetujui. Jadwal
+// peminjaman yang sudah diterima ikut dipindah. approval diisi tahap terakhir rantai jadwal ulang agar
+// keputusannya tersimpan bersama perpindahan, tahap yang sudah diputuskan menghasilkan gorm.ErrRecordNotFound.
+func (r *peminjamanRescheduleRepository) ApplyReschedule(reschedule models.PeminjamanReschedule, decidedByID uint, note string, approval *models.PeminjamanApproval, checkQuota QuotaCheck) (models.Peminjaman, error) {
+	var peminjaman models.Peminjaman
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if approval != nil {
+			if err := decidePeminjamanApproval(tx, *approval); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("id = ?", reschedule.PeminjamanID).First(&peminjaman).Error; err != nil {
+			return err
+		}
+
+		if err := lockUser(tx, peminjaman.UserID); err != nil {
+			return err
+		}
+
+		var lab models.Lab
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", peminjaman.LabID).First(&lab).Error
+		if err != nil {
+			return err
+		}
+
+		available, err := slotAvailable(tx, lab, *reschedule.TanggalPeminjaman, reschedule.JamPeminjaman, reschedule.JamSelesai, peminjaman.ID)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return helpers.ErrSlotConflict
+		}
+
+		if checkQuota != nil {
+			if err := checkQuota(NewBookingQuotaRepository(tx)); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.Peminjaman{}).
+			Where("id = ? AND status IN ?", peminjaman.ID, []string{models.PeminjamanStatusRequest, models.PeminjamanStatusAccept}).
+			Updates(map[string]interface{}{
+				"tanggal_peminjaman": reschedule.TanggalPeminjaman.Format("2006-01-02"),
+				"jam_peminjaman":     reschedule.JamPeminjaman,
+				"jam_selesai":        reschedule.JamSelesai,
+				"lab_slot_id":        reschedule.LabSlotID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return helpers.ErrInvalidStatusTransition
+		}
+
+		if err := decideReschedule(tx, reschedule, models.RescheduleStatusApproved, decidedByID, note); err != nil {
+			return err
+		}
+
+		if peminjaman.Status == models.PeminjamanStatusAccept {
+			if err := moveJadwalForPeminjaman(tx, peminjaman.ID, reschedule); err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("id = ?", peminjaman.ID).First(&peminjaman).Error
+	})
+	return peminjaman, err
+}
+
+// RejectReschedule menolak usulan jadwal ulang yang masih pending atau menunggu rantai persetujuan.
+// approval diisi tahap rantai yang menolak agar keputusannya tersimpan dalam transaksi yang sama.
+func (r *peminjamanRescheduleRepository) RejectReschedule(reschedule models.PeminjamanReschedule, decidedByID uint, note string, approval *models.PeminjamanApproval) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if approval != nil {
+			if err := decidePeminjamanApproval(tx, *approval); err != nil {
+				return err
+			}
+		}
+		return decideReschedule(tx, reschedule, models.RescheduleStatusRejected, decidedByID, note)
+	})
+}
+
+// decideReschedule menyimpan keputusan akhir usulan hanya jika usulan belum diputuskan
+func decideReschedule(tx *gorm.DB, reschedule models.PeminjamanReschedule, status string, decidedByID uint, note string) error {
+	result := tx.Model(&models.PeminjamanReschedule{}).
+		Where("id = ? AND status IN ?", reschedule.ID, []string{models.RescheduleStatusPending, models.RescheduleStatusWaitingApproval}).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"decided_by_id": decidedByID,
+			"decision_note": note,
+			"decided_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return helpers.ErrInvalidStatusTransition
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/testutil"
+
+	"gorm.io/gorm"
+)
+
+// createAcceptedPeminjaman membuat peminjaman yang sudah diterima beserta jadwal lab dan satu usulan jadwal ulang
+func createAcceptedPeminjaman(t *testing.T, repo PeminjamanRepository, rescheduleRepo PeminjamanRescheduleRepository, user models.User, lab models.Lab) (models.Peminjaman, models.PeminjamanReschedule) {
+	t.Helper()
+
+	peminjaman, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+		UserID:            user.ID,
+		LabID:             lab.ID,
+		TanggalPeminjaman: testDate(3),
+		JamPeminjaman:     "08:00",
+		JamSelesai:        "11:00",
+		Status:            models.PeminjamanStatusRequest,
+	}, nil)
+	if err != nil {
+		t.Fatalf("gagal membuat peminjaman: %v", err)
+	}
+	peminjaman, err = repo.UpdatePeminjamanStatus(peminjaman, models.PeminjamanStatusLog{
+		ActorRole:  "admin",
+		FromStatus: models.PeminjamanStatusRequest,
+		ToStatus:   models.PeminjamanStatusAccept,
+	})
+	if err != nil {
+		t.Fatalf("gagal menerima peminjaman: %v", err)
+	}
+
+	reschedule, err := rescheduleRepo.CreateReschedule(models.PeminjamanReschedule{
+		PeminjamanID:      peminjaman.ID,
+		RequestedByID:     user.ID,
+		FromTanggal:       peminjaman.TanggalPeminjaman,
+		FromJamPeminjaman: peminjaman.JamPeminjaman,
+		FromJamSelesai:    peminjaman.JamSelesai,
+		TanggalPeminjaman: testDate(5),
+		JamPeminjaman:     "13:00",
+		JamSelesai:        "15:00",
+		Reason:            "bentrok kuliah",
+		Status:            models.RescheduleStatusPending,
+	})
+	if err != nil {
+		t.Fatalf("gagal membuat usulan jadwal ulang: %v", err)
+	}
+	return peminjaman, reschedule
+}
+
+func TestApplyRescheduleMovesJadwal(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+	rescheduleRepo := NewPeminjamanRescheduleRepository(db)
+
+	lab := createTestLab(t, db, "Lab Reschedule")
+	user := createTestUser(t, db, "reschedule@test.local")
+	admin := createTestUser(t, db, "admin-reschedule@test.local")
+	peminjaman, reschedule := createAcceptedPeminjaman(t, repo, rescheduleRepo, user, lab)
+
+	updated, err := rescheduleRepo.ApplyReschedule(reschedule, admin.ID, "", nil, nil)
+	if err != nil {
+		t.Fatalf("gagal menerapkan jadwal ulang: %v", err)
+	}
+	if updated.Status != models.PeminjamanStatusAccept || updated.JamPeminjaman != "13:00" {
+		t.Fatalf("peminjaman seharusnya tetap accept di slot baru: %+v", updated)
+	}
+
+	var jadwal models.Jadwal
+	if err := db.Where("peminjaman_id = ?", peminjaman.ID).First(&jadwal).Error; err != nil {
+		t.Fatalf("gagal mengambil jadwal: %v", err)
+	}
+	if jadwal.Status != models.JadwalStatusNotUsed || jadwal.WaktuJadwal != "13:00" || jadwal.WaktuSelesai != "15:00" ||
+		helpers.FormatDateToYMD(jadwal.TanggalJadwal) != helpers.FormatDateToYMD(reschedule.TanggalPeminjaman) {
+		t.Fatalf("jadwal seharusnya ikut pindah ke slot baru: %+v", jadwal)
+	}
+}
+
+// startRescheduleChain meneruskan usulan ke rantai dua tahap dan mengembalikan tahapnya
+func startRescheduleChain(t *testing.T, db *gorm.DB, rescheduleRepo PeminjamanRescheduleRepository, reschedule models.PeminjamanReschedule) []models.PeminjamanApproval {
+	t.Helper()
+
+	approvals := []models.PeminjamanApproval{
+		{StepOrder: 1, Name: "Dosen", ApproverRole: "dosen", Status: models.ApprovalStatusPending},
+		{StepOrder: 2, Name: "Kepala Lab", ApproverRole: "kepala_lab", Status: models.ApprovalStatusPending},
+	}
+	if err := rescheduleRepo.StartRescheduleApproval(reschedule, approvals); err != nil {
+		t.Fatalf("gagal memulai rantai jadwal ulang: %v", err)
+	}
+
+	approvals, err := NewApprovalRepository(db).GetRescheduleApprovals(reschedule.ID)
+	if err != nil || len(approvals) != 2 {
+		t.Fatalf("rantai jadwal ulang seharusnya berisi 2 tahap: %v %v", approvals, err)
+	}
+	return approvals
+}
+
+// decideApproval mengisi keputusan satu tahap seperti approvalUsecase
+func decideApproval(approval models.PeminjamanApproval, deciderID uint, status string) models.PeminjamanApproval {
+	now := time.Now()
+	approval.Status = status
+	approval.DecidedByID = &deciderID
+	approval.DecidedAt = &now
+	return approval
+}
+
+func TestRescheduleApprovalChainKeepsBookingUntilRejected(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+	rescheduleRepo := NewPeminjamanRescheduleRepository(db)
+	approvalRepo := NewApprovalRepository(db)
+
+	lab := createTestLab(t, db, "Lab Rantai")
+	user := createTestUser(t, db, "rantai@test.local")
+	approver := createTestUser(t, db, "approver-rantai@test.local")
+	peminjaman, reschedule := createAcceptedPeminjaman(t, repo, rescheduleRepo, user, lab)
+	approvals := startRescheduleChain(t, db, rescheduleRepo, reschedule)
+
+	var current models.Peminjaman
+	if err := db.First(&current, peminjaman.ID).Error; err != nil {
+		t.Fatalf("gagal mengambil peminjaman: %v", err)
+	}
+	if current.Status != models.PeminjamanStatusAccept || current.JamPeminjaman != "08:00" {
+		t.Fatalf("peminjaman seharusnya tetap pada jadwal lama selama rantai berjalan: %+v", current)
+	}
+	bookingApprovals, err := approvalRepo.GetPeminjamanApprovals(peminjaman.ID)
+	if err != nil || len(bookingApprovals) != 0 {
+		t.Fatalf("rantai jadwal ulang tidak boleh tercampur dengan rantai peminjaman: %v %v", bookingApprovals, err)
+	}
+	pending, err := rescheduleRepo.CountPendingReschedules(peminjaman.ID)
+	if err != nil || pending != 1 {
+		t.Fatalf("usulan yang menunggu rantai seharusnya masih dihitung pending, dapat %d %v", pending, err)
+	}
+
+	waiting, _, err := approvalRepo.GetPendingApprovalsForApprover(approver.ID, "dosen", 1, 10)
+	if err != nil || len(waiting) != 1 || waiting[0].PeminjamanReschedule == nil || waiting[0].PeminjamanReschedule.ID != reschedule.ID {
+		t.Fatalf("tahap pertama jadwal ulang seharusnya menunggu dosen: %+v %v", waiting, err)
+	}
+
+	if _, err := approvalRepo.DecidePeminjamanApproval(decideApproval(approvals[0], approver.ID, models.ApprovalStatusApproved)); err != nil {
+		t.Fatalf("gagal menyetujui tahap pertama: %v", err)
+	}
+	waiting, _, err = approvalRepo.GetPendingApprovalsForApprover(approver.ID, "kepala_lab", 1, 10)
+	if err != nil || len(waiting) != 1 || waiting[0].StepOrder != 2 {
+		t.Fatalf("tahap kedua seharusnya menunggu kepala lab: %+v %v", waiting, err)
+	}
+
+	if err := rescheduleRepo.RejectReschedule(reschedule, approver.ID, "bentrok", ptrApproval(decideApproval(approvals[1], approver.ID, models.ApprovalStatusRejected))); err != nil {
+		t.Fatalf("gagal menolak jadwal ulang: %v", err)
+	}
+
+	var rejected models.PeminjamanReschedule
+	if err := db.First(&rejected, reschedule.ID).Error; err != nil || rejected.Status != models.RescheduleStatusRejected {
+		t.Fatalf("usulan seharusnya ditolak: %+v %v", rejected, err)
+	}
+	if err := db.First(&current, peminjaman.ID).Error; err != nil {
+		t.Fatalf("gagal mengambil peminjaman: %v", err)
+	}
+	if current.Status != models.PeminjamanStatusAccept || current.JamPeminjaman != "08:00" {
+		t.Fatalf("peminjaman seharusnya tetap berlaku setelah jadwal ulang ditolak: %+v", current)
+	}
+	var jadwal models.Jadwal
+	if err := db.Where("peminjaman_id = ?", peminjaman.ID).First(&jadwal).Error; err != nil {
+		t.Fatalf("gagal mengambil jadwal: %v", err)
+	}
+	if jadwal.Status != models.JadwalStatusNotUsed || jadwal.WaktuJadwal != "08:00" {
+		t.Fatalf("jadwal lama seharusnya tetap aktif: %+v", jadwal)
+	}
+}
+
+func TestRescheduleApprovalChainFinalStepMovesBooking(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+	rescheduleRepo := NewPeminjamanRescheduleRepository(db)
+
+	lab := createTestLab(t, db, "Lab Rantai Akhir")
+	user := createTestUser(t, db, "rantai-akhir@test.local")
+	approver := createTestUser(t, db, "approver-akhir@test.local")
+	peminjaman, reschedule := createAcceptedPeminjaman(t, repo, rescheduleRepo, user, lab)
+	approvals := startRescheduleChain(t, db, rescheduleRepo, reschedule)
+
+	if _, err := NewApprovalRepository(db).DecidePeminjamanApproval(decideApproval(approvals[0], approver.ID, models.ApprovalStatusApproved)); err != nil {
+		t.Fatalf("gagal menyetujui tahap pertama: %v", err)
+	}
+	final := decideApproval(approvals[1], approver.ID, models.ApprovalStatusApproved)
+	updated, err := rescheduleRepo.ApplyReschedule(reschedule, approver.ID, "", &final, nil)
+	if err != nil {
+		t.Fatalf("gagal menerapkan jadwal ulang: %v", err)
+	}
+	if updated.Status != models.PeminjamanStatusAccept || updated.JamPeminjaman != "13:00" {
+		t.Fatalf("peminjaman seharusnya pindah ke slot baru dengan status tetap accept: %+v", updated)
+	}
+
+	var jadwal models.Jadwal
+	if err := db.Where("peminjaman_id = ?", peminjaman.ID).First(&jadwal).Error; err != nil {
+		t.Fatalf("gagal mengambil jadwal: %v", err)
+	}
+	if jadwal.Status != models.JadwalStatusNotUsed || jadwal.WaktuJadwal != "13:00" {
+		t.Fatalf("jadwal seharusnya ikut pindah setelah tahap terakhir: %+v", jadwal)
+	}
+
+	if _, err := rescheduleRepo.ApplyReschedule(reschedule, approver.ID, "", &final, nil); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("tahap yang sudah diputuskan seharusnya menghasilkan ErrRecordNotFound, dapat %v", err)
+	}
+}
+
+func ptrApproval(approval models.PeminjamanApproval) *models.PeminjamanApproval {
+	return &approval
+}
+
+func TestApplyRescheduleQuotaExceeded(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+	rescheduleRepo := NewPeminjamanRescheduleRepository(db)
+
+	lab := createTestLab(t, db, "Lab Kuota Reschedule")
+	user := createTestUser(t, db, "kuota-reschedule@test.local")
+	admin := createTestUser(t, db, "admin-kuota@test.local")
+	peminjaman, reschedule := createAcceptedPeminjaman(t, repo, rescheduleRepo, user, lab)
+
+	_, err := rescheduleRepo.ApplyReschedule(reschedule, admin.ID, "", nil, func(BookingQuotaRepository) error {
+		return helpers.ErrQuotaExceeded
+	})
+	if !errors.Is(err, helpers.ErrQuotaExceeded) {
+		t.Fatalf("error seharusnya ErrQuotaExceeded, dapat %v", err)
+	}
+
+	var current models.Peminjaman
+	if err := db.First(&current, peminjaman.ID).Error; err != nil {
+		t.Fatalf("gagal mengambil peminjaman: %v", err)
+	}
+	if current.JamPeminjaman != "08:00" {
+		t.Fatalf("peminjaman seharusnya tidak berpindah saat kuota penuh: %+v", current)
+	}
+	var pending models.PeminjamanReschedule
+	if err := db.First(&pending, reschedule.ID).Error; err != nil || pending.Status != models.RescheduleStatusPending {
+		t.Fatalf("usulan seharusnya tetap pending: %+v %v", pending, err)
+	}
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type PeminjamanSeriesRepository interface {
+	GetPeminjamanSeriesByID(id, userID uint) (models.PeminjamanSeries, error)
+	GetPeminjamansBySeriesID(seriesID uint) ([]models.Peminjaman, error)
+	CreatePeminjamanSeries(series models.PeminjamanSeries) (models.PeminjamanSeries, error)
+	DeletePeminjamanSeries(id uint) error
+}
+
+type peminjamanSeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewPeminjamanSeriesRepository(db *gorm.DB) PeminjamanSeriesRepository {
+	return &peminjamanSeriesRepository{db}
+}
+
+func (r *peminjamanSeriesRepository) GetPeminjamanSeriesByID(id, userID uint) (models.PeminjamanSeries, error) {
+	var series models.PeminjamanSeries
+	var err error
+
+	// Jika userID adalah 0, maka cari berdasarkan ID saja
+	if userID == 0 {
+		err = r.db.Where("id = ?", id).First(&series).Error
+	} else {
+		err = r.db.Where("id = ? AND user_id = ?", id, userID).First(&series).Error
+	}
+	return series, err
+}
+
+func (r *peminjamanSeriesRepository) GetPeminjamansBySeriesID(seriesID uint) ([]models.Peminjaman, error) {
+	var peminjamans []models.Peminjaman
+	err := r.db.Where("peminjaman_series_id = ?", seriesID).Order("tanggal_peminjaman ASC").Find(&peminjamans).Error
+	return peminjamans, err
+}
+
+func (r *peminjamanSeriesRepository) CreatePeminjamanSeries(series models.PeminjamanSeries) (models.PeminjamanSeries, error) {
+	err := r.db.Create(&series).Error
+	return series, err
+}
+
+// DeletePeminjamanSeries menghapus permanen series yang tidak punya satu pun pertemuan
+func (r *peminjamanSeriesRepository) DeletePeminjamanSeries(id uint) error {
+	return r.db.Unscoped().Delete(&models.PeminjamanSeries{}, id).Error
+}
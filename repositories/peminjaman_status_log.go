@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type PeminjamanStatusLogRepository interface {
+	GetStatusLogsByPeminjamanID(peminjamanID uint) ([]models.PeminjamanStatusLog, error)
+}
+
+type peminjamanStatusLogRepository struct {
+	db *gorm.DB
+}
+
+func NewPeminjamanStatusLogRepository(db *gorm.DB) PeminjamanStatusLogRepository {
+	return &peminjamanStatusLogRepository{db}
+}
+
+func (r *peminjamanStatusLogRepository) GetStatusLogsByPeminjamanID(peminjamanID uint) ([]models.PeminjamanStatusLog, error) {
+	var statusLogs []models.PeminjamanStatusLog
+	err := r.db.Where("peminjaman_id = ?", peminjamanID).Order("created_at ASC, id ASC").Find(&statusLogs).Error
+	return statusLogs, err
+}
@@ -0,0 +1,406 @@
+package repositories
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/testutil"
+)
+
+func TestCreatePeminjamanIfAvailableConcurrent(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+
+	lab := createTestLab(t, db, "Lab Konkuren")
+	tanggal := testDate(3)
+
+	const total = 8
+	users := make([]models.User, total)
+	for i := range users {
+		users[i] = createTestUser(t, db, "konkuren"+string(rune('a'+i))+"@test.local")
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		success   int
+		conflicts int
+		others    []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(user models.User) {
+			defer wg.Done()
+			<-start
+
+			_, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+				UserID:            user.ID,
+				LabID:             lab.ID,
+				TanggalPeminjaman: tanggal,
+				JamPeminjaman:     "08:00",
+				JamSelesai:        "11:00",
+				Status:            models.PeminjamanStatusRequest,
+			}, nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				success++
+			case errors.Is(err, helpers.ErrSlotConflict):
+				conflicts++
+			default:
+				others = append(others, err)
+			}
+		}(users[i])
+	}
+	close(start)
+	wg.Wait()
+
+	if len(others) > 0 {
+		t.Fatalf("error tak terduga: %v", others)
+	}
+	if success != 1 || conflicts != total-1 {
+		t.Fatalf("berhasil %d dan bentrok %d, seharusnya 1 dan %d", success, conflicts, total-1)
+	}
+
+	var count int64
+	db.Model(&models.Peminjaman{}).Where("lab_id = ?", lab.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("jumlah peminjaman tersimpan %d, seharusnya 1", count)
+	}
+}
+
+func TestCreatePeminjamanIfAvailableOverlap(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+
+	lab := createTestLab(t, db, "Lab Irisan")
+	user := createTestUser(t, db, "irisan@test.local")
+	tanggal := testDate(3)
+
+	_, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+		UserID:            user.ID,
+		LabID:             lab.ID,
+		TanggalPeminjaman: tanggal,
+		JamPeminjaman:     "09:00",
+		JamSelesai:        "12:00",
+		Status:            models.PeminjamanStatusAccept,
+	}, nil)
+	if err != nil {
+		t.Fatalf("peminjaman pertama gagal: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		jamMulai   string
+		jamSelesai string
+		conflict   bool
+	}{
+		{"irisan di awal", "08:00", "10:00", true},
+		{"irisan di akhir", "11:00", "13:00", true},
+		{"di dalam rentang", "10:00", "11:00", true},
+		{"menutupi rentang", "07:00", "13:00", true},
+		{"tepat sebelum", "07:00", "09:00", false},
+		{"tepat sesudah", "12:00", "14:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+				UserID:            user.ID,
+				LabID:             lab.ID,
+				TanggalPeminjaman: tanggal,
+				JamPeminjaman:     tt.jamMulai,
+				JamSelesai:        tt.jamSelesai,
+				Status:            models.PeminjamanStatusRequest,
+			}, nil)
+			if tt.conflict {
+				if !errors.Is(err, helpers.ErrSlotConflict) {
+					t.Fatalf("seharusnya ErrSlotConflict, didapat %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("seharusnya berhasil, didapat %v", err)
+			}
+			// Dihapus lagi agar tidak memengaruhi kasus berikutnya
+			db.Unscoped().Delete(&created)
+		})
+	}
+}
+
+func TestCreatePeminjamanIfAvailableManualJadwal(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+
+	lab := createTestLab(t, db, "Lab Jadwal")
+	user := createTestUser(t, db, "jadwal@test.local")
+	tanggal := testDate(3)
+
+	jadwal := models.Jadwal{
+		TanggalJadwal:    tanggal,
+		WaktuJadwal:      "13:00",
+		WaktuSelesai:     "15:00",
+		LabID:            &lab.ID,
+		NameLaboratorium: lab.Name,
+		Status:           models.JadwalStatusNotUsed,
+	}
+	if err := db.Create(&jadwal).Error; err != nil {
+		t.Fatalf("gagal membuat jadwal: %v", err)
+	}
+
+	newPeminjaman := func() models.Peminjaman {
+		return models.Peminjaman{
+			UserID:            user.ID,
+			LabID:             lab.ID,
+			TanggalPeminjaman: tanggal,
+			JamPeminjaman:     "14:00",
+			JamSelesai:        "16:00",
+			Status:            models.PeminjamanStatusRequest,
+		}
+	}
+
+	_, err := repo.CreatePeminjamanIfAvailable(newPeminjaman(), nil)
+	if !errors.Is(err, helpers.ErrSlotConflict) {
+		t.Fatalf("jadwal manual seharusnya bentrok, didapat %v", err)
+	}
+
+	// Jadwal yang dibatalkan tidak lagi menahan slot
+	if err := db.Model(&jadwal).Update("status", models.JadwalStatusCancelled).Error; err != nil {
+		t.Fatalf("gagal membatalkan jadwal: %v", err)
+	}
+	if _, err := repo.CreatePeminjamanIfAvailable(newPeminjaman(), nil); err != nil {
+		t.Fatalf("jadwal batal seharusnya tidak bentrok, didapat %v", err)
+	}
+}
+
+func TestUpdatePeminjamanStatusSyncsJadwal(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+
+	lab := createTestLab(t, db, "Lab Status")
+	user := createTestUser(t, db, "status@test.local")
+
+	peminjaman, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+		UserID:            user.ID,
+		LabID:             lab.ID,
+		TanggalPeminjaman: testDate(3),
+		JamPeminjaman:     "08:00",
+		JamSelesai:        "11:00",
+		Status:            models.PeminjamanStatusRequest,
+	}, nil)
+	if err != nil {
+		t.Fatalf("gagal membuat peminjaman: %v", err)
+	}
+
+	peminjaman, err = repo.UpdatePeminjamanStatus(peminjaman, models.PeminjamanStatusLog{
+		ActorRole:  "admin",
+		FromStatus: models.PeminjamanStatusRequest,
+		ToStatus:   models.PeminjamanStatusAccept,
+	})
+	if err != nil {
+		t.Fatalf("gagal menerima peminjaman: %v", err)
+	}
+
+	var jadwal models.Jadwal
+	if err := db.Where("peminjaman_id = ?", peminjaman.ID).First(&jadwal).Error; err != nil {
+		t.Fatalf("jadwal seharusnya dibuat saat peminjaman diterima: %v", err)
+	}
+	if jadwal.Status != models.JadwalStatusNotUsed || jadwal.NameLaboratorium != lab.Name || jadwal.NameUser != user.FullName {
+		t.Fatalf("jadwal tidak sesuai peminjaman: %+v", jadwal)
+	}
+
+	if _, err := repo.UpdatePeminjamanStatus(peminjaman, models.PeminjamanStatusLog{
+		ActorRole:  "admin",
+		FromStatus: models.PeminjamanStatusAccept,
+		ToStatus:   models.PeminjamanStatusCancelled,
+	}); err != nil {
+		t.Fatalf("gagal membatalkan peminjaman: %v", err)
+	}
+
+	if err := db.First(&jadwal, jadwal.ID).Error; err != nil {
+		t.Fatalf("gagal mengambil jadwal: %v", err)
+	}
+	if jadwal.Status != models.JadwalStatusCancelled {
+		t.Fatalf("jadwal seharusnya ikut dibatalkan, status %s", jadwal.Status)
+	}
+}
+
+func TestUpdatePeminjamanStatusSyncsJadwalUsage(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+
+	lab := createTestLab(t, db, "Lab Pemakaian")
+	user := createTestUser(t, db, "pemakaian@test.local")
+
+	peminjaman, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+		UserID:            user.ID,
+		LabID:             lab.ID,
+		TanggalPeminjaman: testDate(3),
+		JamPeminjaman:     "08:00",
+		JamSelesai:        "11:00",
+		Status:            models.PeminjamanStatusRequest,
+	}, nil)
+	if err != nil {
+		t.Fatalf("gagal membuat peminjaman: %v", err)
+	}
+
+	transitions := []struct {
+		to      string
+		columns []string
+		want    string
+	}{
+		{to: models.PeminjamanStatusAccept, want: models.JadwalStatusNotUsed},
+		{to: models.PeminjamanStatusInUse, columns: []string{"actual_start_at"}, want: models.JadwalStatusInUsed},
+		{to: models.PeminjamanStatusFinished, columns: []string{"actual_end_at"}, want: models.JadwalStatusFinished},
+	}
+	for _, transition := range transitions {
+		now := time.Now().Truncate(time.Second)
+		switch transition.to {
+		case models.PeminjamanStatusInUse:
+			peminjaman.ActualStartAt = &now
+		case models.PeminjamanStatusFinished:
+			peminjaman.ActualEndAt = &now
+		}
+
+		peminjaman, err = repo.UpdatePeminjamanStatus(peminjaman, models.PeminjamanStatusLog{
+			ActorRole:  "user",
+			FromStatus: peminjaman.Status,
+			ToStatus:   transition.to,
+		}, transition.columns...)
+		if err != nil {
+			t.Fatalf("gagal mengubah status ke %s: %v", transition.to, err)
+		}
+
+		var jadwal models.Jadwal
+		if err := db.Where("peminjaman_id = ?", peminjaman.ID).First(&jadwal).Error; err != nil {
+			t.Fatalf("gagal mengambil jadwal: %v", err)
+		}
+		if jadwal.Status != transition.want {
+			t.Fatalf("status %s: jadwal berstatus %s, seharusnya %s", transition.to, jadwal.Status, transition.want)
+		}
+		if transition.to == models.PeminjamanStatusInUse && jadwal.ActualStartAt == nil {
+			t.Fatalf("waktu check-in seharusnya ikut tercatat di jadwal")
+		}
+		if transition.to == models.PeminjamanStatusFinished && jadwal.ActualEndAt == nil {
+			t.Fatalf("waktu check-out seharusnya ikut tercatat di jadwal")
+		}
+	}
+}
+
+func TestCreatePeminjamanIfAvailableQuotaConcurrent(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+
+	user := createTestUser(t, db, "kuota@test.local")
+	tanggal := testDate(3)
+
+	const total = 6
+	labs := make([]models.Lab, total)
+	for i := range labs {
+		labs[i] = createTestLab(t, db, "Lab Kuota "+string(rune('A'+i)))
+	}
+
+	// Kuota satu pengajuan aktif dihitung lewat repository transaksi, seperti checkBookingQuota
+	checkQuota := func(quotaRepo BookingQuotaRepository) error {
+		active, err := quotaRepo.CountActiveRequests(user.ID, 0)
+		if err != nil {
+			return err
+		}
+		if active >= 1 {
+			return helpers.ErrQuotaExceeded
+		}
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		success  int
+		exceeded int
+		others   []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(lab models.Lab) {
+			defer wg.Done()
+			<-start
+
+			_, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+				UserID:            user.ID,
+				LabID:             lab.ID,
+				TanggalPeminjaman: tanggal,
+				JamPeminjaman:     "08:00",
+				JamSelesai:        "11:00",
+				Status:            models.PeminjamanStatusRequest,
+			}, checkQuota)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				success++
+			case errors.Is(err, helpers.ErrQuotaExceeded):
+				exceeded++
+			default:
+				others = append(others, err)
+			}
+		}(labs[i])
+	}
+	close(start)
+	wg.Wait()
+
+	if len(others) > 0 {
+		t.Fatalf("error tak terduga: %v", others)
+	}
+	if success != 1 || exceeded != total-1 {
+		t.Fatalf("berhasil %d dan melebihi kuota %d, seharusnya 1 dan %d", success, exceeded, total-1)
+	}
+}
+
+func TestExportPeminjamansUsesListFilter(t *testing.T) {
+	db := testutil.OpenTestDB(t, "repositories")
+	repo := NewPeminjamanRepository(db)
+
+	labA := createTestLab(t, db, "Lab Export A")
+	labB := createTestLab(t, db, "Lab Export B")
+	user := createTestUser(t, db, "export@test.local")
+	for _, lab := range []models.Lab{labA, labB} {
+		if _, err := repo.CreatePeminjamanIfAvailable(models.Peminjaman{
+			UserID:            user.ID,
+			LabID:             lab.ID,
+			TanggalPeminjaman: testDate(3),
+			JamPeminjaman:     "08:00",
+			JamSelesai:        "10:00",
+			Status:            models.PeminjamanStatusRequest,
+		}, nil); err != nil {
+			t.Fatalf("gagal membuat peminjaman: %v", err)
+		}
+	}
+
+	filter := dtos.PeminjamanFilter{LabID: labB.ID, User: "nim-export@", Status: models.PeminjamanStatusRequest}
+	listed, count, err := repo.GetPeminjamans(1, 10, filter)
+	if err != nil {
+		t.Fatalf("gagal mengambil daftar peminjaman: %v", err)
+	}
+
+	var exported []dtos.PeminjamanExportRow
+	err = repo.ExportPeminjamans(filter, func(row dtos.PeminjamanExportRow) error {
+		exported = append(exported, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("gagal export peminjaman: %v", err)
+	}
+
+	if count != 1 || len(exported) != 1 || exported[0].ID != listed[0].ID || exported[0].LabName != labB.Name || exported[0].NIMNIP != user.NIMNIP {
+		t.Fatalf("export seharusnya berisi peminjaman yang sama dengan daftar, daftar %d export %+v", count, exported)
+	}
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PeminjamanWaitlistRepository interface {
+	GetWaitlistsByUserID(userID uint) ([]models.PeminjamanWaitlist, error)
+	GetWaitlistByID(id, userID uint) (models.PeminjamanWaitlist, error)
+	GetWaitingEntries(labID uint, tanggal time.Time, jamMulai, jamSelesai string) ([]models.PeminjamanWaitlist, error)
+	CountWaitingAhead(waitlist models.PeminjamanWaitlist) (int, error)
+	CreateWaitlist(waitlist models.PeminjamanWaitlist) (models.PeminjamanWaitlist, error)
+	UpdateWaitlist(waitlist models.PeminjamanWaitlist) (models.PeminjamanWaitlist, error)
+	PromoteWaitlist(waitlist models.PeminjamanWaitlist, peminjaman models.Peminjaman, checkQuota QuotaCheck) (models.Peminjaman, error)
+}
+
+type peminjamanWaitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewPeminjamanWaitlistRepository(db *gorm.DB) PeminjamanWaitlistRepository {
+	return &peminjamanWaitlistRepository{db}
+}
+
+func (r *peminjamanWaitlistRepository) GetWaitlistsByUserID(userID uint) ([]models.PeminjamanWaitlist, error) {
+	var waitlists []models.PeminjamanWaitlist
+	err := r.db.Where("user_id = ?", userID).Order("tanggal_peminjaman DESC, id DESC").Find(&waitlists).Error
+	return waitlists, err
+}
+
+func (r *peminjamanWaitlistRepository) GetWaitlistByID(id, userID uint) (models.PeminjamanWaitlist, error) {
+	var waitlist models.PeminjamanWaitlist
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&waitlist).Error
+	return waitlist, err
+}
+
+// GetWaitingEntries mengambil antrean yang masih menunggu dan beririsan dengan rentang jam, urut dari yang paling awal
+func (r *peminjamanWaitlistRepository) GetWaitingEntries(labID uint, tanggal time.Time, jamMulai, jamSelesai string) ([]models.PeminjamanWaitlist, error) {
+	var waitlists []models.PeminjamanWaitlist
+	err := r.db.Where("lab_id = ? AND tanggal_peminjaman = ? AND status = ?", labID, tanggal.Format("2006-01-02"), models.WaitlistStatusWaiting).
+		Where("jam_peminjaman < ? AND jam_selesai > ?", jamSelesai, jamMulai).
+		Order("created_at ASC, id ASC").
+		Find(&waitlists).Error
+	return waitlists, err
+}
+
+func (r *peminjamanWaitlistRepository) CountWaitingAhead(waitlist models.PeminjamanWaitlist) (int, error) {
+	var count int64
+	err := r.db.Model(&models.PeminjamanWaitlist{}).
+		Where("lab_id = ? AND tanggal_peminjaman = ? AND status = ?", waitlist.LabID, waitlist.TanggalPeminjaman.Format("2006-01-02"), models.WaitlistStatusWaiting).
+		Where("jam_peminjaman < ? AND jam_selesai > ?", waitlist.JamSelesai, waitlist.JamPeminjaman).
+		Where("id < ?", waitlist.ID).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *peminjamanWaitlistRepository) CreateWaitlist(waitlist models.PeminjamanWaitlist) (models.PeminjamanWaitlist, error) {
+	err := r.db.Create(&waitlist).Error
+	return waitlist, err
+}
+
+func (r *peminjamanWaitlistRepository) UpdateWaitlist(waitlist models.PeminjamanWaitlist) (models.PeminjamanWaitlist, error) {
+	err := r.db.Save(&waitlist).Error
+	return waitlist, err
+}
+
+// PromoteWaitlist membuat peminjaman dari antrean dan menandai antrean sebagai promoted dalam satu transaksi.
+// Baris user dan lab dikunci seperti CreatePeminjamanIfAvailable agar slot tidak direbut request lain di saat
+// yang sama dan kuota user diperiksa ulang oleh checkQuota.
+func (r *peminjamanWaitlistRepository) PromoteWaitlist(waitlist models.PeminjamanWaitlist, peminjaman models.Peminjaman, checkQuota QuotaCheck) (models.Peminjaman, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, peminjaman.UserID); err != nil {
+			return err
+		}
+
+		var lab models.Lab
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", peminjaman.LabID).First(&lab).Error
+		if err != nil {
+			return err
+		}
+
+		available, err := slotAvailable(tx, lab, *peminjaman.TanggalPeminjaman, peminjaman.JamPeminjaman, peminjaman.JamSelesai, 0)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return helpers.ErrSlotConflict
+		}
+
+		if checkQuota != nil {
+			if err := checkQuota(NewBookingQuotaRepository(tx)); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&peminjaman).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.PeminjamanWaitlist{}).
+			Where("id = ? AND status = ?", waitlist.ID, models.WaitlistStatusWaiting).
+			Updates(map[string]interface{}{
+				"status":        models.WaitlistStatusPromoted,
+				"peminjaman_id": peminjaman.ID,
+				"promoted_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	return peminjaman, err
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PenaltyRepository interface {
+	GetPenalties(page, limit int, userID uint, status string) ([]models.Penalty, int, error)
+	GetPenaltiesByUserID(userID uint) ([]models.Penalty, error)
+	GetPenaltyByID(id uint) (models.Penalty, error)
+	CreatePenalty(penalty models.Penalty) (models.Penalty, error)
+	UpdatePenalty(penalty models.Penalty) (models.Penalty, error)
+	SumActivePenaltyPoints(userID uint, now time.Time) (int, error)
+}
+
+type penaltyRepository struct {
+	db *gorm.DB
+}
+
+func NewPenaltyRepository(db *gorm.DB) PenaltyRepository {
+	return &penaltyRepository{db}
+}
+
+// GetPenalties mengambil ledger penalti, userID 0 berarti semua user (admin)
+func (r *penaltyRepository) GetPenalties(page, limit int, userID uint, status string) ([]models.Penalty, int, error) {
+	var (
+		penalties []models.Penalty
+		count     int64
+	)
+
+	query := r.db.Model(&models.Penalty{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return penalties, int(count), err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&penalties).Error
+	return penalties, int(count), err
+}
+
+func (r *penaltyRepository) GetPenaltiesByUserID(userID uint) ([]models.Penalty, error) {
+	var penalties []models.Penalty
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&penalties).Error
+	return penalties, err
+}
+
+func (r *penaltyRepository) GetPenaltyByID(id uint) (models.Penalty, error) {
+	var penalty models.Penalty
+	err := r.db.Where("id = ?", id).First(&penalty).Error
+	return penalty, err
+}
+
+func (r *penaltyRepository) CreatePenalty(penalty models.Penalty) (models.Penalty, error) {
+	err := r.db.Omit("User").Create(&penalty).Error
+	return penalty, err
+}
+
+func (r *penaltyRepository) UpdatePenalty(penalty models.Penalty) (models.Penalty, error) {
+	err := r.db.Omit("User").Save(&penalty).Error
+	return penalty, err
+}
+
+// SumActivePenaltyPoints menjumlahkan poin yang masih berlaku. Poin yang sedang diajukan banding
+// tetap dihitung sampai admin memutuskan.
+func (r *penaltyRepository) SumActivePenaltyPoints(userID uint, now time.Time) (int, error) {
+	var total int64
+	err := r.db.Model(&models.Penalty{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("user_id = ? AND status IN ?", userID, []string{models.PenaltyStatusActive, models.PenaltyStatusAppealed}).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Scan(&total).Error
+	return int(total), err
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type ReturnInspectionRepository interface {
+	GetReturnInspectionByID(id uint) (models.ReturnInspection, error)
+	GetReturnInspectionsByPeminjamanID(peminjamanID uint) ([]models.ReturnInspection, error)
+	GetReturnInspectionsByEquipmentLoanID(equipmentLoanID uint) ([]models.ReturnInspection, error)
+	CreateReturnInspection(returnInspection models.ReturnInspection, damageCases []models.DamageCase) (models.ReturnInspection, error)
+}
+
+type returnInspectionRepository struct {
+	db *gorm.DB
+}
+
+func NewReturnInspectionRepository(db *gorm.DB) ReturnInspectionRepository {
+	return &returnInspectionRepository{db}
+}
+
+func (r *returnInspectionRepository) GetReturnInspectionByID(id uint) (models.ReturnInspection, error) {
+	var returnInspection models.ReturnInspection
+	err := r.db.Preload("Items.EquipmentUnit.EquipmentType").Where("id = ?", id).First(&returnInspection).Error
+	return returnInspection, err
+}
+
+func (r *returnInspectionRepository) GetReturnInspectionsByPeminjamanID(peminjamanID uint) ([]models.ReturnInspection, error) {
+	var returnInspections []models.ReturnInspection
+	err := r.db.Preload("Items.EquipmentUnit.EquipmentType").Where("peminjaman_id = ?", peminjamanID).Order("id ASC").Find(&returnInspections).Error
+	return returnInspections, err
+}
+
+func (r *returnInspectionRepository) GetReturnInspectionsByEquipmentLoanID(equipmentLoanID uint) ([]models.ReturnInspection, error) {
+	var returnInspections []models.ReturnInspection
+	err := r.db.Preload("Items.EquipmentUnit.EquipmentType").Where("equipment_loan_id = ?", equipmentLoanID).Order("id ASC").Find(&returnInspections).Error
+	return returnInspections, err
+}
+
+// CreateReturnInspection menyimpan pemeriksaan, kondisi setiap unit, dan kasus kerusakan yang
+// ditemukan dalam satu transaksi. Unit yang rusak masuk maintenance dan unit yang hilang ditandai
+// retired, unit dalam kondisi baik dibiarkan karena statusnya sudah diatur saat pengembalian.
+func (r *returnInspectionRepository) CreateReturnInspection(returnInspection models.ReturnInspection, damageCases []models.DamageCase) (models.ReturnInspection, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Peminjaman", "EquipmentLoan", "Items.EquipmentUnit").Create(&returnInspection).Error; err != nil {
+			return err
+		}
+
+		for _, item := range returnInspection.Items {
+			var status string
+			switch item.Condition {
+			case models.EquipmentConditionDamaged:
+				status = models.EquipmentUnitStatusMaintenance
+			case models.EquipmentConditionMissing:
+				status = models.EquipmentUnitStatusRetired
+			default:
+				continue
+			}
+			err := tx.Model(&models.EquipmentUnit{}).Where("id = ?", item.EquipmentUnitID).
+				Updates(map[string]interface{}{"condition": item.Condition, "status": status}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := range damageCases {
+			damageCases[i].ReturnInspectionID = returnInspection.ID
+		}
+		if len(damageCases) > 0 {
+			return tx.Omit("ReturnInspection", "User", "Lab", "EquipmentUnit").Create(&damageCases).Error
+		}
+		return nil
+	})
+	return returnInspection, err
+}
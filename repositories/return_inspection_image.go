@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+type ReturnInspectionImageRepository interface {
+	GetAllReturnInspectionImageByID(id uint) ([]models.ReturnInspectionImage, error)
+	CreateReturnInspectionImage(returnInspectionImage models.ReturnInspectionImage) (models.ReturnInspectionImage, error)
+	DeleteReturnInspectionImage(id uint) error
+}
+
+type returnInspectionImageRepository struct {
+	db *gorm.DB
+}
+
+func NewReturnInspectionImageRepository(db *gorm.DB) ReturnInspectionImageRepository {
+	return &returnInspectionImageRepository{db}
+}
+
+func (r *returnInspectionImageRepository) GetAllReturnInspectionImageByID(id uint) ([]models.ReturnInspectionImage, error) {
+	var returnInspectionImages []models.ReturnInspectionImage
+	err := r.db.Where("return_inspection_id = ?", id).Find(&returnInspectionImages).Error
+	return returnInspectionImages, err
+}
+
+func (r *returnInspectionImageRepository) CreateReturnInspectionImage(returnInspectionImage models.ReturnInspectionImage) (models.ReturnInspectionImage, error) {
+	err := r.db.Create(&returnInspectionImage).Error
+	return returnInspectionImage, err
+}
+
+func (r *returnInspectionImageRepository) DeleteReturnInspectionImage(id uint) error {
+	var returnInspectionImage models.ReturnInspectionImage
+	err := r.db.Unscoped().Where("return_inspection_id = ?", id).Delete(&returnInspectionImage).Error
+	return err
+}
@@ -9,6 +9,7 @@ import (
 type SuratRekomendasiImageRepository interface {
 	GetAllSuratRekomendasiImages(page, limit int) ([]models.SuratRekomendasiImage, int, error)
 	GetAllSuratRekomendasiImageByID(id uint) ([]models.SuratRekomendasiImage, error)
+	GetSuratRekomendasiImagesByPeminjamanIDs(peminjamanIDs []uint) ([]models.SuratRekomendasiImage, error)
 	GetSuratRekomendasiImageByID(id uint) (models.SuratRekomendasiImage, error)
 	CreateSuratRekomendasiImage(suratrekomendasiImage models.SuratRekomendasiImage) (models.SuratRekomendasiImage, error)
 	UpdateSuratRekomendasiImage(suratrekomendasiImage models.SuratRekomendasiImage) (models.SuratRekomendasiImage, error)
@@ -69,3 +70,13 @@ func (r *suratRekomendasiImageRepository) DeleteSuratRekomendasiImage(id uint) e
 	err := r.db.Unscoped().Where("peminjaman_id = ?", id).Delete(&suratRekomendasiImage).Error
 	return err
 }
+
+// GetSuratRekomendasiImagesByPeminjamanIDs mengambil surat rekomendasi beberapa peminjaman dalam satu query
+func (r *suratRekomendasiImageRepository) GetSuratRekomendasiImagesByPeminjamanIDs(peminjamanIDs []uint) ([]models.SuratRekomendasiImage, error) {
+	var suratRekomendasiImages []models.SuratRekomendasiImage
+	if len(peminjamanIDs) == 0 {
+		return suratRekomendasiImages, nil
+	}
+	err := r.db.Where("peminjaman_id IN ?", peminjamanIDs).Order("id ASC").Find(&suratRekomendasiImages).Error
+	return suratRekomendasiImages, err
+}
@@ -12,10 +12,13 @@ type UserRepository interface {
 	UserGetDetail(id uint, isDeleted bool) (models.User, error)
 	UserGetById(id uint) (models.User, error)
 	UserGetById2(id uint) (models.User, error)
+	UserGetByIDs(ids []uint) ([]models.User, error)
 	UserGetByEmail(email string) (models.User, error)
+	UserGetByRole(role string) ([]models.User, error)
 	ExamUserGetByEmail(email string) (models.ExamUser, error)
 	UserGetByEmail2(id uint, email string) (models.User, error)
 	UserGetByEmail3(email string) (models.User, error)
+	UserGetByNIMNIP(nimnip string) (models.User, error)
 	UserCreate(user models.User) (models.User, error)
 	ExamUserCreate(user models.ExamUser) (models.ExamUser, error)
 	UserCreate2(user models.User, isActive bool) (models.User, error)
@@ -39,14 +42,14 @@ func (r *userRepository) UserGetAll(page, limit int, search string) ([]models.Us
 
 	offset := (page - 1) * limit
 
-	err = r.db.Unscoped().Where("role = 'user' AND full_name LIKE ? OR role = 'user' AND email LIKE ?", "%"+search+"%", "%"+search+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&users).Error
+	err = r.db.Unscoped().Where("role <> 'admin' AND full_name LIKE ? OR role <> 'admin' AND email LIKE ?", "%"+search+"%", "%"+search+"%").Order("id DESC").Limit(limit).Offset(offset).Find(&users).Error
 
 	return users, int(count), err
 }
 
 func (r *userRepository) UserGetDetail(id uint, isDeleted bool) (models.User, error) {
 	var user models.User
-	err := r.db.Unscoped().Where("id = ? AND role = 'user'", id).First(&user).Error
+	err := r.db.Unscoped().Where("id = ? AND role <> 'admin'", id).First(&user).Error
 	if isDeleted {
 		user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
 		err = r.db.Where("id = ?", id).Save(user).Error
@@ -65,7 +68,7 @@ func (r *userRepository) UserGetById(id uint) (models.User, error) {
 
 func (r *userRepository) UserGetById2(id uint) (models.User, error) {
 	var user models.User
-	err := r.db.Unscoped().Where("id = ? AND role = 'user'", id).First(&user).Error
+	err := r.db.Unscoped().Where("id = ? AND role <> 'admin'", id).First(&user).Error
 	return user, err
 }
 
@@ -75,6 +78,12 @@ func (r *userRepository) UserGetByEmail(email string) (models.User, error) {
 	return user, err
 }
 
+func (r *userRepository) UserGetByRole(role string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("role = ?", role).Find(&users).Error
+	return users, err
+}
+
 func (r *userRepository) ExamUserGetByEmail(email string) (models.ExamUser, error) {
 	var user models.ExamUser
 	err := r.db.Where("email = ?", email).First(&user).Error
@@ -87,6 +96,12 @@ func (r *userRepository) UserGetByEmail3(email string) (models.User, error) {
 	return user, err
 }
 
+func (r *userRepository) UserGetByNIMNIP(nimnip string) (models.User, error) {
+	var user models.User
+	err := r.db.Where(&models.User{NIMNIP: nimnip}).First(&user).Error
+	return user, err
+}
+
 func (r *userRepository) UserGetByEmail2(id uint, email string) (models.User, error) {
 	var user models.User
 	err := r.db.Where("id != ? AND email = ?", id, email).First(&user).Error
@@ -132,3 +147,12 @@ func (r *userRepository) DeleteUser(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
 
+// UserGetByIDs mengambil beberapa user non-admin sekaligus dengan aturan yang sama seperti UserGetById2
+func (r *userRepository) UserGetByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Unscoped().Where("id IN ? AND role <> 'admin'", ids).Find(&users).Error
+	return users, err
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"log"
 	"net/http"
+	"sistem_peminjaman_be/configs"
 	"sistem_peminjaman_be/controllers"
 	"sistem_peminjaman_be/middlewares"
 	"sistem_peminjaman_be/repositories"
@@ -34,9 +35,20 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	beritaAcaraImageRepository := repositories.NewBeritaAcaraImageRepository(db)
 	jadwalRepository := repositories.NewJadwalRepository(db)
 	historySeenLabRepository := repositories.NewHistorySeenLabRepository(db)
-	suratRekomendasiImageRepository := repositories.NewSuratRekomendasiImageRepository(db)
 	peminjamanRepository := repositories.NewPeminjamanRepository(db)
 	dashboardRepository := repositories.NewDashboardRepository(db)
+	labSlotRepository := repositories.NewLabSlotRepository(db)
+	peminjamanStatusLogRepository := repositories.NewPeminjamanStatusLogRepository(db)
+	approvalRepository := repositories.NewApprovalRepository(db)
+	damageCaseRepository := repositories.NewDamageCaseRepository(db)
+	penaltyRepository := repositories.NewPenaltyRepository(db)
+	bookingQuotaRepository := repositories.NewBookingQuotaRepository(db)
+	labClosureRepository := repositories.NewLabClosureRepository(db)
+	peminjamanParticipantRepository := repositories.NewPeminjamanParticipantRepository(db)
+	peminjamanRescheduleRepository := repositories.NewPeminjamanRescheduleRepository(db)
+	letterTemplateRepository := repositories.NewLetterTemplateRepository(db)
+	peminjamanLetterRepository := repositories.NewPeminjamanLetterRepository(db)
+	calendarFeedRepository := repositories.NewCalendarFeedRepository(db)
 
 	templateMessageUsecase := usecases.NewTemplateMessageUsecase(templateMessageRepository)
 	templateMessageController := controllers.NewTemplateMessageController(templateMessageUsecase)
@@ -56,15 +68,53 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	historySeenLabUsecase := usecases.NewHistorySeenLabUsecase(historySeenLabRepository, labRepository, labImageRepository)
 	historySeenLabController := controllers.NewHistorySeenLabController(historySeenLabUsecase)
 
-	labUsecase := usecases.NewLabUsecase(labRepository, labImageRepository, historySearchRepository, userRepository, historySeenLabUsecase)
+	labUsecase := usecases.NewLabUsecase(labRepository, labImageRepository, historySearchRepository, userRepository, historySeenLabUsecase, peminjamanRepository, jadwalRepository, labSlotRepository, labClosureRepository)
 	labController := controllers.NewLabController(labUsecase)
 
-	jadwalUsecase := usecases.NewJadwalUsecase(jadwalRepository, beritaAcaraImageRepository, userRepository)
+	labSlotUsecase := usecases.NewLabSlotUsecase(labSlotRepository, labRepository)
+	labSlotController := controllers.NewLabSlotController(labSlotUsecase)
+
+	jadwalUsecase := usecases.NewJadwalUsecase(jadwalRepository, beritaAcaraImageRepository, userRepository, labRepository, labSlotRepository, labClosureRepository)
 	jadwalController := controllers.NewJadwalController(jadwalUsecase)
 
-	peminjamanUsecase := usecases.NewPeminjamanUsecase(peminjamanRepository, suratRekomendasiImageRepository, labRepository, labImageRepository, userRepository)
+	penaltyPolicy := newPenaltyPolicy()
+
+	peminjamanUsecase := newPeminjamanUsecase(db, penaltyPolicy)
 	peminjamanController := controllers.NewPeminjamanController(peminjamanUsecase)
 
+	approvalUsecase := usecases.NewApprovalUsecase(approvalRepository, peminjamanRepository, peminjamanRescheduleRepository, labRepository, userRepository, templateMessageRepository, notificationRepository, peminjamanUsecase)
+	approvalController := controllers.NewApprovalController(approvalUsecase)
+
+	inventoryRepository := repositories.NewInventoryRepository(db)
+	equipmentLoanRepository := repositories.NewEquipmentLoanRepository(db)
+	inventoryUsecase := usecases.NewInventoryUsecase(inventoryRepository, labRepository)
+	inventoryController := controllers.NewInventoryController(inventoryUsecase)
+	equipmentLoanUsecase := usecases.NewEquipmentLoanUsecase(equipmentLoanRepository, inventoryRepository, labRepository, userRepository, templateMessageRepository, notificationRepository, damageCaseRepository, penaltyRepository, penaltyPolicy)
+	equipmentLoanController := controllers.NewEquipmentLoanController(equipmentLoanUsecase)
+
+	returnInspectionRepository := repositories.NewReturnInspectionRepository(db)
+	returnInspectionImageRepository := repositories.NewReturnInspectionImageRepository(db)
+	inspectionUsecase := usecases.NewInspectionUsecase(returnInspectionRepository, returnInspectionImageRepository, damageCaseRepository, peminjamanRepository, equipmentLoanRepository, labRepository, templateMessageRepository, notificationRepository, penaltyRepository, penaltyPolicy)
+	inspectionController := controllers.NewInspectionController(inspectionUsecase)
+
+	penaltyUsecase := usecases.NewPenaltyUsecase(penaltyRepository, userRepository, templateMessageRepository, notificationRepository, penaltyPolicy)
+	penaltyController := controllers.NewPenaltyController(penaltyUsecase)
+
+	bookingQuotaUsecase := usecases.NewBookingQuotaUsecase(bookingQuotaRepository, labRepository, userRepository)
+	bookingQuotaController := controllers.NewBookingQuotaController(bookingQuotaUsecase)
+
+	labClosureUsecase := usecases.NewLabClosureUsecase(labClosureRepository, labRepository)
+	labClosureController := controllers.NewLabClosureController(labClosureUsecase)
+
+	letterUsecase := usecases.NewLetterUsecase(letterTemplateRepository, peminjamanLetterRepository, peminjamanRepository, peminjamanParticipantRepository, peminjamanStatusLogRepository, userRepository, labRepository, configs.EnvAppBaseURL())
+	letterController := controllers.NewLetterController(letterUsecase)
+
+	calendarFeedUsecase := usecases.NewCalendarFeedUsecase(calendarFeedRepository, peminjamanRepository, jadwalRepository, labRepository, configs.EnvAppBaseURL())
+	calendarFeedController := controllers.NewCalendarFeedController(calendarFeedUsecase)
+
+	exportUsecase := usecases.NewExportUsecase(peminjamanRepository, jadwalRepository)
+	exportController := controllers.NewExportController(exportUsecase)
+
 	dashboardUsecase := usecases.NewDashboardUsecase(dashboardRepository, userRepository, peminjamanRepository, jadwalRepository, labRepository)
 	dashboardController := controllers.NewDashboardController(dashboardUsecase)
 
@@ -88,7 +138,7 @@ func Init(e *echo.Echo, db *gorm.DB) {
 	api.POST("/register/admin", userController.AdminRegister)
 
 	user := api.Group("/user")
-	user.Use(middlewares.JWTMiddleware, middlewares.RoleMiddleware("user"))
+	user.Use(middlewares.JWTMiddleware, middlewares.RoleMiddleware("user", "dosen", "kepala_lab"))
 
 	// user account
 	user.Any("", userController.UserCredential)
@@ -137,24 +187,121 @@ func Init(e *echo.Echo, db *gorm.DB) {
 
 	public.GET("/lab", labController.GetAllLabs)
 	public.GET("/lab/:id", labController.GetLabByID)
+	public.GET("/lab/:id/availability", labController.GetLabAvailability)
 	admin.PUT("/lab/:id", labController.UpdateLab)
 	admin.POST("/lab", labController.CreateLab)
 	admin.DELETE("/lab/:id", labController.DeleteLab)
 
+	public.GET("/lab/:id/slots", labSlotController.GetLabSlots)
+	public.GET("/verify/:code", letterController.VerifyLetter)
+	public.GET("/calendar/user/:token", calendarFeedController.GetUserCalendar)
+	public.GET("/calendar/lab/:id", calendarFeedController.GetLabCalendar)
+	admin.GET("/lab/:id/slots", labSlotController.GetLabSlots)
+	admin.POST("/lab/:id/slots", labSlotController.CreateLabSlot)
+	admin.PUT("/lab/:id/slots/:slotId", labSlotController.UpdateLabSlot)
+	admin.DELETE("/lab/:id/slots/:slotId", labSlotController.DeleteLabSlot)
+
 	public.GET("/jadwal", jadwalController.GetAllJadwals)
 	public.GET("/jadwal/:id", jadwalController.GetJadwalByID)
 	admin.PUT("/jadwal/:id", jadwalController.UpdateJadwal)
 	admin.POST("/jadwal", jadwalController.CreateJadwal)
 	admin.DELETE("/jadwal/:id", jadwalController.DeleteJadwal)
+	admin.GET("/jadwal/export", exportController.ExportJadwals)
 
 	user.GET("/peminjaman", peminjamanController.GetAllPeminjamans)
 	user.GET("/peminjaman/:id", peminjamanController.GetPeminjamanByID)
 	admin.GET("/peminjaman", peminjamanController.GetPeminjamansByAdmin)
+	admin.GET("/peminjaman/export", exportController.ExportPeminjamans)
 	public.GET("/peminjamans", peminjamanController.GetPeminjamansByAdmin)
 	public.GET("/peminjaman/:id", peminjamanController.GetPeminjamanByID)
 	public.GET("/admin/peminjaman/:id", peminjamanController.AdminGetPeminjamanByID)
 	//admin.PUT("/peminjaman/admin/:id", peminjamanController.AdminUpdatePeminjaman)
 	admin.PUT("/peminjaman/:id", peminjamanController.UpdatePeminjaman)
+	admin.POST("/peminjaman/bulk", peminjamanController.BulkUpdatePeminjaman)
+	admin.GET("/peminjaman/reschedules", peminjamanController.GetReschedules)
+	admin.PUT("/peminjaman/reschedules/:id", peminjamanController.DecideReschedule)
 	user.POST("/peminjaman", peminjamanController.CreatePeminjaman)
+	user.POST("/peminjaman/:id/cancel", peminjamanController.CancelPeminjaman)
+	user.POST("/peminjaman/:id/reschedule", peminjamanController.RequestReschedule)
+	user.GET("/peminjaman/waitlist", peminjamanController.GetWaitlists)
+	user.POST("/peminjaman/waitlist", peminjamanController.JoinWaitlist)
+	user.DELETE("/peminjaman/waitlist/:id", peminjamanController.LeaveWaitlist)
+	user.GET("/peminjaman/series/:id", peminjamanController.GetPeminjamanSeriesByID)
+	admin.GET("/peminjaman/series/:id", peminjamanController.AdminGetPeminjamanSeriesByID)
+	admin.PUT("/peminjaman/series/:id", peminjamanController.UpdatePeminjamanSeries)
+
+	// check-in QR, kiosk atau asisten lab (admin) juga boleh memanggil check-in/check-out
+	user.GET("/peminjaman/:id/qrcode", peminjamanController.GetPeminjamanQRCode)
+	user.GET("/peminjaman/:id/letter.pdf", letterController.GetPeminjamanLetter)
+
+	user.GET("/calendar-feed", calendarFeedController.GetCalendarFeed)
+	user.POST("/calendar-feed/rotate", calendarFeedController.RotateCalendarFeed)
+	user.GET("/jadwal/:id/qrcode", peminjamanController.GetJadwalQRCode)
+	admin.GET("/peminjaman/:id/qrcode", peminjamanController.GetPeminjamanQRCode)
+	admin.GET("/jadwal/:id/qrcode", peminjamanController.GetJadwalQRCode)
+	api.POST("/user/checkin", peminjamanController.Checkin, middlewares.JWTMiddleware, middlewares.RoleMiddleware("user", "dosen", "kepala_lab", "admin"))
+	api.POST("/user/checkout", peminjamanController.Checkout, middlewares.JWTMiddleware, middlewares.RoleMiddleware("user", "dosen", "kepala_lab", "admin"))
+
+	// APPROVER
+	approver := api.Group("/approver")
+	approver.Use(middlewares.JWTMiddleware, middlewares.RoleMiddleware("dosen", "kepala_lab", "admin"))
+
+	admin.GET("/lab/:id/approval-steps", approvalController.GetLabApprovalSteps)
+	admin.PUT("/lab/:id/approval-steps", approvalController.UpdateLabApprovalSteps)
+	approver.GET("/peminjaman", approvalController.GetPendingApprovals)
+	approver.POST("/peminjaman/:id/decision", approvalController.DecidePeminjamanApproval)
+	approver.POST("/reschedules/:id/decision", approvalController.DecideRescheduleApproval)
+
+	// INVENTORY
+	admin.GET("/inventory/types", inventoryController.GetEquipmentTypes)
+	admin.POST("/inventory/types", inventoryController.CreateEquipmentType)
+	admin.PUT("/inventory/types/:id", inventoryController.UpdateEquipmentType)
+	admin.DELETE("/inventory/types/:id", inventoryController.DeleteEquipmentType)
+	admin.GET("/inventory/units", inventoryController.GetEquipmentUnits)
+	admin.POST("/inventory/units", inventoryController.CreateEquipmentUnit)
+	admin.PUT("/inventory/units/:id", inventoryController.UpdateEquipmentUnit)
+	admin.DELETE("/inventory/units/:id", inventoryController.DeleteEquipmentUnit)
+	admin.GET("/inventory/stock", inventoryController.GetEquipmentStock)
+	admin.GET("/inventory/loans", equipmentLoanController.AdminGetEquipmentLoans)
+	admin.GET("/inventory/loans/:id", equipmentLoanController.AdminGetEquipmentLoanByID)
+	admin.POST("/inventory/loans/:id/decision", equipmentLoanController.DecideEquipmentLoan)
+	admin.POST("/inventory/loans/:id/handover", equipmentLoanController.HandOverEquipmentLoan)
+	admin.POST("/inventory/loans/:id/return", equipmentLoanController.ReturnEquipmentLoan)
+	admin.POST("/inventory/loans/:id/inspection", inspectionController.InspectEquipmentLoan)
+	admin.GET("/inventory/loans/:id/inspection", inspectionController.GetEquipmentLoanInspections)
+	admin.POST("/peminjaman/:id/inspection", inspectionController.InspectPeminjaman)
+	admin.GET("/peminjaman/:id/inspection", inspectionController.GetPeminjamanInspections)
+	admin.GET("/damage-cases", inspectionController.AdminGetDamageCases)
+	admin.GET("/damage-cases/report", inspectionController.GetDamageCaseReport)
+	admin.PUT("/damage-cases/:id", inspectionController.ResolveDamageCase)
+	admin.GET("/penalties", penaltyController.AdminGetPenalties)
+	admin.GET("/penalties/users/:id", penaltyController.AdminGetUserPenalties)
+	admin.POST("/penalties", penaltyController.CreatePenalty)
+	admin.POST("/penalties/:id/waive", penaltyController.WaivePenalty)
+	admin.PUT("/penalties/:id/appeal", penaltyController.DecidePenaltyAppeal)
+	admin.GET("/quotas", bookingQuotaController.GetBookingQuotas)
+	admin.POST("/quotas", bookingQuotaController.CreateBookingQuota)
+	admin.PUT("/quotas/:id", bookingQuotaController.UpdateBookingQuota)
+	admin.DELETE("/quotas/:id", bookingQuotaController.DeleteBookingQuota)
+	admin.GET("/closures", labClosureController.GetLabClosures)
+	admin.POST("/closures", labClosureController.CreateLabClosure)
+	admin.POST("/closures/import", labClosureController.ImportLabClosures)
+	admin.PUT("/closures/:id", labClosureController.UpdateLabClosure)
+	admin.DELETE("/closures/:id", labClosureController.DeleteLabClosure)
+
+	admin.GET("/letter-templates", letterController.GetLetterTemplates)
+	admin.POST("/letter-templates", letterController.CreateLetterTemplate)
+	admin.PUT("/letter-templates/:id", letterController.UpdateLetterTemplate)
+	admin.DELETE("/letter-templates/:id", letterController.DeleteLetterTemplate)
+
+	user.GET("/inventory", inventoryController.GetCatalogue)
+	user.GET("/inventory/loans", equipmentLoanController.GetEquipmentLoans)
+	user.POST("/inventory/loans", equipmentLoanController.CreateEquipmentLoan)
+	user.GET("/inventory/loans/:id", equipmentLoanController.GetEquipmentLoanByID)
+	user.POST("/inventory/loans/:id/cancel", equipmentLoanController.CancelEquipmentLoan)
+	user.GET("/damage-cases", inspectionController.GetDamageCases)
+	user.GET("/penalties", penaltyController.GetPenalties)
+	user.POST("/penalties/:id/appeal", penaltyController.AppealPenalty)
+	user.GET("/quota", bookingQuotaController.GetUserQuota)
 
 }
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"sistem_peminjaman_be/configs"
+	"sistem_peminjaman_be/repositories"
+	"sistem_peminjaman_be/scheduler"
+	"sistem_peminjaman_be/usecases"
+
+	"gorm.io/gorm"
+)
+
+// InitScheduler menyusun job berkala. Dipanggil dari main baik saat berjalan bersama API
+// maupun saat dijalankan sebagai worker terpisah.
+func InitScheduler(db *gorm.DB, clock scheduler.Clock) *scheduler.Scheduler {
+	peminjamanRepository := repositories.NewPeminjamanRepository(db)
+	jadwalRepository := repositories.NewJadwalRepository(db)
+	jobRunRepository := repositories.NewJobRunRepository(db)
+
+	peminjamanUsecase := newPeminjamanUsecase(db, newPenaltyPolicy())
+	schedulerUsecase := usecases.NewSchedulerUsecase(peminjamanRepository, jadwalRepository, peminjamanUsecase, configs.EnvCheckinGracePeriod())
+
+	interval := configs.EnvSchedulerInterval()
+
+	return scheduler.New(clock, jobRunRepository,
+		scheduler.Job{Name: "expire_stale_requests", Interval: interval, Run: schedulerUsecase.ExpireStaleRequests},
+		scheduler.Job{Name: "advance_peminjaman_status", Interval: interval, Run: schedulerUsecase.AdvancePeminjamanStatus},
+		scheduler.Job{Name: "advance_jadwal_status", Interval: interval, Run: schedulerUsecase.AdvanceJadwalStatus},
+	)
+}
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"sistem_peminjaman_be/configs"
+	"sistem_peminjaman_be/repositories"
+	"sistem_peminjaman_be/usecases"
+
+	"gorm.io/gorm"
+)
+
+// newPenaltyPolicy membaca aturan poin penalti dari environment
+func newPenaltyPolicy() usecases.PenaltyPolicy {
+	return usecases.PenaltyPolicy{
+		Threshold:        configs.EnvPenaltySuspendThreshold(),
+		Expiry:           configs.EnvPenaltyExpiry(),
+		NoShowPoints:     configs.EnvPenaltyNoShowPoints(),
+		LateReturnPoints: configs.EnvPenaltyLateReturnPoints(),
+		DamagePoints:     configs.EnvPenaltyDamagePoints(),
+		MissingPoints:    configs.EnvPenaltyMissingPoints(),
+	}
+}
+
+// newPeminjamanUsecase menyusun usecase peminjaman beserta repository dan aturannya. Dipakai bersama
+// oleh Init dan InitScheduler agar API dan worker selalu memakai susunan yang sama.
+func newPeminjamanUsecase(db *gorm.DB, penaltyPolicy usecases.PenaltyPolicy) usecases.PeminjamanUsecase {
+	return usecases.NewPeminjamanUsecase(
+		repositories.NewPeminjamanRepository(db),
+		repositories.NewSuratRekomendasiImageRepository(db),
+		repositories.NewLabRepository(db),
+		repositories.NewLabImageRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewLabSlotRepository(db),
+		repositories.NewPeminjamanSeriesRepository(db),
+		repositories.NewPeminjamanStatusLogRepository(db),
+		repositories.NewTemplateMessageRepository(db),
+		repositories.NewNotificationRepository(db),
+		repositories.NewApprovalRepository(db),
+		repositories.NewPeminjamanWaitlistRepository(db),
+		repositories.NewJadwalRepository(db),
+		repositories.NewDamageCaseRepository(db),
+		repositories.NewPenaltyRepository(db),
+		repositories.NewBookingQuotaRepository(db),
+		repositories.NewLabClosureRepository(db),
+		repositories.NewPeminjamanParticipantRepository(db),
+		repositories.NewPeminjamanRescheduleRepository(db),
+		usecases.PeminjamanCancelPolicy{
+			Cutoff:     configs.EnvPeminjamanCancelCutoff(),
+			LateWindow: configs.EnvPeminjamanLateCancelWindow(),
+		},
+		usecases.CheckinPolicy{
+			Secret:      configs.EnvCheckinSecret(),
+			EarlyWindow: configs.EnvCheckinEarlyWindow(),
+			GracePeriod: configs.EnvCheckinGracePeriod(),
+		},
+		penaltyPolicy,
+	)
+}
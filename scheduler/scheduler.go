@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"sync"
+	"time"
+)
+
+// Clock menyediakan waktu sekarang, bisa diganti saat pengujian agar job berjalan pada waktu tertentu
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock memakai waktu sistem
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// ClockFunc mengubah fungsi biasa menjadi Clock
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// Job adalah pekerjaan berkala. Run menerima waktu dari Clock dan mengembalikan jumlah data yang diproses.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) (int, error)
+}
+
+type Scheduler struct {
+	clock      Clock
+	jobRunRepo repositories.JobRunRepository
+	jobs       []Job
+}
+
+func New(clock Clock, jobRunRepo repositories.JobRunRepository, jobs ...Job) *Scheduler {
+	return &Scheduler{clock, jobRunRepo, jobs}
+}
+
+// Start menjalankan setiap job sekali di awal lalu mengulanginya sesuai Interval sampai ctx dihentikan.
+// Start menunggu semua job selesai sebelum kembali.
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// RunAll menjalankan semua job satu kali secara berurutan
+func (s *Scheduler) RunAll() {
+	for _, job := range s.jobs {
+		s.RunJob(job)
+	}
+}
+
+// RunJob menjalankan satu job dan mencatat hasilnya ke tabel job_runs. Job dikunci per nama di database
+// sehingga beberapa worker boleh berjalan bersamaan, worker yang tidak mendapat lock melewati putaran itu.
+func (s *Scheduler) RunJob(job Job) models.JobRun {
+	jobRun := models.JobRun{JobName: job.Name}
+
+	locked, err := s.jobRunRepo.WithJobLock(job.Name, func() {
+		jobRun = s.runJob(job)
+	})
+	if err != nil {
+		log.Printf("scheduler: gagal mengambil lock job %s: %v", job.Name, err)
+		return jobRun
+	}
+	if !locked {
+		log.Printf("scheduler: job %s dilewati karena sedang berjalan di worker lain", job.Name)
+	}
+
+	return jobRun
+}
+
+func (s *Scheduler) runJob(job Job) models.JobRun {
+	startedAt := s.clock.Now()
+	affected, err := job.Run(startedAt)
+
+	jobRun := models.JobRun{
+		JobName:    job.Name,
+		StartedAt:  startedAt,
+		FinishedAt: s.clock.Now(),
+		Status:     models.JobRunStatusSuccess,
+		Affected:   affected,
+	}
+	if err != nil {
+		jobRun.Status = models.JobRunStatusFailed
+		jobRun.Message = err.Error()
+		log.Printf("scheduler: job %s gagal: %v", job.Name, err)
+	}
+
+	jobRun, err = s.jobRunRepo.CreateJobRun(jobRun)
+	if err != nil {
+		log.Printf("scheduler: gagal mencatat job %s: %v", job.Name, err)
+	}
+
+	return jobRun
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	s.RunJob(job)
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RunJob(job)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"sistem_peminjaman_be/routes"
+	"sistem_peminjaman_be/scheduler"
+	"sistem_peminjaman_be/testutil"
+
+	"gorm.io/gorm"
+)
+
+// fixedClock adalah clock yang waktunya diatur manual oleh test
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestSchedulerTransitions(t *testing.T) {
+	db := testutil.OpenTestDB(t, "scheduler")
+	t.Setenv("CHECKIN_GRACE_MINUTES", "15")
+
+	user := models.User{FullName: "Scheduler", Email: "scheduler@test.local", Role: models.UserRoleUser}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("gagal membuat user: %v", err)
+	}
+	lab := models.Lab{Name: "Lab Scheduler"}
+	if err := db.Create(&lab).Error; err != nil {
+		t.Fatalf("gagal membuat lab: %v", err)
+	}
+
+	tanggal := time.Date(2030, 1, 10, 0, 0, 0, 0, time.Local)
+	createPeminjaman := func(status string) models.Peminjaman {
+		peminjaman := models.Peminjaman{
+			UserID:            user.ID,
+			LabID:             lab.ID,
+			TanggalPeminjaman: &tanggal,
+			JamPeminjaman:     "08:00",
+			JamSelesai:        "11:00",
+			Status:            status,
+		}
+		if err := db.Create(&peminjaman).Error; err != nil {
+			t.Fatalf("gagal membuat peminjaman: %v", err)
+		}
+		return peminjaman
+	}
+
+	createJadwal := func(peminjaman models.Peminjaman, status string) models.Jadwal {
+		jadwal := models.Jadwal{
+			TanggalJadwal: &tanggal,
+			WaktuJadwal:   peminjaman.JamPeminjaman,
+			WaktuSelesai:  peminjaman.JamSelesai,
+			LabID:         &lab.ID,
+			UserID:        &user.ID,
+			PeminjamanID:  &peminjaman.ID,
+			Status:        status,
+		}
+		if err := db.Omit("Lab", "User", "Peminjaman").Create(&jadwal).Error; err != nil {
+			t.Fatalf("gagal membuat jadwal: %v", err)
+		}
+		return jadwal
+	}
+
+	request := createPeminjaman(models.PeminjamanStatusRequest)
+	accepted := createPeminjaman(models.PeminjamanStatusAccept)
+	inUse := createPeminjaman(models.PeminjamanStatusInUse)
+	acceptedJadwal := createJadwal(accepted, models.JadwalStatusNotUsed)
+	inUseJadwal := createJadwal(inUse, models.JadwalStatusInUsed)
+
+	clock := &fixedClock{}
+	jobs := routes.InitScheduler(db, clock)
+
+	steps := []struct {
+		name       string
+		now        time.Time
+		want       map[uint]string
+		wantJadwal map[uint]string
+	}{
+		{
+			name: "sebelum jam mulai",
+			now:  time.Date(2030, 1, 10, 7, 59, 0, 0, time.Local),
+			want: map[uint]string{
+				request.ID:  models.PeminjamanStatusRequest,
+				accepted.ID: models.PeminjamanStatusAccept,
+				inUse.ID:    models.PeminjamanStatusInUse,
+			},
+		},
+		{
+			name: "jam mulai, request kedaluwarsa",
+			now:  time.Date(2030, 1, 10, 8, 0, 0, 0, time.Local),
+			want: map[uint]string{
+				request.ID:  models.PeminjamanStatusExpired,
+				accepted.ID: models.PeminjamanStatusAccept,
+				inUse.ID:    models.PeminjamanStatusInUse,
+			},
+		},
+		{
+			name: "masih dalam toleransi check-in",
+			now:  time.Date(2030, 1, 10, 8, 14, 0, 0, time.Local),
+			want: map[uint]string{
+				accepted.ID: models.PeminjamanStatusAccept,
+			},
+			wantJadwal: map[uint]string{
+				acceptedJadwal.ID: models.JadwalStatusNotUsed,
+			},
+		},
+		{
+			name: "toleransi check-in habis",
+			now:  time.Date(2030, 1, 10, 8, 15, 0, 0, time.Local),
+			want: map[uint]string{
+				accepted.ID: models.PeminjamanStatusNoShow,
+				inUse.ID:    models.PeminjamanStatusInUse,
+			},
+			wantJadwal: map[uint]string{
+				acceptedJadwal.ID: models.JadwalStatusCancelled,
+			},
+		},
+		{
+			name: "jam selesai, check-out masih dalam toleransi",
+			now:  time.Date(2030, 1, 10, 11, 14, 0, 0, time.Local),
+			want: map[uint]string{
+				inUse.ID: models.PeminjamanStatusInUse,
+			},
+			wantJadwal: map[uint]string{
+				acceptedJadwal.ID: models.JadwalStatusCancelled,
+				inUseJadwal.ID:    models.JadwalStatusInUsed,
+			},
+		},
+		{
+			name: "toleransi check-out habis",
+			now:  time.Date(2030, 1, 10, 11, 15, 0, 0, time.Local),
+			want: map[uint]string{
+				inUse.ID: models.PeminjamanStatusFinished,
+			},
+			wantJadwal: map[uint]string{
+				acceptedJadwal.ID: models.JadwalStatusCancelled,
+				inUseJadwal.ID:    models.JadwalStatusFinished,
+			},
+		},
+	}
+
+	for _, step := range steps {
+		clock.now = step.now
+		jobs.RunAll()
+
+		for id, want := range step.want {
+			var peminjaman models.Peminjaman
+			if err := db.First(&peminjaman, id).Error; err != nil {
+				t.Fatalf("%s: gagal mengambil peminjaman %d: %v", step.name, id, err)
+			}
+			if peminjaman.Status != want {
+				t.Errorf("%s: status peminjaman %d %s, seharusnya %s", step.name, id, peminjaman.Status, want)
+			}
+		}
+		for id, want := range step.wantJadwal {
+			var jadwal models.Jadwal
+			if err := db.First(&jadwal, id).Error; err != nil {
+				t.Fatalf("%s: gagal mengambil jadwal %d: %v", step.name, id, err)
+			}
+			if jadwal.Status != want {
+				t.Errorf("%s: status jadwal %d %s, seharusnya %s", step.name, id, jadwal.Status, want)
+			}
+		}
+	}
+
+	var failed int64
+	db.Model(&models.JobRun{}).Where("status = ?", models.JobRunStatusFailed).Count(&failed)
+	if failed > 0 {
+		t.Errorf("ada %d job yang gagal", failed)
+	}
+}
+
+func TestRunJobSkipsWhenLocked(t *testing.T) {
+	db := testutil.OpenTestDB(t, "scheduler")
+
+	clock := scheduler.ClockFunc(time.Now)
+	runs := 0
+	job := scheduler.Job{Name: "test_lock", Run: func(now time.Time) (int, error) {
+		runs++
+		return 0, nil
+	}}
+
+	// Lock dipegang koneksi lain seolah worker lain sedang menjalankan job yang sama
+	err := db.Connection(func(conn *gorm.DB) error {
+		var acquired int
+		if err := conn.Raw("SELECT GET_LOCK(?, 0)", "scheduler:test_lock").Row().Scan(&acquired); err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", "scheduler:test_lock")
+
+		scheduler.New(clock, repositories.NewJobRunRepository(db), job).RunJob(job)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("gagal mengambil lock: %v", err)
+	}
+	if runs != 0 {
+		t.Fatalf("job berjalan %d kali padahal lock dipegang worker lain", runs)
+	}
+
+	jobRun := scheduler.New(clock, repositories.NewJobRunRepository(db), job).RunJob(job)
+	if runs != 1 || jobRun.Status != models.JobRunStatusSuccess {
+		t.Fatalf("job seharusnya berjalan sekali setelah lock lepas, berjalan %d kali dengan status %q", runs, jobRun.Status)
+	}
+}
@@ -0,0 +1,80 @@
+// Package testutil berisi helper bersama untuk test yang membutuhkan database MySQL.
+package testutil
+
+import (
+	"os"
+	"testing"
+
+	"sistem_peminjaman_be/configs"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// OpenTestDB membuka database MySQL khusus test dari TEST_DB_DSN, test dilewati jika tidak diisi.
+// Setiap package memakai schema sendiri bernama <database DSN>_<schema> agar package yang dijalankan
+// paralel oleh go test tidak saling mengosongkan tabel, sehingga user DSN perlu hak CREATE DATABASE.
+// Semua tabel dimigrasi lalu dikosongkan agar setiap test mulai dari data yang bersih.
+func OpenTestDB(t *testing.T, schema string) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN tidak diisi, test database dilewati")
+	}
+
+	config, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("TEST_DB_DSN tidak valid: %v", err)
+	}
+	config.DBName = config.DBName + "_" + schema
+
+	gormConfig := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	base, err := gorm.Open(mysql.Open(dsn), gormConfig)
+	if err != nil {
+		t.Fatalf("gagal membuka database test: %v", err)
+	}
+	if err := base.Exec("CREATE DATABASE IF NOT EXISTS `" + config.DBName + "`").Error; err != nil {
+		t.Fatalf("gagal membuat schema %s: %v", config.DBName, err)
+	}
+	if sqlDB, err := base.DB(); err == nil {
+		sqlDB.Close()
+	}
+
+	db, err := gorm.Open(mysql.Open(config.FormatDSN()), gormConfig)
+	if err != nil {
+		t.Fatalf("gagal membuka schema %s: %v", config.DBName, err)
+	}
+	if err := configs.MigrateDB(db); err != nil {
+		t.Fatalf("gagal migrasi database test: %v", err)
+	}
+	if err := truncateTables(db); err != nil {
+		t.Fatalf("gagal mengosongkan tabel: %v", err)
+	}
+	return db
+}
+
+// truncateTables mengosongkan semua tabel di satu koneksi dengan foreign key check dimatikan,
+// sehingga urutan tabel tidak perlu mengikuti relasinya
+func truncateTables(db *gorm.DB) error {
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return err
+	}
+
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+
+		for _, table := range tables {
+			if err := conn.Exec("DELETE FROM `" + table + "`").Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
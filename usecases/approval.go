@@ -0,0 +1,397 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"time"
+)
+
+type ApprovalUsecase interface {
+	GetLabApprovalSteps(labID uint) (dtos.LabApprovalChainResponse, error)
+	UpdateLabApprovalSteps(labID uint, input dtos.LabApprovalStepsInput) (dtos.LabApprovalChainResponse, error)
+	GetPendingApprovals(userID uint, role string, page, limit int) ([]dtos.PendingApprovalResponse, int, error)
+	DecidePeminjamanApproval(userID uint, role string, peminjamanID uint, input dtos.ApprovalDecisionInput) (dtos.ApprovalDecisionResponse, error)
+	DecideRescheduleApproval(userID uint, role string, rescheduleID uint, input dtos.ApprovalDecisionInput) (dtos.ApprovalDecisionResponse, error)
+}
+
+type approvalUsecase struct {
+	approvalRepo             repositories.ApprovalRepository
+	peminjamanRepo           repositories.PeminjamanRepository
+	peminjamanRescheduleRepo repositories.PeminjamanRescheduleRepository
+	labRepo                  repositories.LabRepository
+	userRepo                 repositories.UserRepository
+	templateMessageRepo      repositories.TemplateMessageRepository
+	notificationRepo         repositories.NotificationRepository
+	peminjamanUsecase        PeminjamanUsecase
+}
+
+func NewApprovalUsecase(approvalRepo repositories.ApprovalRepository, peminjamanRepo repositories.PeminjamanRepository, peminjamanRescheduleRepo repositories.PeminjamanRescheduleRepository, labRepo repositories.LabRepository, userRepo repositories.UserRepository, templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, peminjamanUsecase PeminjamanUsecase) ApprovalUsecase {
+	return &approvalUsecase{approvalRepo, peminjamanRepo, peminjamanRescheduleRepo, labRepo, userRepo, templateMessageRepo, notificationRepo, peminjamanUsecase}
+}
+
+func (u *approvalUsecase) GetLabApprovalSteps(labID uint) (dtos.LabApprovalChainResponse, error) {
+	chainResponse := dtos.LabApprovalChainResponse{LabID: labID, Steps: []dtos.LabApprovalStepResponse{}}
+
+	_, err := u.labRepo.GetLabByID(labID)
+	if err != nil {
+		return chainResponse, errors.New("lab tidak ditemukan, pastikan ID benar")
+	}
+
+	steps, err := u.approvalRepo.GetLabApprovalSteps(labID)
+	if err != nil {
+		return chainResponse, err
+	}
+
+	for _, step := range steps {
+		chainResponse.Steps = append(chainResponse.Steps, toLabApprovalStepResponse(step))
+	}
+
+	return chainResponse, nil
+}
+
+// UpdateLabApprovalSteps mengganti rantai persetujuan lab, urutan tahap mengikuti urutan input.
+// Rantai kosong berarti peminjaman lab cukup disetujui admin seperti sebelumnya.
+func (u *approvalUsecase) UpdateLabApprovalSteps(labID uint, input dtos.LabApprovalStepsInput) (dtos.LabApprovalChainResponse, error) {
+	chainResponse := dtos.LabApprovalChainResponse{LabID: labID, Steps: []dtos.LabApprovalStepResponse{}}
+
+	_, err := u.labRepo.GetLabByID(labID)
+	if err != nil {
+		return chainResponse, errors.New("lab tidak ditemukan, pastikan ID benar")
+	}
+
+	var steps []models.LabApprovalStep
+	for i, stepInput := range input.Steps {
+		if !isApproverRole(stepInput.ApproverRole) {
+			return chainResponse, fmt.Errorf("approver role tahap %d invalid, gunakan admin, dosen atau kepala_lab", i+1)
+		}
+
+		if stepInput.ApproverUserID != nil {
+			approver, err := u.userRepo.UserGetById(*stepInput.ApproverUserID)
+			if err != nil {
+				return chainResponse, fmt.Errorf("approver tahap %d tidak ditemukan", i+1)
+			}
+			if approver.Role != stepInput.ApproverRole {
+				return chainResponse, fmt.Errorf("approver tahap %d tidak memiliki role %s", i+1, stepInput.ApproverRole)
+			}
+		}
+
+		steps = append(steps, models.LabApprovalStep{
+			LabID:          labID,
+			StepOrder:      i + 1,
+			Name:           stepInput.Name,
+			ApproverRole:   stepInput.ApproverRole,
+			ApproverUserID: stepInput.ApproverUserID,
+		})
+	}
+
+	savedSteps, err := u.approvalRepo.ReplaceLabApprovalSteps(labID, steps)
+	if err != nil {
+		return chainResponse, err
+	}
+
+	for _, step := range savedSteps {
+		chainResponse.Steps = append(chainResponse.Steps, toLabApprovalStepResponse(step))
+	}
+
+	return chainResponse, nil
+}
+
+func (u *approvalUsecase) GetPendingApprovals(userID uint, role string, page, limit int) ([]dtos.PendingApprovalResponse, int, error) {
+	var pendingResponses []dtos.PendingApprovalResponse
+
+	approvals, count, err := u.approvalRepo.GetPendingApprovalsForApprover(userID, role, page, limit)
+	if err != nil {
+		return pendingResponses, 0, err
+	}
+
+	for _, approval := range approvals {
+		peminjaman := approval.Peminjaman
+
+		getLab, err := u.labRepo.GetLabByID2(peminjaman.LabID)
+		if err != nil {
+			return pendingResponses, 0, err
+		}
+
+		getUser, err := u.userRepo.UserGetById(peminjaman.UserID)
+		if err != nil {
+			return pendingResponses, 0, err
+		}
+
+		var rescheduleResponse *dtos.PeminjamanRescheduleResponse
+		if approval.PeminjamanReschedule != nil {
+			response := toPeminjamanRescheduleResponse(*approval.PeminjamanReschedule)
+			rescheduleResponse = &response
+		}
+
+		pendingResponses = append(pendingResponses, dtos.PendingApprovalResponse{
+			PeminjamanID:      peminjaman.ID,
+			TanggalPeminjaman: helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
+			JamPeminjaman:     peminjaman.JamPeminjaman,
+			JamSelesai:        peminjaman.JamSelesai,
+			Description:       peminjaman.Description,
+			Lab: dtos.LabByIDResponses{
+				LabID:       getLab.ID,
+				Name:        getLab.Name,
+				Description: getLab.Description,
+			},
+			User: &dtos.UserInformationResponses{
+				ID:             getUser.ID,
+				FullName:       getUser.FullName,
+				Email:          getUser.Email,
+				NIMNIP:         getUser.NIMNIP,
+				ProfilePicture: getUser.ProfilePicture,
+			},
+			Reschedule:  rescheduleResponse,
+			CurrentStep: toPeminjamanApprovalResponse(approval),
+		})
+	}
+
+	return pendingResponses, count, nil
+}
+
+// DecidePeminjamanApproval menyimpan keputusan approver untuk tahap yang sedang berjalan.
+// Penolakan di tahap mana pun langsung menolak peminjaman, sedangkan persetujuan di tahap terakhir
+// mengubah status peminjaman menjadi accept.
+func (u *approvalUsecase) DecidePeminjamanApproval(userID uint, role string, peminjamanID uint, input dtos.ApprovalDecisionInput) (dtos.ApprovalDecisionResponse, error) {
+	var decisionResponse dtos.ApprovalDecisionResponse
+
+	var status string
+	switch input.Decision {
+	case "approve":
+		status = models.ApprovalStatusApproved
+	case "reject":
+		status = models.ApprovalStatusRejected
+	default:
+		return decisionResponse, errors.New("decision invalid, gunakan approve atau reject")
+	}
+
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanID(peminjamanID)
+	if err != nil {
+		return decisionResponse, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+	}
+	if peminjaman.Status != models.PeminjamanStatusRequest {
+		return decisionResponse, helpers.ErrInvalidStatusTransition
+	}
+
+	approvals, err := u.approvalRepo.GetPeminjamanApprovals(peminjaman.ID)
+	if err != nil {
+		return decisionResponse, err
+	}
+
+	currentIndex := currentApprovalIndex(approvals)
+	if currentIndex < 0 {
+		return decisionResponse, errors.New("peminjaman ini tidak memerlukan persetujuan bertahap")
+	}
+
+	current := approvals[currentIndex]
+	if !canApprove(current, userID, role) {
+		return decisionResponse, helpers.ErrStatusTransitionForbidden
+	}
+
+	now := time.Now()
+	current.Status = status
+	current.DecidedByID = &userID
+	current.Comment = input.Comment
+	current.DecidedAt = &now
+
+	// Penolakan atau persetujuan tahap terakhir disimpan dalam satu transaksi bersama perubahan status
+	// peminjaman, sehingga tahap tidak tercatat diputuskan jika status peminjaman gagal berubah
+	isFinalStep := currentIndex == len(approvals)-1
+	if status == models.ApprovalStatusRejected || isFinalStep {
+		toStatus := models.PeminjamanStatusAccept
+		if status == models.ApprovalStatusRejected {
+			toStatus = models.PeminjamanStatusReject
+		}
+		statusResponse, err := u.peminjamanUsecase.TransitionPeminjamanStatusWithApproval(current, toStatus, input.Comment)
+		if err != nil {
+			return decisionResponse, err
+		}
+		peminjaman.Status = statusResponse.Status
+	} else {
+		current, err = u.approvalRepo.DecidePeminjamanApproval(current)
+		if err != nil {
+			return decisionResponse, errors.New("tahap persetujuan sudah diputuskan")
+		}
+	}
+	approvals[currentIndex] = current
+
+	if peminjaman.Status != models.PeminjamanStatusRequest {
+		title := "Peminjaman Disetujui"
+		if peminjaman.Status == models.PeminjamanStatusReject {
+			title = "Peminjaman Ditolak"
+		}
+		content := fmt.Sprintf("Peminjaman tanggal %s jam %s-%s diputuskan pada tahap %s.",
+			helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman), peminjaman.JamPeminjaman, peminjaman.JamSelesai, current.Name)
+		if input.Comment != "" {
+			content += " Catatan: " + input.Comment
+		}
+		if err := notifyUsers(u.templateMessageRepo, u.notificationRepo, []uint{peminjaman.UserID}, title, content); err != nil {
+			return decisionResponse, err
+		}
+	}
+
+	decisionResponse = dtos.ApprovalDecisionResponse{
+		PeminjamanID: peminjaman.ID,
+		Status:       peminjaman.Status,
+		Approvals:    toPeminjamanApprovalResponses(approvals),
+	}
+
+	return decisionResponse, nil
+}
+
+// DecideRescheduleApproval menyimpan keputusan approver untuk tahap rantai jadwal ulang yang sedang berjalan.
+// Penolakan di tahap mana pun menolak usulan, persetujuan di tahap terakhir baru memindahkan peminjaman.
+func (u *approvalUsecase) DecideRescheduleApproval(userID uint, role string, rescheduleID uint, input dtos.ApprovalDecisionInput) (dtos.ApprovalDecisionResponse, error) {
+	var decisionResponse dtos.ApprovalDecisionResponse
+
+	var status string
+	switch input.Decision {
+	case "approve":
+		status = models.ApprovalStatusApproved
+	case "reject":
+		status = models.ApprovalStatusRejected
+	default:
+		return decisionResponse, errors.New("decision invalid, gunakan approve atau reject")
+	}
+
+	reschedule, err := u.peminjamanRescheduleRepo.GetRescheduleByID(rescheduleID)
+	if err != nil {
+		return decisionResponse, errors.New("usulan jadwal ulang tidak ditemukan, pastikan ID benar")
+	}
+	if reschedule.Status != models.RescheduleStatusWaitingApproval {
+		return decisionResponse, helpers.ErrInvalidStatusTransition
+	}
+
+	approvals, err := u.approvalRepo.GetRescheduleApprovals(reschedule.ID)
+	if err != nil {
+		return decisionResponse, err
+	}
+
+	currentIndex := currentApprovalIndex(approvals)
+	if currentIndex < 0 {
+		return decisionResponse, errors.New("usulan jadwal ulang ini tidak memerlukan persetujuan bertahap")
+	}
+
+	current := approvals[currentIndex]
+	if !canApprove(current, userID, role) {
+		return decisionResponse, helpers.ErrStatusTransitionForbidden
+	}
+
+	now := time.Now()
+	current.Status = status
+	current.DecidedByID = &userID
+	current.Comment = input.Comment
+	current.DecidedAt = &now
+
+	rescheduleStatus := reschedule.Status
+	isFinalStep := currentIndex == len(approvals)-1
+	switch {
+	case status == models.ApprovalStatusRejected:
+		rescheduleResponse, err := u.peminjamanUsecase.RejectRescheduleWithApproval(reschedule, current)
+		if err != nil {
+			return decisionResponse, err
+		}
+		rescheduleStatus = rescheduleResponse.Status
+	case isFinalStep:
+		rescheduleResponse, err := u.peminjamanUsecase.ApplyRescheduleWithApproval(reschedule, current)
+		if err != nil {
+			return decisionResponse, err
+		}
+		rescheduleStatus = rescheduleResponse.Status
+	default:
+		current, err = u.approvalRepo.DecidePeminjamanApproval(current)
+		if err != nil {
+			return decisionResponse, errors.New("tahap persetujuan sudah diputuskan")
+		}
+	}
+	approvals[currentIndex] = current
+
+	decisionResponse = dtos.ApprovalDecisionResponse{
+		PeminjamanID: reschedule.PeminjamanID,
+		RescheduleID: &reschedule.ID,
+		Status:       rescheduleStatus,
+		Approvals:    toPeminjamanApprovalResponses(approvals),
+	}
+
+	return decisionResponse, nil
+}
+
+// approvalChainForLab menyalin rantai persetujuan lab menjadi tahap persetujuan peminjaman baru
+func approvalChainForLab(approvalRepo repositories.ApprovalRepository, labID uint) ([]models.PeminjamanApproval, error) {
+	var approvals []models.PeminjamanApproval
+
+	steps, err := approvalRepo.GetLabApprovalSteps(labID)
+	if err != nil {
+		return approvals, err
+	}
+
+	for _, step := range steps {
+		approvals = append(approvals, models.PeminjamanApproval{
+			StepOrder:      step.StepOrder,
+			Name:           step.Name,
+			ApproverRole:   step.ApproverRole,
+			ApproverUserID: step.ApproverUserID,
+			Status:         models.ApprovalStatusPending,
+		})
+	}
+
+	return approvals, nil
+}
+
+// currentApprovalIndex mengembalikan index tahap pertama yang belum disetujui, -1 jika tidak ada
+func currentApprovalIndex(approvals []models.PeminjamanApproval) int {
+	for i, approval := range approvals {
+		if approval.Status != models.ApprovalStatusApproved {
+			if approval.Status != models.ApprovalStatusPending {
+				return -1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+func canApprove(approval models.PeminjamanApproval, userID uint, role string) bool {
+	if approval.ApproverUserID != nil {
+		return *approval.ApproverUserID == userID
+	}
+	return approval.ApproverRole == role
+}
+
+func isApproverRole(role string) bool {
+	return role == models.UserRoleAdmin || role == models.UserRoleDosen || role == models.UserRoleKepalaLab
+}
+
+func toLabApprovalStepResponse(step models.LabApprovalStep) dtos.LabApprovalStepResponse {
+	return dtos.LabApprovalStepResponse{
+		StepOrder:      step.StepOrder,
+		Name:           step.Name,
+		ApproverRole:   step.ApproverRole,
+		ApproverUserID: step.ApproverUserID,
+	}
+}
+
+func toPeminjamanApprovalResponse(approval models.PeminjamanApproval) dtos.PeminjamanApprovalResponse {
+	return dtos.PeminjamanApprovalResponse{
+		StepOrder:      approval.StepOrder,
+		Name:           approval.Name,
+		ApproverRole:   approval.ApproverRole,
+		ApproverUserID: approval.ApproverUserID,
+		Status:         approval.Status,
+		DecidedByID:    approval.DecidedByID,
+		Comment:        approval.Comment,
+		DecidedAt:      approval.DecidedAt,
+	}
+}
+
+func toPeminjamanApprovalResponses(approvals []models.PeminjamanApproval) []dtos.PeminjamanApprovalResponse {
+	var approvalResponses []dtos.PeminjamanApprovalResponse
+	for _, approval := range approvals {
+		approvalResponses = append(approvalResponses, toPeminjamanApprovalResponse(approval))
+	}
+	return approvalResponses
+}
@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+)
+
+// Fungsi di file ini memuat data relasi untuk satu halaman list dengan satu query IN per relasi,
+// sehingga jumlah query per halaman tetap dan tidak bertambah mengikuti jumlah baris.
+
+// uniqueIDs membuang ID kosong dan duplikat dengan tetap menjaga urutan
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+func loadLabs(labRepo repositories.LabRepository, labIDs []uint) (map[uint]models.Lab, error) {
+	labs, err := labRepo.GetLabsByIDs(uniqueIDs(labIDs))
+	if err != nil {
+		return nil, err
+	}
+	labByID := make(map[uint]models.Lab, len(labs))
+	for _, lab := range labs {
+		labByID[lab.ID] = lab
+	}
+	return labByID, nil
+}
+
+func loadUsers(userRepo repositories.UserRepository, userIDs []uint) (map[uint]models.User, error) {
+	users, err := userRepo.UserGetByIDs(uniqueIDs(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	userByID := make(map[uint]models.User, len(users))
+	for _, user := range users {
+		userByID[user.ID] = user
+	}
+	return userByID, nil
+}
+
+func loadLabImageResponses(labImageRepo repositories.LabImageRepository, labIDs []uint) (map[uint][]dtos.LabImageResponse, error) {
+	labImages, err := labImageRepo.GetLabImagesByLabIDs(uniqueIDs(labIDs))
+	if err != nil {
+		return nil, err
+	}
+	labImageResponses := make(map[uint][]dtos.LabImageResponse)
+	for _, labImage := range labImages {
+		labImageResponses[labImage.LabID] = append(labImageResponses[labImage.LabID], dtos.LabImageResponse{
+			LabID:    labImage.LabID,
+			ImageUrl: labImage.ImageUrl,
+		})
+	}
+	return labImageResponses, nil
+}
+
+func loadSuratRekomendasiImageResponses(suratRekomendasiImageRepo repositories.SuratRekomendasiImageRepository, peminjamanIDs []uint) (map[uint][]dtos.SuratRekomendasiImageResponse, error) {
+	suratRekomendasiImages, err := suratRekomendasiImageRepo.GetSuratRekomendasiImagesByPeminjamanIDs(uniqueIDs(peminjamanIDs))
+	if err != nil {
+		return nil, err
+	}
+	suratRekomendasiImageResponses := make(map[uint][]dtos.SuratRekomendasiImageResponse)
+	for _, suratRekomendasiImage := range suratRekomendasiImages {
+		suratRekomendasiImageResponses[suratRekomendasiImage.PeminjamanID] = append(suratRekomendasiImageResponses[suratRekomendasiImage.PeminjamanID], dtos.SuratRekomendasiImageResponse{
+			PeminjamanID:             suratRekomendasiImage.PeminjamanID,
+			SuratRekomendasiImageUrl: suratRekomendasiImage.SuratRekomendasiImageUrl,
+		})
+	}
+	return suratRekomendasiImageResponses, nil
+}
+
+func loadBeritaAcaraImageResponses(beritaAcaraImageRepo repositories.BeritaAcaraImageRepository, jadwalIDs []uint) (map[uint][]dtos.BeritaAcaraImageResponse, error) {
+	beritaAcaraImages, err := beritaAcaraImageRepo.GetBeritaAcaraImagesByJadwalIDs(uniqueIDs(jadwalIDs))
+	if err != nil {
+		return nil, err
+	}
+	beritaAcaraImageResponses := make(map[uint][]dtos.BeritaAcaraImageResponse)
+	for _, beritaAcaraImage := range beritaAcaraImages {
+		beritaAcaraImageResponses[beritaAcaraImage.JadwalID] = append(beritaAcaraImageResponses[beritaAcaraImage.JadwalID], dtos.BeritaAcaraImageResponse{
+			JadwalID:            beritaAcaraImage.JadwalID,
+			BeritaAcaraImageUrl: beritaAcaraImage.BeritaAcaraImageUrl,
+		})
+	}
+	return beritaAcaraImageResponses, nil
+}
+
+// peminjamanListRelations adalah data relasi satu halaman list peminjaman yang dimuat sekaligus
+type peminjamanListRelations struct {
+	labs                   map[uint]models.Lab
+	labImages              map[uint][]dtos.LabImageResponse
+	users                  map[uint]models.User
+	suratRekomendasiImages map[uint][]dtos.SuratRekomendasiImageResponse
+}
+
+// loadPeminjamanListRelations memuat lab, gambar lab, surat rekomendasi, dan peminjam (jika withUsers)
+// untuk semua peminjaman dalam satu halaman
+func (u *peminjamanUsecase) loadPeminjamanListRelations(peminjamans []models.Peminjaman, withUsers bool) (peminjamanListRelations, error) {
+	var relations peminjamanListRelations
+
+	peminjamanIDs := make([]uint, 0, len(peminjamans))
+	labIDs := make([]uint, 0, len(peminjamans))
+	userIDs := make([]uint, 0, len(peminjamans))
+	for _, peminjaman := range peminjamans {
+		peminjamanIDs = append(peminjamanIDs, peminjaman.ID)
+		labIDs = append(labIDs, peminjaman.LabID)
+		userIDs = append(userIDs, peminjaman.UserID)
+	}
+
+	var err error
+	if relations.labs, err = loadLabs(u.labRepo, labIDs); err != nil {
+		return relations, err
+	}
+	if relations.labImages, err = loadLabImageResponses(u.labImageRepo, labIDs); err != nil {
+		return relations, err
+	}
+	if relations.suratRekomendasiImages, err = loadSuratRekomendasiImageResponses(u.suratRekomendasiImageRepo, peminjamanIDs); err != nil {
+		return relations, err
+	}
+	if withUsers {
+		if relations.users, err = loadUsers(u.userRepo, userIDs); err != nil {
+			return relations, err
+		}
+	}
+
+	return relations, nil
+}
@@ -0,0 +1,306 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"time"
+)
+
+type BookingQuotaUsecase interface {
+	GetBookingQuotas() ([]dtos.BookingQuotaResponse, error)
+	CreateBookingQuota(input dtos.BookingQuotaInput) (dtos.BookingQuotaResponse, error)
+	UpdateBookingQuota(id uint, input dtos.BookingQuotaInput) (dtos.BookingQuotaResponse, error)
+	DeleteBookingQuota(id uint) error
+	GetUserQuota(userID, labID uint, tanggal string) ([]dtos.QuotaStatusResponse, error)
+}
+
+type bookingQuotaUsecase struct {
+	bookingQuotaRepo repositories.BookingQuotaRepository
+	labRepo          repositories.LabRepository
+	userRepo         repositories.UserRepository
+}
+
+func NewBookingQuotaUsecase(bookingQuotaRepo repositories.BookingQuotaRepository, labRepo repositories.LabRepository, userRepo repositories.UserRepository) BookingQuotaUsecase {
+	return &bookingQuotaUsecase{bookingQuotaRepo, labRepo, userRepo}
+}
+
+func (u *bookingQuotaUsecase) GetBookingQuotas() ([]dtos.BookingQuotaResponse, error) {
+	var bookingQuotaResponses []dtos.BookingQuotaResponse
+
+	bookingQuotas, err := u.bookingQuotaRepo.GetBookingQuotas()
+	if err != nil {
+		return bookingQuotaResponses, err
+	}
+
+	for _, bookingQuota := range bookingQuotas {
+		bookingQuotaResponses = append(bookingQuotaResponses, toBookingQuotaResponse(bookingQuota))
+	}
+
+	return bookingQuotaResponses, nil
+}
+
+func (u *bookingQuotaUsecase) CreateBookingQuota(input dtos.BookingQuotaInput) (dtos.BookingQuotaResponse, error) {
+	bookingQuota := models.BookingQuota{}
+	if err := u.applyBookingQuotaInput(&bookingQuota, input); err != nil {
+		return dtos.BookingQuotaResponse{}, err
+	}
+
+	createdBookingQuota, err := u.bookingQuotaRepo.CreateBookingQuota(bookingQuota)
+	if err != nil {
+		return dtos.BookingQuotaResponse{}, err
+	}
+
+	return u.getBookingQuotaResponse(createdBookingQuota.ID)
+}
+
+func (u *bookingQuotaUsecase) UpdateBookingQuota(id uint, input dtos.BookingQuotaInput) (dtos.BookingQuotaResponse, error) {
+	bookingQuota, err := u.bookingQuotaRepo.GetBookingQuotaByID(id)
+	if err != nil {
+		return dtos.BookingQuotaResponse{}, errors.New("kuota tidak ditemukan, pastikan ID benar")
+	}
+	if err := u.applyBookingQuotaInput(&bookingQuota, input); err != nil {
+		return dtos.BookingQuotaResponse{}, err
+	}
+
+	if _, err := u.bookingQuotaRepo.UpdateBookingQuota(bookingQuota); err != nil {
+		return dtos.BookingQuotaResponse{}, err
+	}
+
+	return u.getBookingQuotaResponse(bookingQuota.ID)
+}
+
+func (u *bookingQuotaUsecase) DeleteBookingQuota(id uint) error {
+	if _, err := u.bookingQuotaRepo.GetBookingQuotaByID(id); err != nil {
+		return errors.New("kuota tidak ditemukan, pastikan ID benar")
+	}
+	return u.bookingQuotaRepo.DeleteBookingQuota(id)
+}
+
+// GetUserQuota menampilkan pemakaian kuota user pada minggu dan bulan dari tanggal yang diminta (default hari ini)
+func (u *bookingQuotaUsecase) GetUserQuota(userID, labID uint, tanggal string) ([]dtos.QuotaStatusResponse, error) {
+	getUser, err := u.userRepo.UserGetById(userID)
+	if err != nil {
+		return nil, errors.New("failed to get user")
+	}
+
+	date, err := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if tanggal != "" {
+		date, err = time.Parse("2006-01-02", tanggal)
+		if err != nil {
+			return nil, errors.New("tanggal invalid, gunakan format YYYY-MM-DD")
+		}
+	}
+
+	return quotaStatuses(u.bookingQuotaRepo, getUser, labID, date)
+}
+
+func (u *bookingQuotaUsecase) applyBookingQuotaInput(bookingQuota *models.BookingQuota, input dtos.BookingQuotaInput) error {
+	switch input.Role {
+	case "", models.UserRoleUser, models.UserRoleDosen, models.UserRoleKepalaLab:
+	default:
+		return errors.New("role harus user, dosen, kepala_lab, atau dikosongkan untuk semua role")
+	}
+	if input.LabID != nil {
+		if _, err := u.labRepo.GetLabByID(*input.LabID); err != nil {
+			return errors.New("lab tidak ditemukan, pastikan lab_id benar")
+		}
+	}
+	for _, limit := range []*int{input.MaxActiveRequests, input.MaxBookingsPerWeek, input.MaxHoursPerMonth} {
+		if limit != nil && *limit < 0 {
+			return errors.New("batas kuota tidak boleh negatif")
+		}
+	}
+	if input.MaxActiveRequests == nil && input.MaxBookingsPerWeek == nil && input.MaxHoursPerMonth == nil {
+		return errors.New("minimal satu batas kuota harus diisi")
+	}
+
+	bookingQuota.Role = input.Role
+	bookingQuota.LabID = input.LabID
+	bookingQuota.MaxActiveRequests = input.MaxActiveRequests
+	bookingQuota.MaxBookingsPerWeek = input.MaxBookingsPerWeek
+	bookingQuota.MaxHoursPerMonth = input.MaxHoursPerMonth
+	return nil
+}
+
+func (u *bookingQuotaUsecase) getBookingQuotaResponse(id uint) (dtos.BookingQuotaResponse, error) {
+	bookingQuota, err := u.bookingQuotaRepo.GetBookingQuotaByID(id)
+	if err != nil {
+		return dtos.BookingQuotaResponse{}, errors.New("failed to get booking quota")
+	}
+	return toBookingQuotaResponse(bookingQuota), nil
+}
+
+// quotaStatuses menghitung pemakaian setiap kuota yang berlaku untuk user. Minggu dimulai hari Senin
+// dan kuota lab hanya menghitung peminjaman di lab tersebut.
+func quotaStatuses(bookingQuotaRepo repositories.BookingQuotaRepository, user models.User, labID uint, tanggal time.Time) ([]dtos.QuotaStatusResponse, error) {
+	return quotaStatusesExcept(bookingQuotaRepo, user, labID, tanggal, nil)
+}
+
+// quotaStatusesExcept menghitung kuota seperti quotaStatuses tanpa menghitung peminjaman exclude,
+// dipakai saat peminjaman yang sudah ada dipindah ke tanggal lain
+func quotaStatusesExcept(bookingQuotaRepo repositories.BookingQuotaRepository, user models.User, labID uint, tanggal time.Time, exclude *models.Peminjaman) ([]dtos.QuotaStatusResponse, error) {
+	quotaStatusResponses := []dtos.QuotaStatusResponse{}
+
+	bookingQuotas, err := bookingQuotaRepo.GetBookingQuotasForRole(user.Role, labID)
+	if err != nil {
+		return quotaStatusResponses, err
+	}
+
+	weekStart := tanggal.AddDate(0, 0, -((int(tanggal.Weekday()) + 6) % 7))
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	monthStart := time.Date(tanggal.Year(), tanggal.Month(), 1, 0, 0, 0, 0, tanggal.Location())
+	monthEnd := monthStart.AddDate(0, 1, -1)
+
+	for _, bookingQuota := range bookingQuotas {
+		var scopeLabID uint
+		if bookingQuota.LabID != nil {
+			scopeLabID = *bookingQuota.LabID
+		}
+
+		activeRequests, err := bookingQuotaRepo.CountActiveRequests(user.ID, scopeLabID)
+		if err != nil {
+			return quotaStatusResponses, err
+		}
+		if exclude != nil && exclude.Status == models.PeminjamanStatusRequest && (scopeLabID == 0 || scopeLabID == exclude.LabID) {
+			activeRequests--
+		}
+
+		weekBookings, err := bookingQuotaRepo.GetBookedPeminjamans(user.ID, scopeLabID, weekStart, weekEnd)
+		if err != nil {
+			return quotaStatusResponses, err
+		}
+
+		monthBookings, err := bookingQuotaRepo.GetBookedPeminjamans(user.ID, scopeLabID, monthStart, monthEnd)
+		if err != nil {
+			return quotaStatusResponses, err
+		}
+		if exclude != nil {
+			weekBookings = excludePeminjaman(weekBookings, exclude.ID)
+			monthBookings = excludePeminjaman(monthBookings, exclude.ID)
+		}
+		var monthHours float64
+		for _, peminjaman := range monthBookings {
+			monthHours += sessionHours(peminjaman.JamPeminjaman, peminjaman.JamSelesai)
+		}
+
+		bookingQuotaResponse := toBookingQuotaResponse(bookingQuota)
+		quotaStatusResponses = append(quotaStatusResponses, dtos.QuotaStatusResponse{
+			BookingQuotaID:  bookingQuota.ID,
+			Role:            bookingQuota.Role,
+			Lab:             bookingQuotaResponse.Lab,
+			WeekStart:       weekStart.Format("2006-01-02"),
+			WeekEnd:         weekEnd.Format("2006-01-02"),
+			Month:           monthStart.Format("2006-01"),
+			ActiveRequests:  quotaCount(bookingQuota.MaxActiveRequests, activeRequests),
+			BookingsPerWeek: quotaCount(bookingQuota.MaxBookingsPerWeek, len(weekBookings)),
+			HoursPerMonth:   quotaHours(bookingQuota.MaxHoursPerMonth, monthHours),
+		})
+	}
+
+	return quotaStatusResponses, nil
+}
+
+func excludePeminjaman(peminjamans []models.Peminjaman, id uint) []models.Peminjaman {
+	var filtered []models.Peminjaman
+	for _, peminjaman := range peminjamans {
+		if peminjaman.ID != id {
+			filtered = append(filtered, peminjaman)
+		}
+	}
+	return filtered
+}
+
+// checkBookingQuota memastikan satu peminjaman baru masih muat di semua kuota yang berlaku.
+// exclude diisi peminjaman yang dijadwalkan ulang agar tempat lamanya tidak ikut dihitung.
+func checkBookingQuota(bookingQuotaRepo repositories.BookingQuotaRepository, user models.User, labID uint, tanggal time.Time, jamMulai, jamSelesai string, exclude *models.Peminjaman) error {
+	statuses, err := quotaStatusesExcept(bookingQuotaRepo, user, labID, tanggal, exclude)
+	if err != nil {
+		return errors.New("failed to check booking quota")
+	}
+
+	hours := sessionHours(jamMulai, jamSelesai)
+	for _, status := range statuses {
+		if status.ActiveRequests.Remaining != nil && *status.ActiveRequests.Remaining < 1 {
+			return fmt.Errorf("%w: pengajuan aktif sudah %d dari %d", helpers.ErrQuotaExceeded, status.ActiveRequests.Used, *status.ActiveRequests.Limit)
+		}
+		if status.BookingsPerWeek.Remaining != nil && *status.BookingsPerWeek.Remaining < 1 {
+			return fmt.Errorf("%w: peminjaman minggu %s sampai %s sudah %d dari %d", helpers.ErrQuotaExceeded, status.WeekStart, status.WeekEnd, status.BookingsPerWeek.Used, *status.BookingsPerWeek.Limit)
+		}
+		if status.HoursPerMonth.Remaining != nil && *status.HoursPerMonth.Remaining < hours {
+			return fmt.Errorf("%w: peminjaman bulan %s sudah %.1f dari %d jam", helpers.ErrQuotaExceeded, status.Month, status.HoursPerMonth.Used, *status.HoursPerMonth.Limit)
+		}
+	}
+	return nil
+}
+
+// bookingQuotaCheck menunda checkBookingQuota agar dijalankan repository di dalam transaksi pembuatan
+// peminjaman, setelah baris user dikunci, dengan repository kuota milik transaksi tersebut
+func bookingQuotaCheck(user models.User, labID uint, tanggal time.Time, jamMulai, jamSelesai string, exclude *models.Peminjaman) repositories.QuotaCheck {
+	return func(bookingQuotaRepo repositories.BookingQuotaRepository) error {
+		return checkBookingQuota(bookingQuotaRepo, user, labID, tanggal, jamMulai, jamSelesai, exclude)
+	}
+}
+
+func quotaCount(limit *int, used int) dtos.QuotaCountResponse {
+	quotaCountResponse := dtos.QuotaCountResponse{Limit: limit, Used: used}
+	if limit != nil {
+		remaining := *limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		quotaCountResponse.Remaining = &remaining
+	}
+	return quotaCountResponse
+}
+
+func quotaHours(limit *int, used float64) dtos.QuotaHoursResponse {
+	quotaHoursResponse := dtos.QuotaHoursResponse{Limit: limit, Used: used}
+	if limit != nil {
+		remaining := float64(*limit) - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		quotaHoursResponse.Remaining = &remaining
+	}
+	return quotaHoursResponse
+}
+
+// sessionHours menghitung durasi sesi dari jam mulai dan jam selesai berformat HH:MM
+func sessionHours(jamMulai, jamSelesai string) float64 {
+	start, err := time.Parse("15:04", jamMulai)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse("15:04", jamSelesai)
+	if err != nil || !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Hours()
+}
+
+func toBookingQuotaResponse(bookingQuota models.BookingQuota) dtos.BookingQuotaResponse {
+	bookingQuotaResponse := dtos.BookingQuotaResponse{
+		BookingQuotaID:     bookingQuota.ID,
+		Role:               bookingQuota.Role,
+		MaxActiveRequests:  bookingQuota.MaxActiveRequests,
+		MaxBookingsPerWeek: bookingQuota.MaxBookingsPerWeek,
+		MaxHoursPerMonth:   bookingQuota.MaxHoursPerMonth,
+		CreatedAt:          bookingQuota.CreatedAt,
+		UpdatedAt:          bookingQuota.UpdatedAt,
+	}
+	if bookingQuota.Lab != nil {
+		bookingQuotaResponse.Lab = &dtos.LabByIDResponses{
+			LabID:       bookingQuota.Lab.ID,
+			Name:        bookingQuota.Lab.Name,
+			Description: bookingQuota.Lab.Description,
+		}
+	}
+	return bookingQuotaResponse
+}
@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"strings"
+	"time"
+)
+
+// calendarFeedHistoryDays adalah berapa hari ke belakang event lama masih dimuat di feed
+const calendarFeedHistoryDays = 90
+
+// calendarPeminjamanStatuses adalah status peminjaman yang tampil di feed. Peminjaman yang dibatalkan
+// tetap dikirim dengan STATUS:CANCELLED agar aplikasi kalender menghapus event yang sudah tersimpan.
+var calendarPeminjamanStatuses = []string{
+	models.PeminjamanStatusAccept,
+	models.PeminjamanStatusInUse,
+	models.PeminjamanStatusFinished,
+	models.PeminjamanStatusCancelled,
+}
+
+type CalendarFeedUsecase interface {
+	GetCalendarFeed(userID uint) (dtos.CalendarFeedResponse, error)
+	RotateCalendarFeed(userID uint) (dtos.CalendarFeedResponse, error)
+	GetUserCalendar(token string) ([]byte, error)
+	GetLabCalendar(labID uint) ([]byte, error)
+}
+
+type calendarFeedUsecase struct {
+	calendarFeedRepo repositories.CalendarFeedRepository
+	peminjamanRepo   repositories.PeminjamanRepository
+	jadwalRepo       repositories.JadwalRepository
+	labRepo          repositories.LabRepository
+	feedBaseURL      string
+}
+
+func NewCalendarFeedUsecase(calendarFeedRepo repositories.CalendarFeedRepository, peminjamanRepo repositories.PeminjamanRepository, jadwalRepo repositories.JadwalRepository, labRepo repositories.LabRepository, feedBaseURL string) CalendarFeedUsecase {
+	return &calendarFeedUsecase{calendarFeedRepo, peminjamanRepo, jadwalRepo, labRepo, feedBaseURL}
+}
+
+// GetCalendarFeed mengembalikan feed kalender user, token dibuat saat pertama kali diminta
+func (u *calendarFeedUsecase) GetCalendarFeed(userID uint) (dtos.CalendarFeedResponse, error) {
+	calendarFeed, err := u.calendarFeedRepo.GetCalendarFeedByUserID(userID)
+	if err == nil {
+		return u.toCalendarFeedResponse(calendarFeed), nil
+	}
+
+	token, err := generateCalendarFeedToken()
+	if err != nil {
+		return dtos.CalendarFeedResponse{}, errors.New("failed to generate calendar feed token")
+	}
+	calendarFeed, err = u.calendarFeedRepo.CreateCalendarFeed(models.CalendarFeed{UserID: userID, Token: token})
+	if err != nil {
+		// Permintaan bersamaan bisa lebih dulu membuat feed untuk user yang sama
+		if existing, getErr := u.calendarFeedRepo.GetCalendarFeedByUserID(userID); getErr == nil {
+			return u.toCalendarFeedResponse(existing), nil
+		}
+		return dtos.CalendarFeedResponse{}, errors.New("failed to create calendar feed")
+	}
+
+	return u.toCalendarFeedResponse(calendarFeed), nil
+}
+
+// RotateCalendarFeed mengganti token sehingga alamat feed lama tidak bisa dipakai lagi
+func (u *calendarFeedUsecase) RotateCalendarFeed(userID uint) (dtos.CalendarFeedResponse, error) {
+	calendarFeed, err := u.calendarFeedRepo.GetCalendarFeedByUserID(userID)
+	if err != nil {
+		return u.GetCalendarFeed(userID)
+	}
+
+	token, err := generateCalendarFeedToken()
+	if err != nil {
+		return dtos.CalendarFeedResponse{}, errors.New("failed to generate calendar feed token")
+	}
+	now := time.Now()
+	calendarFeed.Token = token
+	calendarFeed.RotatedAt = &now
+
+	calendarFeed, err = u.calendarFeedRepo.UpdateCalendarFeed(calendarFeed)
+	if err != nil {
+		return dtos.CalendarFeedResponse{}, errors.New("failed to rotate calendar feed")
+	}
+
+	return u.toCalendarFeedResponse(calendarFeed), nil
+}
+
+// GetUserCalendar membuat feed .ics berisi peminjaman user, termasuk peminjaman kelompok yang diikutinya
+func (u *calendarFeedUsecase) GetUserCalendar(token string) ([]byte, error) {
+	calendarFeed, err := u.calendarFeedRepo.GetCalendarFeedByToken(strings.TrimSuffix(token, ".ics"))
+	if err != nil {
+		return nil, errors.New("feed kalender tidak ditemukan, pastikan token benar")
+	}
+
+	from := time.Now().AddDate(0, 0, -calendarFeedHistoryDays)
+	peminjamans, err := u.peminjamanRepo.GetCalendarPeminjamans(calendarFeed.UserID, 0, calendarPeminjamanStatuses, from)
+	if err != nil {
+		return nil, errors.New("failed to get peminjaman")
+	}
+
+	events := []helpers.ICSEvent{}
+	for _, peminjaman := range peminjamans {
+		if event, ok := peminjamanICSEvent(peminjaman, true); ok {
+			events = append(events, event)
+		}
+	}
+
+	return writeCalendar("Peminjaman Lab Saya", events)
+}
+
+// GetLabCalendar membuat feed .ics publik berisi jadwal dan peminjaman yang diterima di satu lab.
+// Detail peminjam tidak dicantumkan karena feed ini bisa dibaca tanpa login.
+func (u *calendarFeedUsecase) GetLabCalendar(labID uint) ([]byte, error) {
+	lab, err := u.labRepo.GetLabByID(labID)
+	if err != nil {
+		return nil, errors.New("lab tidak ditemukan, pastikan ID benar")
+	}
+
+	from := time.Now().AddDate(0, 0, -calendarFeedHistoryDays)
+	jadwals, err := u.jadwalRepo.GetCalendarJadwals(lab.ID, from)
+	if err != nil {
+		return nil, errors.New("failed to get jadwal")
+	}
+	peminjamans, err := u.peminjamanRepo.GetCalendarPeminjamans(0, lab.ID, calendarPeminjamanStatuses, from)
+	if err != nil {
+		return nil, errors.New("failed to get peminjaman")
+	}
+
+	events := []helpers.ICSEvent{}
+	for _, jadwal := range jadwals {
+		if event, ok := jadwalICSEvent(jadwal); ok {
+			events = append(events, event)
+		}
+	}
+	for _, peminjaman := range peminjamans {
+		if event, ok := peminjamanICSEvent(peminjaman, false); ok {
+			events = append(events, event)
+		}
+	}
+
+	return writeCalendar("Jadwal "+lab.Name, events)
+}
+
+func (u *calendarFeedUsecase) toCalendarFeedResponse(calendarFeed models.CalendarFeed) dtos.CalendarFeedResponse {
+	return dtos.CalendarFeedResponse{
+		Token:     calendarFeed.Token,
+		FeedURL:   fmt.Sprintf("%s/api/v1/public/calendar/user/%s.ics", u.feedBaseURL, calendarFeed.Token),
+		RotatedAt: calendarFeed.RotatedAt,
+		CreatedAt: calendarFeed.CreatedAt,
+	}
+}
+
+// peminjamanICSEvent mengubah peminjaman menjadi event. withDetail menambahkan keperluan peminjaman
+// yang hanya ditampilkan di feed pribadi.
+func peminjamanICSEvent(peminjaman models.Peminjaman, withDetail bool) (helpers.ICSEvent, bool) {
+	start, end, ok := calendarEventTime(peminjaman.TanggalPeminjaman, peminjaman.JamPeminjaman, peminjaman.JamSelesai)
+	if !ok {
+		return helpers.ICSEvent{}, false
+	}
+
+	event := helpers.ICSEvent{
+		UID:      fmt.Sprintf("peminjaman-%d@sistem-peminjaman", peminjaman.ID),
+		Summary:  "Peminjaman " + peminjaman.Lab.Name,
+		Location: peminjaman.Lab.Name,
+		Status:   helpers.ICSStatusConfirmed,
+		Start:    start,
+		End:      end,
+		Stamp:    peminjaman.UpdatedAt,
+	}
+	if withDetail {
+		event.Description = peminjaman.Description
+	}
+	if peminjaman.Status == models.PeminjamanStatusCancelled {
+		event.Summary = "[Dibatalkan] " + event.Summary
+		event.Status = helpers.ICSStatusCancelled
+		if withDetail && peminjaman.CancelReason != "" {
+			event.Description = "Alasan pembatalan: " + peminjaman.CancelReason
+		}
+	}
+	return event, true
+}
+
+// jadwalICSEvent mengubah jadwal menjadi event feed lab publik, nama pemilik jadwal tidak dicantumkan
+func jadwalICSEvent(jadwal models.Jadwal) (helpers.ICSEvent, bool) {
+	start, end, ok := calendarEventTime(jadwal.TanggalJadwal, jadwal.WaktuJadwal, jadwal.WaktuSelesai)
+	if !ok {
+		return helpers.ICSEvent{}, false
+	}
+
+	event := helpers.ICSEvent{
+		UID:     fmt.Sprintf("jadwal-%d@sistem-peminjaman", jadwal.ID),
+		Summary: "Jadwal " + jadwal.NameLaboratorium,
+		Status:  helpers.ICSStatusConfirmed,
+		Start:   start,
+		End:     end,
+		Stamp:   jadwal.UpdatedAt,
+	}
+	if jadwal.Lab != nil {
+		event.Summary = "Jadwal " + jadwal.Lab.Name
+		event.Location = jadwal.Lab.Name
+	}
+	if jadwal.Status == models.JadwalStatusCancelled {
+		event.Summary = "[Dibatalkan] " + event.Summary
+		event.Status = helpers.ICSStatusCancelled
+	}
+	return event, true
+}
+
+// calendarEventTime menggabungkan tanggal dan jam HH:MM menjadi waktu Asia/Jakarta
+func calendarEventTime(tanggal *time.Time, jamMulai, jamSelesai string) (time.Time, time.Time, bool) {
+	if tanggal == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	location := helpers.JakartaLocation()
+	date := tanggal.Format("2006-01-02")
+	start, err := time.ParseInLocation("2006-01-02 15:04", date+" "+jamMulai, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.ParseInLocation("2006-01-02 15:04", date+" "+jamSelesai, location)
+	if err != nil || !end.After(start) {
+		end = start.Add(time.Hour)
+	}
+	return start, end, true
+}
+
+func writeCalendar(name string, events []helpers.ICSEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := helpers.WriteICSCalendar(&buf, name, events); err != nil {
+		return nil, errors.New("failed to generate calendar")
+	}
+	return buf.Bytes(), nil
+}
+
+func generateCalendarFeedToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
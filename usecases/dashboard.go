@@ -165,7 +165,7 @@ func (u *dashboardUsecase) DashboardGetAll() (dtos.DashboardResponse, error) {
 
 func (u *dashboardUsecase) DashboardGetByMonth(month, year int) (dtos.DashboardfilterResponse, error) {
 	var dashboardResponse dtos.DashboardfilterResponse
-	countUser, countLab, countPeminjaman, countJadwal, countPeminjamanLabElektronika, countPeminjamanLabTransmisi, countPeminjamanLabJaringan, countJadwalLabElektronika, countJadwalLabTransmisi, countJadwalLabJaringan, err := u.dashboardRepository.DashboardGetByMonth(month, year)
+	countUser, countLab, countPeminjaman, countJadwal, peminjamanPerLab, jadwalPerLab, err := u.dashboardRepository.DashboardGetByMonth(month, year)
 	if err != nil {
 		return dashboardResponse, err
 	}
@@ -177,19 +177,21 @@ func (u *dashboardUsecase) DashboardGetByMonth(month, year int) (dtos.Dashboardf
 		CountLab: echo.Map{
 			"total_lab": countLab,
 		},
-		CountPeminjaman: echo.Map{
-			"total_peminjaman":               countPeminjaman,
-			"total_peminjaman_lab_elektronika": countPeminjamanLabElektronika,
-			"total_peminjaman_lab_transmisi":   countPeminjamanLabTransmisi,
-			"total_peminjaman_lab_jaringan":    countPeminjamanLabJaringan,
-		},
-		CountJadwal: echo.Map{
-			"total_jadwal":               countJadwal,
-			"total_jadwal_lab_elektronika": countJadwalLabElektronika,
-			"total_jadwal_lab_transmisi":   countJadwalLabTransmisi,
-			"total_jadwal_lab_jaringan":    countJadwalLabJaringan,
-		},
+		CountPeminjaman: countPerLabMap("total_peminjaman", countPeminjaman, peminjamanPerLab),
+		CountJadwal:     countPerLabMap("total_jadwal", countJadwal, jadwalPerLab),
 	}
 
 	return dashboardResponse, nil
 }
+
+// countPerLabMap menyusun total keseluruhan dan total per lab. Setiap lab dikenali lewat lab_id di per_lab,
+// bukan lewat namanya, sehingga mengganti nama lab tidak mengubah bentuk respons.
+func countPerLabMap(prefix string, total int, perLab []dtos.DashboardLabCount) echo.Map {
+	if perLab == nil {
+		perLab = []dtos.DashboardLabCount{}
+	}
+	return echo.Map{
+		prefix:    total,
+		"per_lab": perLab,
+	}
+}
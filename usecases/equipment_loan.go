@@ -0,0 +1,315 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"time"
+)
+
+type EquipmentLoanUsecase interface {
+	GetEquipmentLoans(page, limit int, userID uint, status string) ([]dtos.EquipmentLoanResponse, int, error)
+	GetEquipmentLoanByID(userID, id uint) (dtos.EquipmentLoanResponse, error)
+	CreateEquipmentLoan(userID uint, input dtos.EquipmentLoanInput) (dtos.EquipmentLoanResponse, error)
+	CancelEquipmentLoan(userID, id uint) (dtos.EquipmentLoanResponse, error)
+	DecideEquipmentLoan(adminID, id uint, input dtos.EquipmentLoanDecisionInput) (dtos.EquipmentLoanResponse, error)
+	HandOverEquipmentLoan(adminID, id uint, input dtos.EquipmentLoanHandoverInput) (dtos.EquipmentLoanResponse, error)
+	ReturnEquipmentLoan(adminID, id uint) (dtos.EquipmentLoanResponse, error)
+}
+
+type equipmentLoanUsecase struct {
+	equipmentLoanRepo   repositories.EquipmentLoanRepository
+	inventoryRepo       repositories.InventoryRepository
+	labRepo             repositories.LabRepository
+	userRepo            repositories.UserRepository
+	templateMessageRepo repositories.TemplateMessageRepository
+	notificationRepo    repositories.NotificationRepository
+	damageCaseRepo      repositories.DamageCaseRepository
+	penaltyRepo         repositories.PenaltyRepository
+	penaltyPolicy       PenaltyPolicy
+}
+
+func NewEquipmentLoanUsecase(equipmentLoanRepo repositories.EquipmentLoanRepository, inventoryRepo repositories.InventoryRepository, labRepo repositories.LabRepository, userRepo repositories.UserRepository, templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, damageCaseRepo repositories.DamageCaseRepository, penaltyRepo repositories.PenaltyRepository, penaltyPolicy PenaltyPolicy) EquipmentLoanUsecase {
+	return &equipmentLoanUsecase{equipmentLoanRepo, inventoryRepo, labRepo, userRepo, templateMessageRepo, notificationRepo, damageCaseRepo, penaltyRepo, penaltyPolicy}
+}
+
+// GetEquipmentLoans mengambil peminjaman alat milik user, userID 0 untuk admin
+func (u *equipmentLoanUsecase) GetEquipmentLoans(page, limit int, userID uint, status string) ([]dtos.EquipmentLoanResponse, int, error) {
+	var equipmentLoanResponses []dtos.EquipmentLoanResponse
+
+	equipmentLoans, count, err := u.equipmentLoanRepo.GetEquipmentLoans(page, limit, userID, status)
+	if err != nil {
+		return equipmentLoanResponses, 0, err
+	}
+
+	for _, equipmentLoan := range equipmentLoans {
+		equipmentLoanResponse, err := u.toEquipmentLoanResponse(equipmentLoan, userID == 0)
+		if err != nil {
+			return equipmentLoanResponses, 0, err
+		}
+		equipmentLoanResponses = append(equipmentLoanResponses, equipmentLoanResponse)
+	}
+
+	return equipmentLoanResponses, count, nil
+}
+
+func (u *equipmentLoanUsecase) GetEquipmentLoanByID(userID, id uint) (dtos.EquipmentLoanResponse, error) {
+	equipmentLoan, err := u.equipmentLoanRepo.GetEquipmentLoanByID(id, userID)
+	if err != nil {
+		return dtos.EquipmentLoanResponse{}, errors.New("peminjaman alat tidak ditemukan, pastikan ID benar")
+	}
+	return u.toEquipmentLoanResponse(equipmentLoan, userID == 0)
+}
+
+func (u *equipmentLoanUsecase) CreateEquipmentLoan(userID uint, input dtos.EquipmentLoanInput) (dtos.EquipmentLoanResponse, error) {
+	var equipmentLoanResponse dtos.EquipmentLoanResponse
+
+	if err := checkBorrowingStanding(u.damageCaseRepo, u.penaltyRepo, u.penaltyPolicy, userID); err != nil {
+		return equipmentLoanResponse, err
+	}
+
+	getLab, err := u.labRepo.GetLabByID(input.LabID)
+	if err != nil {
+		return equipmentLoanResponse, errors.New("lab tidak ditemukan, pastikan lab_id benar")
+	}
+
+	if input.TanggalPinjam == nil || input.TanggalKembali == nil {
+		return equipmentLoanResponse, errors.New("tanggal pinjam dan tanggal kembali wajib diisi")
+	}
+	if *input.TanggalPinjam < time.Now().Format("2006-01-02") {
+		return equipmentLoanResponse, errors.New("tanggal pinjam invalid")
+	}
+	tanggalPinjam, err := time.Parse("2006-01-02", *input.TanggalPinjam)
+	if err != nil {
+		return equipmentLoanResponse, errors.New("failed to parse tanggal pinjam")
+	}
+	tanggalKembali, err := time.Parse("2006-01-02", *input.TanggalKembali)
+	if err != nil {
+		return equipmentLoanResponse, errors.New("failed to parse tanggal kembali")
+	}
+	if tanggalKembali.Before(tanggalPinjam) {
+		return equipmentLoanResponse, errors.New("tanggal kembali harus sama atau setelah tanggal pinjam")
+	}
+
+	if len(input.Items) == 0 {
+		return equipmentLoanResponse, errors.New("minimal satu alat harus dipinjam")
+	}
+
+	// Item dengan jenis alat yang sama digabung agar pengecekan stok dan serah terima konsisten
+	quantities := map[uint]int{}
+	var order []uint
+	for _, item := range input.Items {
+		if item.Quantity <= 0 {
+			return equipmentLoanResponse, errors.New("jumlah alat harus lebih dari 0")
+		}
+		if _, ok := quantities[item.EquipmentTypeID]; !ok {
+			order = append(order, item.EquipmentTypeID)
+		}
+		quantities[item.EquipmentTypeID] += item.Quantity
+	}
+
+	var items []models.EquipmentLoanItem
+	for _, equipmentTypeID := range order {
+		equipmentType, err := u.inventoryRepo.GetEquipmentTypeByID(equipmentTypeID)
+		if err != nil {
+			return equipmentLoanResponse, fmt.Errorf("jenis alat %d tidak ditemukan", equipmentTypeID)
+		}
+
+		bookable, err := u.equipmentLoanRepo.GetBookableQuantity(getLab.ID, equipmentTypeID, tanggalPinjam, tanggalKembali, 0)
+		if err != nil {
+			return equipmentLoanResponse, err
+		}
+		if bookable < quantities[equipmentTypeID] {
+			return equipmentLoanResponse, fmt.Errorf("%w: %s hanya tersedia %d unit", helpers.ErrStockInsufficient, equipmentType.Name, bookable)
+		}
+
+		items = append(items, models.EquipmentLoanItem{
+			EquipmentTypeID: equipmentTypeID,
+			EquipmentType:   equipmentType,
+			Quantity:        quantities[equipmentTypeID],
+		})
+	}
+
+	createdEquipmentLoan, err := u.equipmentLoanRepo.CreateEquipmentLoan(models.EquipmentLoan{
+		UserID:         userID,
+		LabID:          getLab.ID,
+		TanggalPinjam:  &tanggalPinjam,
+		TanggalKembali: &tanggalKembali,
+		Description:    input.Description,
+		Status:         models.EquipmentLoanStatusRequest,
+		Items:          items,
+	})
+	if err != nil {
+		return equipmentLoanResponse, errors.New("failed to create equipment loan")
+	}
+
+	content := fmt.Sprintf("Ada pengajuan peminjaman alat baru di %s untuk tanggal %s sampai %s.",
+		getLab.Name, *input.TanggalPinjam, *input.TanggalKembali)
+	if err := notifyAdmins(u.templateMessageRepo, u.notificationRepo, u.userRepo, "Pengajuan Peminjaman Alat", content); err != nil {
+		log.Printf("gagal mengirim notifikasi peminjaman alat %d: %v", createdEquipmentLoan.ID, err)
+	}
+
+	return u.toEquipmentLoanResponse(createdEquipmentLoan, false)
+}
+
+func (u *equipmentLoanUsecase) CancelEquipmentLoan(userID, id uint) (dtos.EquipmentLoanResponse, error) {
+	equipmentLoan, err := u.equipmentLoanRepo.GetEquipmentLoanByID(id, userID)
+	if err != nil {
+		return dtos.EquipmentLoanResponse{}, errors.New("peminjaman alat tidak ditemukan, pastikan ID benar")
+	}
+	if equipmentLoan.Status != models.EquipmentLoanStatusRequest && equipmentLoan.Status != models.EquipmentLoanStatusAccept {
+		return dtos.EquipmentLoanResponse{}, fmt.Errorf("%w: peminjaman alat yang sudah diserahkan atau selesai tidak bisa dibatalkan", helpers.ErrInvalidStatusTransition)
+	}
+
+	fromStatus := equipmentLoan.Status
+	equipmentLoan.Status = models.EquipmentLoanStatusCancelled
+	updatedEquipmentLoan, err := u.equipmentLoanRepo.UpdateEquipmentLoanStatus(equipmentLoan, fromStatus)
+	if err != nil {
+		return dtos.EquipmentLoanResponse{}, err
+	}
+
+	return u.toEquipmentLoanResponse(updatedEquipmentLoan, false)
+}
+
+// DecideEquipmentLoan menerima atau menolak pengajuan peminjaman alat. Penerimaan mengecek ulang stok.
+func (u *equipmentLoanUsecase) DecideEquipmentLoan(adminID, id uint, input dtos.EquipmentLoanDecisionInput) (dtos.EquipmentLoanResponse, error) {
+	equipmentLoan, err := u.equipmentLoanRepo.GetEquipmentLoanByID(id, 0)
+	if err != nil {
+		return dtos.EquipmentLoanResponse{}, errors.New("peminjaman alat tidak ditemukan, pastikan ID benar")
+	}
+	if equipmentLoan.Status != models.EquipmentLoanStatusRequest {
+		return dtos.EquipmentLoanResponse{}, fmt.Errorf("%w: peminjaman alat sudah diputuskan", helpers.ErrInvalidStatusTransition)
+	}
+
+	now := time.Now()
+	equipmentLoan.AdminNote = input.Note
+	equipmentLoan.DecidedByID = &adminID
+	equipmentLoan.DecidedAt = &now
+
+	var updatedEquipmentLoan models.EquipmentLoan
+	switch input.Decision {
+	case "approve":
+		updatedEquipmentLoan, err = u.equipmentLoanRepo.AcceptEquipmentLoan(equipmentLoan, "admin_note", "decided_by_id", "decided_at")
+	case "reject":
+		equipmentLoan.Status = models.EquipmentLoanStatusReject
+		updatedEquipmentLoan, err = u.equipmentLoanRepo.UpdateEquipmentLoanStatus(equipmentLoan, models.EquipmentLoanStatusRequest, "admin_note", "decided_by_id", "decided_at")
+	default:
+		return dtos.EquipmentLoanResponse{}, errors.New("decision harus approve atau reject")
+	}
+	if err != nil {
+		return dtos.EquipmentLoanResponse{}, err
+	}
+
+	title := "Peminjaman Alat Diterima"
+	if updatedEquipmentLoan.Status == models.EquipmentLoanStatusReject {
+		title = "Peminjaman Alat Ditolak"
+	}
+	content := fmt.Sprintf("Peminjaman alat tanggal %s sampai %s berstatus %s. %s",
+		helpers.FormatDateToYMD(updatedEquipmentLoan.TanggalPinjam), helpers.FormatDateToYMD(updatedEquipmentLoan.TanggalKembali), updatedEquipmentLoan.Status, input.Note)
+	if err := notifyUsers(u.templateMessageRepo, u.notificationRepo, []uint{updatedEquipmentLoan.UserID}, title, content); err != nil {
+		log.Printf("gagal mengirim notifikasi peminjaman alat %d: %v", updatedEquipmentLoan.ID, err)
+	}
+
+	return u.toEquipmentLoanResponse(updatedEquipmentLoan, true)
+}
+
+func (u *equipmentLoanUsecase) HandOverEquipmentLoan(adminID, id uint, input dtos.EquipmentLoanHandoverInput) (dtos.EquipmentLoanResponse, error) {
+	equipmentLoan, err := u.equipmentLoanRepo.GetEquipmentLoanByID(id, 0)
+	if err != nil {
+		return dtos.EquipmentLoanResponse{}, errors.New("peminjaman alat tidak ditemukan, pastikan ID benar")
+	}
+	if equipmentLoan.Status != models.EquipmentLoanStatusAccept {
+		return dtos.EquipmentLoanResponse{}, fmt.Errorf("%w: hanya peminjaman alat yang diterima yang bisa diserahkan", helpers.ErrInvalidStatusTransition)
+	}
+
+	now := time.Now()
+	equipmentLoan.Status = models.EquipmentLoanStatusHandedOver
+	equipmentLoan.HandedOverByID = &adminID
+	equipmentLoan.HandedOverAt = &now
+
+	if _, err := u.equipmentLoanRepo.HandOverEquipmentLoan(equipmentLoan, input.UnitIDs); err != nil {
+		return dtos.EquipmentLoanResponse{}, err
+	}
+
+	return u.GetEquipmentLoanByID(0, id)
+}
+
+func (u *equipmentLoanUsecase) ReturnEquipmentLoan(adminID, id uint) (dtos.EquipmentLoanResponse, error) {
+	equipmentLoan, err := u.equipmentLoanRepo.GetEquipmentLoanByID(id, 0)
+	if err != nil {
+		return dtos.EquipmentLoanResponse{}, errors.New("peminjaman alat tidak ditemukan, pastikan ID benar")
+	}
+	if equipmentLoan.Status != models.EquipmentLoanStatusHandedOver {
+		return dtos.EquipmentLoanResponse{}, fmt.Errorf("%w: hanya peminjaman alat yang sudah diserahkan yang bisa dikembalikan", helpers.ErrInvalidStatusTransition)
+	}
+
+	now := time.Now()
+	equipmentLoan.Status = models.EquipmentLoanStatusReturned
+	equipmentLoan.ReturnedByID = &adminID
+	equipmentLoan.ReturnedAt = &now
+
+	if _, err := u.equipmentLoanRepo.ReturnEquipmentLoan(equipmentLoan); err != nil {
+		return dtos.EquipmentLoanResponse{}, err
+	}
+	issuePenaltyAsync(u.penaltyRepo, u.templateMessageRepo, u.notificationRepo, u.penaltyPolicy, lateReturnPenalty(u.penaltyPolicy, equipmentLoan, now))
+
+	return u.GetEquipmentLoanByID(0, id)
+}
+
+func (u *equipmentLoanUsecase) toEquipmentLoanResponse(equipmentLoan models.EquipmentLoan, withUser bool) (dtos.EquipmentLoanResponse, error) {
+	var equipmentLoanResponse dtos.EquipmentLoanResponse
+
+	getLab, err := u.labRepo.GetLabByID2(equipmentLoan.LabID)
+	if err != nil {
+		return equipmentLoanResponse, errors.New("failed to get lab")
+	}
+
+	equipmentLoanResponse = dtos.EquipmentLoanResponse{
+		EquipmentLoanID: equipmentLoan.ID,
+		TanggalPinjam:   helpers.FormatDateToYMD(equipmentLoan.TanggalPinjam),
+		TanggalKembali:  helpers.FormatDateToYMD(equipmentLoan.TanggalKembali),
+		Description:     equipmentLoan.Description,
+		Status:          equipmentLoan.Status,
+		AdminNote:       equipmentLoan.AdminNote,
+		DecidedAt:       equipmentLoan.DecidedAt,
+		HandedOverAt:    equipmentLoan.HandedOverAt,
+		ReturnedAt:      equipmentLoan.ReturnedAt,
+		Items:           []dtos.EquipmentLoanItemResponse{},
+		Lab: dtos.LabByIDResponses{
+			LabID:       getLab.ID,
+			Name:        getLab.Name,
+			Description: getLab.Description,
+		},
+		CreatedAt: equipmentLoan.CreatedAt,
+		UpdatedAt: equipmentLoan.UpdatedAt,
+	}
+
+	for _, item := range equipmentLoan.Items {
+		equipmentLoanResponse.Items = append(equipmentLoanResponse.Items, dtos.EquipmentLoanItemResponse{
+			EquipmentType: toEquipmentTypeResponse(item.EquipmentType),
+			Quantity:      item.Quantity,
+		})
+	}
+	for _, loanUnit := range equipmentLoan.Units {
+		equipmentLoanResponse.Units = append(equipmentLoanResponse.Units, toEquipmentUnitResponse(loanUnit.EquipmentUnit))
+	}
+
+	if withUser {
+		getUser, err := u.userRepo.UserGetById(equipmentLoan.UserID)
+		if err == nil {
+			equipmentLoanResponse.User = &dtos.UserInformationResponses{
+				ID:             getUser.ID,
+				FullName:       getUser.FullName,
+				Email:          getUser.Email,
+				NIMNIP:         getUser.NIMNIP,
+				ProfilePicture: getUser.ProfilePicture,
+			}
+		}
+	}
+
+	return equipmentLoanResponse, nil
+}
@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"errors"
+	"io"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/repositories"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var peminjamanExportHeader = []string{
+	"ID", "Nama", "NIM/NIP", "Lab", "Tanggal", "Jam Mulai", "Jam Selesai", "Status", "Keperluan",
+	"Diajukan Pada", "Diterima Pada", "Ditolak Pada", "Dibatalkan Pada",
+}
+
+var jadwalExportHeader = []string{
+	"ID", "Nama", "NIM/NIP", "Lab", "Tanggal", "Jam Mulai", "Jam Selesai", "Status", "ID Peminjaman",
+	"Dibuat Pada", "Diterima Pada",
+}
+
+type ExportUsecase interface {
+	ExportPeminjamans(w io.Writer, format string, filter dtos.PeminjamanFilter) error
+	ExportJadwals(w io.Writer, format, nameLaboratorium, from, to string) error
+}
+
+type exportUsecase struct {
+	peminjamanRepo repositories.PeminjamanRepository
+	jadwalRepo     repositories.JadwalRepository
+}
+
+func NewExportUsecase(peminjamanRepo repositories.PeminjamanRepository, jadwalRepo repositories.JadwalRepository) ExportUsecase {
+	return &exportUsecase{peminjamanRepo, jadwalRepo}
+}
+
+// ExportPeminjamans menulis peminjaman ke w dalam format csv atau xlsx dengan filter yang sama seperti
+// daftar peminjaman admin. Validasi dilakukan sebelum byte pertama ditulis agar controller masih bisa
+// membalas dengan error JSON.
+func (u *exportUsecase) ExportPeminjamans(w io.Writer, format string, filter dtos.PeminjamanFilter) error {
+	if err := validateExportFormat(format); err != nil {
+		return err
+	}
+	if err := validatePeminjamanFilter(filter); err != nil {
+		return err
+	}
+
+	tableWriter, err := helpers.NewTableWriter(w, format, "Peminjaman")
+	if err != nil {
+		return err
+	}
+	if err := tableWriter.WriteRow(peminjamanExportHeader); err != nil {
+		return err
+	}
+
+	err = u.peminjamanRepo.ExportPeminjamans(filter, func(row dtos.PeminjamanExportRow) error {
+		return tableWriter.WriteRow([]string{
+			strconv.FormatUint(uint64(row.ID), 10),
+			row.FullName,
+			row.NIMNIP,
+			row.LabName,
+			row.TanggalPeminjaman,
+			row.JamPeminjaman,
+			row.JamSelesai,
+			row.Status,
+			row.Description,
+			row.RequestedAt,
+			row.AcceptedAt,
+			row.RejectedAt,
+			row.CancelledAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return tableWriter.Close()
+}
+
+func (u *exportUsecase) ExportJadwals(w io.Writer, format, nameLaboratorium, from, to string) error {
+	if err := validateExportFormat(format); err != nil {
+		return err
+	}
+	fromDate, toDate, err := parseExportRange(from, to)
+	if err != nil {
+		return err
+	}
+
+	tableWriter, err := helpers.NewTableWriter(w, format, "Jadwal")
+	if err != nil {
+		return err
+	}
+	if err := tableWriter.WriteRow(jadwalExportHeader); err != nil {
+		return err
+	}
+
+	filter := dtos.JadwalExportFilter{NameLaboratorium: nameLaboratorium, From: fromDate, To: toDate}
+	err = u.jadwalRepo.ExportJadwals(filter, func(row dtos.JadwalExportRow) error {
+		peminjamanID := ""
+		if row.PeminjamanID != 0 {
+			peminjamanID = strconv.FormatUint(uint64(row.PeminjamanID), 10)
+		}
+		return tableWriter.WriteRow([]string{
+			strconv.FormatUint(uint64(row.ID), 10),
+			row.FullName,
+			row.NIMNIP,
+			row.LabName,
+			row.TanggalJadwal,
+			row.WaktuJadwal,
+			row.WaktuSelesai,
+			row.Status,
+			peminjamanID,
+			row.CreatedAt,
+			row.AcceptedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return tableWriter.Close()
+}
+
+func validateExportFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "", helpers.ExportFormatCSV, helpers.ExportFormatXLSX:
+		return nil
+	}
+	return errors.New("format export harus csv atau xlsx")
+}
+
+// parseExportRange membaca rentang tanggal from dan to (YYYY-MM-DD), keduanya opsional
+func parseExportRange(from, to string) (*time.Time, *time.Time, error) {
+	var fromDate, toDate *time.Time
+	if from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, nil, errors.New("tanggal from invalid, gunakan format YYYY-MM-DD")
+		}
+		fromDate = &parsed
+	}
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, nil, errors.New("tanggal to invalid, gunakan format YYYY-MM-DD")
+		}
+		toDate = &parsed
+	}
+	if fromDate != nil && toDate != nil && toDate.Before(*fromDate) {
+		return nil, nil, errors.New("tanggal to harus sama atau setelah tanggal from")
+	}
+	return fromDate, toDate, nil
+}
@@ -0,0 +1,477 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"strings"
+	"time"
+)
+
+type InspectionUsecase interface {
+	InspectPeminjaman(adminID, peminjamanID uint, input dtos.ReturnInspectionInput) (dtos.ReturnInspectionResponse, error)
+	GetPeminjamanInspections(peminjamanID uint) ([]dtos.ReturnInspectionResponse, error)
+	InspectEquipmentLoan(adminID, equipmentLoanID uint, input dtos.ReturnInspectionInput) (dtos.ReturnInspectionResponse, error)
+	GetEquipmentLoanInspections(equipmentLoanID uint) ([]dtos.ReturnInspectionResponse, error)
+	GetDamageCases(page, limit int, userID uint, status string) ([]dtos.DamageCaseResponse, int, error)
+	ResolveDamageCase(adminID, id uint, input dtos.DamageCaseResolveInput) (dtos.DamageCaseResponse, error)
+	GetDamageCaseReport(from, to string) (dtos.DamageCaseReportResponse, error)
+}
+
+type inspectionUsecase struct {
+	returnInspectionRepo      repositories.ReturnInspectionRepository
+	returnInspectionImageRepo repositories.ReturnInspectionImageRepository
+	damageCaseRepo            repositories.DamageCaseRepository
+	peminjamanRepo            repositories.PeminjamanRepository
+	equipmentLoanRepo         repositories.EquipmentLoanRepository
+	labRepo                   repositories.LabRepository
+	templateMessageRepo       repositories.TemplateMessageRepository
+	notificationRepo          repositories.NotificationRepository
+	penaltyRepo               repositories.PenaltyRepository
+	penaltyPolicy             PenaltyPolicy
+}
+
+func NewInspectionUsecase(returnInspectionRepo repositories.ReturnInspectionRepository, returnInspectionImageRepo repositories.ReturnInspectionImageRepository, damageCaseRepo repositories.DamageCaseRepository, peminjamanRepo repositories.PeminjamanRepository, equipmentLoanRepo repositories.EquipmentLoanRepository, labRepo repositories.LabRepository, templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, penaltyRepo repositories.PenaltyRepository, penaltyPolicy PenaltyPolicy) InspectionUsecase {
+	return &inspectionUsecase{returnInspectionRepo, returnInspectionImageRepo, damageCaseRepo, peminjamanRepo, equipmentLoanRepo, labRepo, templateMessageRepo, notificationRepo, penaltyRepo, penaltyPolicy}
+}
+
+// InspectPeminjaman mencatat kondisi ruangan setelah peminjaman selesai. Ruangan yang rusak
+// atau ada barang yang hilang langsung membuka kasus kerusakan atas nama peminjam.
+func (u *inspectionUsecase) InspectPeminjaman(adminID, peminjamanID uint, input dtos.ReturnInspectionInput) (dtos.ReturnInspectionResponse, error) {
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanID(peminjamanID)
+	if err != nil {
+		return dtos.ReturnInspectionResponse{}, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+	}
+	if peminjaman.Status != models.PeminjamanStatusFinished {
+		return dtos.ReturnInspectionResponse{}, fmt.Errorf("%w: hanya peminjaman yang sudah selesai yang bisa diperiksa", helpers.ErrInvalidStatusTransition)
+	}
+	if !isEquipmentCondition(input.Condition) {
+		return dtos.ReturnInspectionResponse{}, errors.New("kondisi harus good, damaged, atau missing")
+	}
+	if err := validateReturnInspectionImages(input.ReturnInspectionImage); err != nil {
+		return dtos.ReturnInspectionResponse{}, err
+	}
+
+	existing, err := u.returnInspectionRepo.GetReturnInspectionsByPeminjamanID(peminjaman.ID)
+	if err != nil {
+		return dtos.ReturnInspectionResponse{}, err
+	}
+	if len(existing) > 0 {
+		return dtos.ReturnInspectionResponse{}, errors.New("peminjaman sudah diperiksa")
+	}
+
+	var damageCases []models.DamageCase
+	if input.Condition != models.EquipmentConditionGood {
+		damageCases = append(damageCases, models.DamageCase{
+			UserID:      peminjaman.UserID,
+			LabID:       peminjaman.LabID,
+			Condition:   input.Condition,
+			Description: input.Notes,
+			Status:      models.DamageCaseStatusOpen,
+		})
+	}
+
+	return u.createReturnInspection(models.ReturnInspection{
+		PeminjamanID: &peminjaman.ID,
+		InspectorID:  adminID,
+		Condition:    input.Condition,
+		Notes:        input.Notes,
+		InspectedAt:  time.Now(),
+	}, damageCases, input.ReturnInspectionImage, peminjaman.UserID)
+}
+
+func (u *inspectionUsecase) GetPeminjamanInspections(peminjamanID uint) ([]dtos.ReturnInspectionResponse, error) {
+	returnInspections, err := u.returnInspectionRepo.GetReturnInspectionsByPeminjamanID(peminjamanID)
+	if err != nil {
+		return nil, err
+	}
+	return u.toReturnInspectionResponses(returnInspections)
+}
+
+// InspectEquipmentLoan mencatat kondisi setiap unit alat yang dikembalikan. Peminjaman yang masih
+// berstatus handed_over dikembalikan sekaligus. Unit yang tidak disebut di Items dianggap baik.
+func (u *inspectionUsecase) InspectEquipmentLoan(adminID, equipmentLoanID uint, input dtos.ReturnInspectionInput) (dtos.ReturnInspectionResponse, error) {
+	equipmentLoan, err := u.equipmentLoanRepo.GetEquipmentLoanByID(equipmentLoanID, 0)
+	if err != nil {
+		return dtos.ReturnInspectionResponse{}, errors.New("peminjaman alat tidak ditemukan, pastikan ID benar")
+	}
+	if equipmentLoan.Status != models.EquipmentLoanStatusHandedOver && equipmentLoan.Status != models.EquipmentLoanStatusReturned {
+		return dtos.ReturnInspectionResponse{}, fmt.Errorf("%w: hanya peminjaman alat yang sudah diserahkan yang bisa diperiksa", helpers.ErrInvalidStatusTransition)
+	}
+	if input.Condition != "" && !isEquipmentCondition(input.Condition) {
+		return dtos.ReturnInspectionResponse{}, errors.New("kondisi harus good, damaged, atau missing")
+	}
+	if err := validateReturnInspectionImages(input.ReturnInspectionImage); err != nil {
+		return dtos.ReturnInspectionResponse{}, err
+	}
+
+	existing, err := u.returnInspectionRepo.GetReturnInspectionsByEquipmentLoanID(equipmentLoan.ID)
+	if err != nil {
+		return dtos.ReturnInspectionResponse{}, err
+	}
+	if len(existing) > 0 {
+		return dtos.ReturnInspectionResponse{}, errors.New("peminjaman alat sudah diperiksa")
+	}
+
+	inputItems := map[uint]dtos.ReturnInspectionItemInput{}
+	for _, item := range input.Items {
+		if !isEquipmentCondition(item.Condition) {
+			return dtos.ReturnInspectionResponse{}, errors.New("kondisi unit harus good, damaged, atau missing")
+		}
+		inputItems[item.EquipmentUnitID] = item
+	}
+
+	condition := models.EquipmentConditionGood
+	if input.Condition != "" {
+		condition = input.Condition
+	}
+
+	var (
+		items       []models.ReturnInspectionItem
+		damageCases []models.DamageCase
+	)
+	for _, loanUnit := range equipmentLoan.Units {
+		item, ok := inputItems[loanUnit.EquipmentUnitID]
+		if !ok {
+			item = dtos.ReturnInspectionItemInput{EquipmentUnitID: loanUnit.EquipmentUnitID, Condition: models.EquipmentConditionGood}
+		}
+		delete(inputItems, loanUnit.EquipmentUnitID)
+
+		items = append(items, models.ReturnInspectionItem{
+			EquipmentUnitID: loanUnit.EquipmentUnitID,
+			Condition:       item.Condition,
+			Notes:           item.Notes,
+		})
+		condition = worseCondition(condition, item.Condition)
+
+		if item.Condition != models.EquipmentConditionGood {
+			equipmentUnitID := loanUnit.EquipmentUnitID
+			damageCases = append(damageCases, models.DamageCase{
+				UserID:          equipmentLoan.UserID,
+				LabID:           equipmentLoan.LabID,
+				EquipmentUnitID: &equipmentUnitID,
+				Condition:       item.Condition,
+				Description:     strings.TrimSpace(fmt.Sprintf("%s %s", loanUnit.EquipmentUnit.SerialNumber, item.Notes)),
+				Status:          models.DamageCaseStatusOpen,
+			})
+		}
+	}
+	for _, item := range input.Items {
+		if _, ok := inputItems[item.EquipmentUnitID]; ok {
+			return dtos.ReturnInspectionResponse{}, fmt.Errorf("unit %d bukan bagian dari peminjaman alat ini", item.EquipmentUnitID)
+		}
+	}
+
+	if equipmentLoan.Status == models.EquipmentLoanStatusHandedOver {
+		now := time.Now()
+		equipmentLoan.Status = models.EquipmentLoanStatusReturned
+		equipmentLoan.ReturnedByID = &adminID
+		equipmentLoan.ReturnedAt = &now
+		if _, err := u.equipmentLoanRepo.ReturnEquipmentLoan(equipmentLoan); err != nil {
+			return dtos.ReturnInspectionResponse{}, err
+		}
+		issuePenaltyAsync(u.penaltyRepo, u.templateMessageRepo, u.notificationRepo, u.penaltyPolicy, lateReturnPenalty(u.penaltyPolicy, equipmentLoan, now))
+	}
+
+	return u.createReturnInspection(models.ReturnInspection{
+		EquipmentLoanID: &equipmentLoan.ID,
+		InspectorID:     adminID,
+		Condition:       condition,
+		Notes:           input.Notes,
+		Items:           items,
+		InspectedAt:     time.Now(),
+	}, damageCases, input.ReturnInspectionImage, equipmentLoan.UserID)
+}
+
+func (u *inspectionUsecase) GetEquipmentLoanInspections(equipmentLoanID uint) ([]dtos.ReturnInspectionResponse, error) {
+	returnInspections, err := u.returnInspectionRepo.GetReturnInspectionsByEquipmentLoanID(equipmentLoanID)
+	if err != nil {
+		return nil, err
+	}
+	return u.toReturnInspectionResponses(returnInspections)
+}
+
+// GetDamageCases mengambil kasus kerusakan milik user, userID 0 untuk admin
+func (u *inspectionUsecase) GetDamageCases(page, limit int, userID uint, status string) ([]dtos.DamageCaseResponse, int, error) {
+	var damageCaseResponses []dtos.DamageCaseResponse
+
+	damageCases, count, err := u.damageCaseRepo.GetDamageCases(page, limit, userID, status)
+	if err != nil {
+		return damageCaseResponses, 0, err
+	}
+
+	for _, damageCase := range damageCases {
+		damageCaseResponses = append(damageCaseResponses, toDamageCaseResponse(damageCase))
+	}
+
+	return damageCaseResponses, count, nil
+}
+
+func (u *inspectionUsecase) ResolveDamageCase(adminID, id uint, input dtos.DamageCaseResolveInput) (dtos.DamageCaseResponse, error) {
+	damageCase, err := u.damageCaseRepo.GetDamageCaseByID(id)
+	if err != nil {
+		return dtos.DamageCaseResponse{}, errors.New("kasus kerusakan tidak ditemukan, pastikan ID benar")
+	}
+	if damageCase.Status != models.DamageCaseStatusOpen {
+		return dtos.DamageCaseResponse{}, fmt.Errorf("%w: kasus kerusakan sudah ditutup", helpers.ErrInvalidStatusTransition)
+	}
+	if input.Status != models.DamageCaseStatusResolved && input.Status != models.DamageCaseStatusWaived {
+		return dtos.DamageCaseResponse{}, errors.New("status harus resolved atau waived")
+	}
+
+	now := time.Now()
+	damageCase.Status = input.Status
+	damageCase.Resolution = input.Resolution
+	damageCase.ResolvedByID = &adminID
+	damageCase.ResolvedAt = &now
+
+	updatedDamageCase, err := u.damageCaseRepo.UpdateDamageCase(damageCase)
+	if err != nil {
+		return dtos.DamageCaseResponse{}, err
+	}
+
+	content := fmt.Sprintf("Kasus kerusakan #%d telah ditutup dengan status %s. %s", updatedDamageCase.ID, updatedDamageCase.Status, input.Resolution)
+	if err := notifyUsers(u.templateMessageRepo, u.notificationRepo, []uint{updatedDamageCase.UserID}, "Kasus Kerusakan Ditutup", content); err != nil {
+		log.Printf("gagal mengirim notifikasi kasus kerusakan %d: %v", updatedDamageCase.ID, err)
+	}
+
+	return toDamageCaseResponse(updatedDamageCase), nil
+}
+
+// GetDamageCaseReport merangkum kasus kerusakan per status, kondisi, dan lab. Default rentang 30 hari terakhir.
+func (u *inspectionUsecase) GetDamageCaseReport(from, to string) (dtos.DamageCaseReportResponse, error) {
+	var reportResponse dtos.DamageCaseReportResponse
+
+	toDate, err := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	if err != nil {
+		return reportResponse, err
+	}
+	if to != "" {
+		toDate, err = time.Parse("2006-01-02", to)
+		if err != nil {
+			return reportResponse, errors.New("tanggal to invalid, gunakan format YYYY-MM-DD")
+		}
+	}
+
+	fromDate := toDate.AddDate(0, 0, -29)
+	if from != "" {
+		fromDate, err = time.Parse("2006-01-02", from)
+		if err != nil {
+			return reportResponse, errors.New("tanggal from invalid, gunakan format YYYY-MM-DD")
+		}
+	}
+
+	if toDate.Before(fromDate) {
+		return reportResponse, errors.New("tanggal to harus setelah tanggal from")
+	}
+
+	summaries, err := u.damageCaseRepo.GetDamageCaseSummary(fromDate, toDate)
+	if err != nil {
+		return reportResponse, err
+	}
+
+	reportResponse = dtos.DamageCaseReportResponse{
+		From: fromDate.Format("2006-01-02"),
+		To:   toDate.Format("2006-01-02"),
+		Labs: []dtos.DamageCaseLabResponse{},
+	}
+
+	labIndex := map[uint]int{}
+	for _, summary := range summaries {
+		idx, ok := labIndex[summary.LabID]
+		if !ok {
+			getLab, err := u.labRepo.GetLabByID2(summary.LabID)
+			if err != nil {
+				return reportResponse, errors.New("failed to get lab")
+			}
+			reportResponse.Labs = append(reportResponse.Labs, dtos.DamageCaseLabResponse{
+				Lab: dtos.LabByIDResponses{
+					LabID:       getLab.ID,
+					Name:        getLab.Name,
+					Description: getLab.Description,
+				},
+			})
+			idx = len(reportResponse.Labs) - 1
+			labIndex[summary.LabID] = idx
+		}
+		labResponse := &reportResponse.Labs[idx]
+
+		reportResponse.Total += summary.Total
+		labResponse.Total += summary.Total
+
+		switch summary.Status {
+		case models.DamageCaseStatusOpen:
+			reportResponse.Open += summary.Total
+			labResponse.Open += summary.Total
+		case models.DamageCaseStatusResolved:
+			reportResponse.Resolved += summary.Total
+		case models.DamageCaseStatusWaived:
+			reportResponse.Waived += summary.Total
+		}
+
+		switch summary.Condition {
+		case models.EquipmentConditionDamaged:
+			reportResponse.Damaged += summary.Total
+			labResponse.Damaged += summary.Total
+		case models.EquipmentConditionMissing:
+			reportResponse.Missing += summary.Total
+			labResponse.Missing += summary.Total
+		}
+	}
+
+	return reportResponse, nil
+}
+
+// createReturnInspection menyimpan pemeriksaan beserta fotonya lalu memberi tahu peminjam jika ada kasus kerusakan
+func (u *inspectionUsecase) createReturnInspection(returnInspection models.ReturnInspection, damageCases []models.DamageCase, images []dtos.ReturnInspectionImageInput, borrowerID uint) (dtos.ReturnInspectionResponse, error) {
+	createdReturnInspection, err := u.returnInspectionRepo.CreateReturnInspection(returnInspection, damageCases)
+	if err != nil {
+		return dtos.ReturnInspectionResponse{}, errors.New("failed to create return inspection")
+	}
+
+	for _, image := range images {
+		returnInspectionImage := models.ReturnInspectionImage{
+			ReturnInspectionID:       createdReturnInspection.ID,
+			ReturnInspectionImageUrl: image.ReturnInspectionImageUrl,
+		}
+		if _, err := u.returnInspectionImageRepo.CreateReturnInspectionImage(returnInspectionImage); err != nil {
+			return dtos.ReturnInspectionResponse{}, errors.New("failed to create return inspection image")
+		}
+	}
+
+	createdDamageCases, err := u.damageCaseRepo.GetDamageCasesByReturnInspectionID(createdReturnInspection.ID)
+	if err != nil {
+		log.Printf("gagal mengambil kasus kerusakan pemeriksaan %d: %v", createdReturnInspection.ID, err)
+	}
+	for _, damageCase := range createdDamageCases {
+		points := u.penaltyPolicy.DamagePoints
+		if damageCase.Condition == models.EquipmentConditionMissing {
+			points = u.penaltyPolicy.MissingPoints
+		}
+		damageCaseID := damageCase.ID
+		issuePenaltyAsync(u.penaltyRepo, u.templateMessageRepo, u.notificationRepo, u.penaltyPolicy, models.Penalty{
+			UserID:          damageCase.UserID,
+			Source:          models.PenaltySourceDamage,
+			Points:          points,
+			Reason:          fmt.Sprintf("Kasus kerusakan #%d (%s) dari pemeriksaan pengembalian", damageCase.ID, damageCase.Condition),
+			PeminjamanID:    returnInspection.PeminjamanID,
+			EquipmentLoanID: returnInspection.EquipmentLoanID,
+			DamageCaseID:    &damageCaseID,
+		})
+	}
+
+	if len(damageCases) > 0 {
+		content := fmt.Sprintf("Pemeriksaan pengembalian menemukan %d kerusakan atau kehilangan. Pengajuan peminjaman baru ditangguhkan sampai kasus diselesaikan bersama admin.", len(damageCases))
+		if err := notifyUsers(u.templateMessageRepo, u.notificationRepo, []uint{borrowerID}, "Laporan Kerusakan", content); err != nil {
+			log.Printf("gagal mengirim notifikasi pemeriksaan %d: %v", createdReturnInspection.ID, err)
+		}
+	}
+
+	getReturnInspection, err := u.returnInspectionRepo.GetReturnInspectionByID(createdReturnInspection.ID)
+	if err != nil {
+		return dtos.ReturnInspectionResponse{}, errors.New("failed to get return inspection")
+	}
+
+	return u.toReturnInspectionResponse(getReturnInspection)
+}
+
+func (u *inspectionUsecase) toReturnInspectionResponses(returnInspections []models.ReturnInspection) ([]dtos.ReturnInspectionResponse, error) {
+	returnInspectionResponses := []dtos.ReturnInspectionResponse{}
+	for _, returnInspection := range returnInspections {
+		returnInspectionResponse, err := u.toReturnInspectionResponse(returnInspection)
+		if err != nil {
+			return returnInspectionResponses, err
+		}
+		returnInspectionResponses = append(returnInspectionResponses, returnInspectionResponse)
+	}
+	return returnInspectionResponses, nil
+}
+
+func (u *inspectionUsecase) toReturnInspectionResponse(returnInspection models.ReturnInspection) (dtos.ReturnInspectionResponse, error) {
+	returnInspectionResponse := dtos.ReturnInspectionResponse{
+		ReturnInspectionID:    returnInspection.ID,
+		PeminjamanID:          returnInspection.PeminjamanID,
+		EquipmentLoanID:       returnInspection.EquipmentLoanID,
+		InspectorID:           returnInspection.InspectorID,
+		Condition:             returnInspection.Condition,
+		Notes:                 returnInspection.Notes,
+		ReturnInspectionImage: []dtos.ReturnInspectionImageResponse{},
+		InspectedAt:           returnInspection.InspectedAt,
+	}
+
+	for _, item := range returnInspection.Items {
+		returnInspectionResponse.Items = append(returnInspectionResponse.Items, dtos.ReturnInspectionItemResponse{
+			EquipmentUnit: toEquipmentUnitResponse(item.EquipmentUnit),
+			Condition:     item.Condition,
+			Notes:         item.Notes,
+		})
+	}
+
+	getReturnInspectionImage, err := u.returnInspectionImageRepo.GetAllReturnInspectionImageByID(returnInspection.ID)
+	if err != nil {
+		return returnInspectionResponse, errors.New("failed to get return inspection image")
+	}
+	for _, returnInspectionImage := range getReturnInspectionImage {
+		returnInspectionResponse.ReturnInspectionImage = append(returnInspectionResponse.ReturnInspectionImage, dtos.ReturnInspectionImageResponse{
+			ReturnInspectionID:       returnInspectionImage.ReturnInspectionID,
+			ReturnInspectionImageUrl: returnInspectionImage.ReturnInspectionImageUrl,
+		})
+	}
+
+	damageCases, err := u.damageCaseRepo.GetDamageCasesByReturnInspectionID(returnInspection.ID)
+	if err != nil {
+		return returnInspectionResponse, errors.New("failed to get damage cases")
+	}
+	for _, damageCase := range damageCases {
+		returnInspectionResponse.DamageCases = append(returnInspectionResponse.DamageCases, toDamageCaseResponse(damageCase))
+	}
+
+	return returnInspectionResponse, nil
+}
+
+func toDamageCaseResponse(damageCase models.DamageCase) dtos.DamageCaseResponse {
+	damageCaseResponse := dtos.DamageCaseResponse{
+		DamageCaseID:       damageCase.ID,
+		ReturnInspectionID: damageCase.ReturnInspectionID,
+		UserID:             damageCase.UserID,
+		LabID:              damageCase.LabID,
+		Condition:          damageCase.Condition,
+		Description:        damageCase.Description,
+		Status:             damageCase.Status,
+		Resolution:         damageCase.Resolution,
+		ResolvedAt:         damageCase.ResolvedAt,
+		CreatedAt:          damageCase.CreatedAt,
+		UpdatedAt:          damageCase.UpdatedAt,
+	}
+	if damageCase.EquipmentUnit != nil {
+		equipmentUnitResponse := toEquipmentUnitResponse(*damageCase.EquipmentUnit)
+		damageCaseResponse.EquipmentUnit = &equipmentUnitResponse
+	}
+	return damageCaseResponse
+}
+
+func validateReturnInspectionImages(images []dtos.ReturnInspectionImageInput) error {
+	for _, image := range images {
+		if image.ReturnInspectionImageUrl == "" {
+			return errors.New("return inspection image URL is empty")
+		}
+	}
+	return nil
+}
+
+// worseCondition mengembalikan kondisi yang lebih buruk, urutannya good < damaged < missing
+func worseCondition(a, b string) string {
+	rank := map[string]int{
+		models.EquipmentConditionGood:    0,
+		models.EquipmentConditionDamaged: 1,
+		models.EquipmentConditionMissing: 2,
+	}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,344 @@
+package usecases
+
+import (
+	"errors"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"strings"
+)
+
+type InventoryUsecase interface {
+	GetEquipmentTypes(search string) ([]dtos.EquipmentTypeResponse, error)
+	CreateEquipmentType(input dtos.EquipmentTypeInput) (dtos.EquipmentTypeResponse, error)
+	UpdateEquipmentType(id uint, input dtos.EquipmentTypeInput) (dtos.EquipmentTypeResponse, error)
+	DeleteEquipmentType(id uint) error
+	GetEquipmentUnits(labID, equipmentTypeID uint) ([]dtos.EquipmentUnitResponse, error)
+	CreateEquipmentUnit(input dtos.EquipmentUnitInput) (dtos.EquipmentUnitResponse, error)
+	UpdateEquipmentUnit(id uint, input dtos.EquipmentUnitInput) (dtos.EquipmentUnitResponse, error)
+	DeleteEquipmentUnit(id uint) error
+	GetEquipmentStock(labID uint) ([]dtos.EquipmentStockResponse, error)
+	GetCatalogue(labID uint, search string) ([]dtos.EquipmentCatalogueResponse, error)
+}
+
+type inventoryUsecase struct {
+	inventoryRepo repositories.InventoryRepository
+	labRepo       repositories.LabRepository
+}
+
+func NewInventoryUsecase(inventoryRepo repositories.InventoryRepository, labRepo repositories.LabRepository) InventoryUsecase {
+	return &inventoryUsecase{inventoryRepo, labRepo}
+}
+
+func (u *inventoryUsecase) GetEquipmentTypes(search string) ([]dtos.EquipmentTypeResponse, error) {
+	var equipmentTypeResponses []dtos.EquipmentTypeResponse
+
+	equipmentTypes, err := u.inventoryRepo.GetEquipmentTypes(search)
+	if err != nil {
+		return equipmentTypeResponses, err
+	}
+
+	for _, equipmentType := range equipmentTypes {
+		equipmentTypeResponses = append(equipmentTypeResponses, toEquipmentTypeResponse(equipmentType))
+	}
+
+	return equipmentTypeResponses, nil
+}
+
+func (u *inventoryUsecase) CreateEquipmentType(input dtos.EquipmentTypeInput) (dtos.EquipmentTypeResponse, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return dtos.EquipmentTypeResponse{}, errors.New("nama jenis alat wajib diisi")
+	}
+
+	createdEquipmentType, err := u.inventoryRepo.CreateEquipmentType(models.EquipmentType{
+		Name:        input.Name,
+		Category:    input.Category,
+		Description: input.Description,
+		ImageUrl:    input.ImageUrl,
+	})
+	if err != nil {
+		return dtos.EquipmentTypeResponse{}, err
+	}
+
+	return toEquipmentTypeResponse(createdEquipmentType), nil
+}
+
+func (u *inventoryUsecase) UpdateEquipmentType(id uint, input dtos.EquipmentTypeInput) (dtos.EquipmentTypeResponse, error) {
+	equipmentType, err := u.inventoryRepo.GetEquipmentTypeByID(id)
+	if err != nil {
+		return dtos.EquipmentTypeResponse{}, errors.New("jenis alat tidak ditemukan, pastikan ID benar")
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return dtos.EquipmentTypeResponse{}, errors.New("nama jenis alat wajib diisi")
+	}
+
+	equipmentType.Name = input.Name
+	equipmentType.Category = input.Category
+	equipmentType.Description = input.Description
+	equipmentType.ImageUrl = input.ImageUrl
+
+	updatedEquipmentType, err := u.inventoryRepo.UpdateEquipmentType(equipmentType)
+	if err != nil {
+		return dtos.EquipmentTypeResponse{}, err
+	}
+
+	return toEquipmentTypeResponse(updatedEquipmentType), nil
+}
+
+func (u *inventoryUsecase) DeleteEquipmentType(id uint) error {
+	if _, err := u.inventoryRepo.GetEquipmentTypeByID(id); err != nil {
+		return errors.New("jenis alat tidak ditemukan, pastikan ID benar")
+	}
+	return u.inventoryRepo.DeleteEquipmentType(id)
+}
+
+func (u *inventoryUsecase) GetEquipmentUnits(labID, equipmentTypeID uint) ([]dtos.EquipmentUnitResponse, error) {
+	var equipmentUnitResponses []dtos.EquipmentUnitResponse
+
+	equipmentUnits, err := u.inventoryRepo.GetEquipmentUnits(labID, equipmentTypeID)
+	if err != nil {
+		return equipmentUnitResponses, err
+	}
+
+	for _, equipmentUnit := range equipmentUnits {
+		equipmentUnitResponses = append(equipmentUnitResponses, toEquipmentUnitResponse(equipmentUnit))
+	}
+
+	return equipmentUnitResponses, nil
+}
+
+func (u *inventoryUsecase) CreateEquipmentUnit(input dtos.EquipmentUnitInput) (dtos.EquipmentUnitResponse, error) {
+	equipmentUnit := models.EquipmentUnit{
+		Condition: models.EquipmentConditionGood,
+		Status:    models.EquipmentUnitStatusAvailable,
+	}
+	if err := u.applyEquipmentUnitInput(&equipmentUnit, input); err != nil {
+		return dtos.EquipmentUnitResponse{}, err
+	}
+
+	createdEquipmentUnit, err := u.inventoryRepo.CreateEquipmentUnit(equipmentUnit)
+	if err != nil {
+		return dtos.EquipmentUnitResponse{}, err
+	}
+
+	return toEquipmentUnitResponse(createdEquipmentUnit), nil
+}
+
+func (u *inventoryUsecase) UpdateEquipmentUnit(id uint, input dtos.EquipmentUnitInput) (dtos.EquipmentUnitResponse, error) {
+	equipmentUnit, err := u.inventoryRepo.GetEquipmentUnitByID(id)
+	if err != nil {
+		return dtos.EquipmentUnitResponse{}, errors.New("unit alat tidak ditemukan, pastikan ID benar")
+	}
+
+	// Status loaned hanya diatur oleh alur serah terima dan pengembalian
+	if equipmentUnit.Status == models.EquipmentUnitStatusLoaned && input.Status != "" && input.Status != models.EquipmentUnitStatusLoaned {
+		return dtos.EquipmentUnitResponse{}, errors.New("unit sedang dipinjam, status akan berubah saat dikembalikan")
+	}
+	if input.Status == models.EquipmentUnitStatusLoaned && equipmentUnit.Status != models.EquipmentUnitStatusLoaned {
+		return dtos.EquipmentUnitResponse{}, errors.New("status loaned hanya bisa diatur lewat serah terima peminjaman alat")
+	}
+
+	if err := u.applyEquipmentUnitInput(&equipmentUnit, input); err != nil {
+		return dtos.EquipmentUnitResponse{}, err
+	}
+
+	updatedEquipmentUnit, err := u.inventoryRepo.UpdateEquipmentUnit(equipmentUnit)
+	if err != nil {
+		return dtos.EquipmentUnitResponse{}, err
+	}
+
+	return toEquipmentUnitResponse(updatedEquipmentUnit), nil
+}
+
+func (u *inventoryUsecase) applyEquipmentUnitInput(equipmentUnit *models.EquipmentUnit, input dtos.EquipmentUnitInput) error {
+	equipmentType, err := u.inventoryRepo.GetEquipmentTypeByID(input.EquipmentTypeID)
+	if err != nil {
+		return errors.New("jenis alat tidak ditemukan, pastikan equipment_type_id benar")
+	}
+	if _, err := u.labRepo.GetLabByID(input.LabID); err != nil {
+		return errors.New("lab tidak ditemukan, pastikan lab_id benar")
+	}
+
+	serialNumber := strings.TrimSpace(input.SerialNumber)
+	if serialNumber == "" {
+		return errors.New("nomor seri wajib diisi")
+	}
+	existing, err := u.inventoryRepo.GetEquipmentUnitBySerialNumber(serialNumber)
+	if err == nil && existing.ID != equipmentUnit.ID {
+		return errors.New("nomor seri " + serialNumber + " sudah terdaftar")
+	}
+
+	if input.Condition != "" {
+		if !isEquipmentCondition(input.Condition) {
+			return errors.New("kondisi harus good, damaged, atau missing")
+		}
+		equipmentUnit.Condition = input.Condition
+	}
+	if input.Status != "" {
+		switch input.Status {
+		case models.EquipmentUnitStatusAvailable, models.EquipmentUnitStatusLoaned, models.EquipmentUnitStatusMaintenance, models.EquipmentUnitStatusRetired:
+			equipmentUnit.Status = input.Status
+		default:
+			return errors.New("status harus available, maintenance, atau retired")
+		}
+	}
+
+	equipmentUnit.EquipmentTypeID = equipmentType.ID
+	equipmentUnit.EquipmentType = equipmentType
+	equipmentUnit.LabID = input.LabID
+	equipmentUnit.SerialNumber = serialNumber
+	equipmentUnit.Notes = input.Notes
+	return nil
+}
+
+func (u *inventoryUsecase) DeleteEquipmentUnit(id uint) error {
+	equipmentUnit, err := u.inventoryRepo.GetEquipmentUnitByID(id)
+	if err != nil {
+		return errors.New("unit alat tidak ditemukan, pastikan ID benar")
+	}
+	if equipmentUnit.Status == models.EquipmentUnitStatusLoaned {
+		return errors.New("unit sedang dipinjam dan tidak bisa dihapus")
+	}
+	return u.inventoryRepo.DeleteEquipmentUnit(id)
+}
+
+func (u *inventoryUsecase) GetEquipmentStock(labID uint) ([]dtos.EquipmentStockResponse, error) {
+	var stockResponses []dtos.EquipmentStockResponse
+
+	stocks, err := u.inventoryRepo.GetEquipmentStock(labID)
+	if err != nil {
+		return stockResponses, err
+	}
+
+	labs := map[uint]dtos.LabByIDResponses{}
+	equipmentTypes := map[uint]dtos.EquipmentTypeResponse{}
+	for _, stock := range stocks {
+		lab, err := u.cachedLab(labs, stock.LabID)
+		if err != nil {
+			return stockResponses, err
+		}
+		equipmentType, err := u.cachedEquipmentType(equipmentTypes, stock.EquipmentTypeID)
+		if err != nil {
+			continue
+		}
+
+		stockResponses = append(stockResponses, dtos.EquipmentStockResponse{
+			Lab:           lab,
+			EquipmentType: equipmentType,
+			Total:         stock.Total,
+			Available:     stock.Available,
+			Loaned:        stock.Loaned,
+			Maintenance:   stock.Maintenance,
+		})
+	}
+
+	return stockResponses, nil
+}
+
+// GetCatalogue menampilkan jenis alat beserta jumlah unit yang siap dipinjam di setiap lab
+func (u *inventoryUsecase) GetCatalogue(labID uint, search string) ([]dtos.EquipmentCatalogueResponse, error) {
+	var catalogueResponses []dtos.EquipmentCatalogueResponse
+
+	equipmentTypes, err := u.inventoryRepo.GetEquipmentTypes(search)
+	if err != nil {
+		return catalogueResponses, err
+	}
+
+	stocks, err := u.inventoryRepo.GetEquipmentStock(labID)
+	if err != nil {
+		return catalogueResponses, err
+	}
+
+	stocksByType := map[uint][]dtos.EquipmentStock{}
+	for _, stock := range stocks {
+		stocksByType[stock.EquipmentTypeID] = append(stocksByType[stock.EquipmentTypeID], stock)
+	}
+
+	labs := map[uint]dtos.LabByIDResponses{}
+	for _, equipmentType := range equipmentTypes {
+		typeStocks, ok := stocksByType[equipmentType.ID]
+		if !ok {
+			continue
+		}
+
+		catalogueResponse := dtos.EquipmentCatalogueResponse{
+			EquipmentType: toEquipmentTypeResponse(equipmentType),
+			Stocks:        []dtos.EquipmentCatalogueStock{},
+		}
+		for _, stock := range typeStocks {
+			lab, err := u.cachedLab(labs, stock.LabID)
+			if err != nil {
+				return catalogueResponses, err
+			}
+			catalogueResponse.Stocks = append(catalogueResponse.Stocks, dtos.EquipmentCatalogueStock{
+				Lab:       lab,
+				Available: stock.Available,
+			})
+		}
+		catalogueResponses = append(catalogueResponses, catalogueResponse)
+	}
+
+	return catalogueResponses, nil
+}
+
+func (u *inventoryUsecase) cachedLab(labs map[uint]dtos.LabByIDResponses, labID uint) (dtos.LabByIDResponses, error) {
+	if lab, ok := labs[labID]; ok {
+		return lab, nil
+	}
+	getLab, err := u.labRepo.GetLabByID2(labID)
+	if err != nil {
+		return dtos.LabByIDResponses{}, errors.New("failed to get lab")
+	}
+	labs[labID] = dtos.LabByIDResponses{
+		LabID:       getLab.ID,
+		Name:        getLab.Name,
+		Description: getLab.Description,
+	}
+	return labs[labID], nil
+}
+
+func (u *inventoryUsecase) cachedEquipmentType(equipmentTypes map[uint]dtos.EquipmentTypeResponse, equipmentTypeID uint) (dtos.EquipmentTypeResponse, error) {
+	if equipmentType, ok := equipmentTypes[equipmentTypeID]; ok {
+		return equipmentType, nil
+	}
+	getEquipmentType, err := u.inventoryRepo.GetEquipmentTypeByID(equipmentTypeID)
+	if err != nil {
+		return dtos.EquipmentTypeResponse{}, err
+	}
+	equipmentTypes[equipmentTypeID] = toEquipmentTypeResponse(getEquipmentType)
+	return equipmentTypes[equipmentTypeID], nil
+}
+
+func isEquipmentCondition(condition string) bool {
+	switch condition {
+	case models.EquipmentConditionGood, models.EquipmentConditionDamaged, models.EquipmentConditionMissing:
+		return true
+	}
+	return false
+}
+
+func toEquipmentTypeResponse(equipmentType models.EquipmentType) dtos.EquipmentTypeResponse {
+	return dtos.EquipmentTypeResponse{
+		EquipmentTypeID: equipmentType.ID,
+		Name:            equipmentType.Name,
+		Category:        equipmentType.Category,
+		Description:     equipmentType.Description,
+		ImageUrl:        equipmentType.ImageUrl,
+		CreatedAt:       equipmentType.CreatedAt,
+		UpdatedAt:       equipmentType.UpdatedAt,
+	}
+}
+
+func toEquipmentUnitResponse(equipmentUnit models.EquipmentUnit) dtos.EquipmentUnitResponse {
+	return dtos.EquipmentUnitResponse{
+		EquipmentUnitID: equipmentUnit.ID,
+		EquipmentType:   toEquipmentTypeResponse(equipmentUnit.EquipmentType),
+		LabID:           equipmentUnit.LabID,
+		SerialNumber:    equipmentUnit.SerialNumber,
+		Condition:       equipmentUnit.Condition,
+		Status:          equipmentUnit.Status,
+		Notes:           equipmentUnit.Notes,
+		CreatedAt:       equipmentUnit.CreatedAt,
+		UpdatedAt:       equipmentUnit.UpdatedAt,
+	}
+}
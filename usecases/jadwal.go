@@ -26,10 +26,13 @@ type jadwalUsecase struct {
 	jadwalRepo               	  repositories.JadwalRepository
 	beritaAcaraImageRepo          repositories.BeritaAcaraImageRepository
 	userRepo                      repositories.UserRepository
+	labRepo                       repositories.LabRepository
+	labSlotRepo                   repositories.LabSlotRepository
+	labClosureRepo                repositories.LabClosureRepository
 }
 
-func NewJadwalUsecase(jadwalRepo repositories.JadwalRepository, beritaAcaraImageRepo repositories.BeritaAcaraImageRepository, userRepo repositories.UserRepository) JadwalUsecase {
-	return &jadwalUsecase{jadwalRepo, beritaAcaraImageRepo, userRepo}
+func NewJadwalUsecase(jadwalRepo repositories.JadwalRepository, beritaAcaraImageRepo repositories.BeritaAcaraImageRepository, userRepo repositories.UserRepository, labRepo repositories.LabRepository, labSlotRepo repositories.LabSlotRepository, labClosureRepo repositories.LabClosureRepository) JadwalUsecase {
+	return &jadwalUsecase{jadwalRepo, beritaAcaraImageRepo, userRepo, labRepo, labSlotRepo, labClosureRepo}
 }
 
 
@@ -41,25 +44,30 @@ func (u *jadwalUsecase) GetAllJadwals(page, limit int, name_laboratirum string)
 
 	var jadwalResponses []dtos.JadwalResponse
 
+	jadwalIDs := make([]uint, 0, len(jadwals))
 	for _, jadwal := range jadwals {
-		getBeritaAcaraImage, err := u.beritaAcaraImageRepo.GetAllBeritaAcaraImageByID(jadwal.ID)
-		if err != nil {
-			continue
-		}
+		jadwalIDs = append(jadwalIDs, jadwal.ID)
+	}
+	beritaAcaraImages, err := loadBeritaAcaraImageResponses(u.beritaAcaraImageRepo, jadwalIDs)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		var beritaAcaraImageResponses []dtos.BeritaAcaraImageResponse
-		for _, beritaacaraimage := range getBeritaAcaraImage {
-			beritaAcaraImageResponse := dtos.BeritaAcaraImageResponse{
-				JadwalID:  beritaacaraimage.JadwalID,
-				BeritaAcaraImageUrl: beritaacaraimage.BeritaAcaraImageUrl,
-			}
-			beritaAcaraImageResponses = append(beritaAcaraImageResponses, beritaAcaraImageResponse)
-		}
+	for _, jadwal := range jadwals {
+		beritaAcaraImageResponses := beritaAcaraImages[jadwal.ID]
 
 		jadwalResponse := dtos.JadwalResponse{
 			JadwalID:           int(jadwal.ID),
 			TanggalJadwal:      helpers.FormatDateToYMD(jadwal.TanggalJadwal),
 			WaktuJadwal:        jadwal.WaktuJadwal,
+			WaktuSelesai:       jadwal.WaktuSelesai,
+			LabSlotID:          jadwal.LabSlotID,
+			LabID:              jadwal.LabID,
+			UserID:             jadwal.UserID,
+			Lab:                toJadwalLabResponse(jadwal.Lab),
+			User:               toJadwalUserResponse(jadwal.User),
+			PeminjamanID:       jadwal.PeminjamanID,
+			Peminjaman:         toJadwalPeminjamanResponse(jadwal.Peminjaman),
 			NameUser:           jadwal.NameUser,
 			NameLaboratorium:   jadwal.NameLaboratorium,
 			BeritaAcaraImage:   beritaAcaraImageResponses,
@@ -100,6 +108,14 @@ func (u *jadwalUsecase) GetJadwalByID(userId, id uint) (dtos.JadwalResponse, err
 		JadwalID:           int(jadwal.ID),
 		TanggalJadwal:      helpers.FormatDateToYMD(jadwal.TanggalJadwal),
 		WaktuJadwal:        jadwal.WaktuJadwal,
+		WaktuSelesai:       jadwal.WaktuSelesai,
+		LabSlotID:          jadwal.LabSlotID,
+			LabID:              jadwal.LabID,
+			UserID:             jadwal.UserID,
+			Lab:                toJadwalLabResponse(jadwal.Lab),
+			User:               toJadwalUserResponse(jadwal.User),
+			PeminjamanID:       jadwal.PeminjamanID,
+			Peminjaman:         toJadwalPeminjamanResponse(jadwal.Peminjaman),
 		NameUser:           jadwal.NameUser,
 		NameLaboratorium:   jadwal.NameLaboratorium,
 		BeritaAcaraImage:   beritaAcaraImageResponses,
@@ -143,6 +159,14 @@ func (u *jadwalUsecase) AdminGetJadwalByID(jadwalId uint) (dtos.JadwalResponse,
         JadwalID:          int(jadwal.ID),
         TanggalJadwal:     helpers.FormatDateToYMD(jadwal.TanggalJadwal),
         WaktuJadwal:         jadwal.WaktuJadwal,
+        WaktuSelesai:       jadwal.WaktuSelesai,
+        LabSlotID:          jadwal.LabSlotID,
+			LabID:              jadwal.LabID,
+			UserID:             jadwal.UserID,
+			Lab:                toJadwalLabResponse(jadwal.Lab),
+			User:               toJadwalUserResponse(jadwal.User),
+			PeminjamanID:       jadwal.PeminjamanID,
+			Peminjaman:         toJadwalPeminjamanResponse(jadwal.Peminjaman),
 		NameUser:            jadwal.NameUser,
 		NameLaboratorium:    jadwal.NameLaboratorium,
         BeritaAcaraImage: beritaAcaraImageResponses,
@@ -183,6 +207,14 @@ func (u *jadwalUsecase) GetJadwalsDetailByAdmin(jadwalId uint) (dtos.JadwalRespo
 		JadwalID:          int(jadwal.ID),
 		TanggalJadwal:     helpers.FormatDateToYMD(jadwal.TanggalJadwal),
 		WaktuJadwal:         jadwal.WaktuJadwal,
+		WaktuSelesai:       jadwal.WaktuSelesai,
+		LabSlotID:          jadwal.LabSlotID,
+			LabID:              jadwal.LabID,
+			UserID:             jadwal.UserID,
+			Lab:                toJadwalLabResponse(jadwal.Lab),
+			User:               toJadwalUserResponse(jadwal.User),
+			PeminjamanID:       jadwal.PeminjamanID,
+			Peminjaman:         toJadwalPeminjamanResponse(jadwal.Peminjaman),
 		NameUser:            jadwal.NameUser,
 		NameLaboratorium:    jadwal.NameLaboratorium,
 		BeritaAcaraImage: beritaAcaraImageResponses,
@@ -216,20 +248,45 @@ func (u *jadwalUsecase) CreateJadwal(jadwal *dtos.JadwalInput) (dtos.JadwalRespo
 		return jadwalResponse, errors.New("Tanggal Jadwal harus setelah tanggal sekarang")
 	}
 
+	getLab, getUser, err := u.resolveJadwalLabUser(*jadwal)
+	if err != nil {
+		return jadwalResponse, err
+	}
+
+	// Memastikan waktu jadwal sesuai slot yang dikonfigurasi untuk lab pada hari tersebut
+	labSlot, err := resolveLabSlot(u.labSlotRepo, getLab.ID, tanggalJadwalParse, jadwal.WaktuJadwal, jadwal.LabSlotID)
+	if err != nil {
+		return jadwalResponse, err
+	}
+
+	// Jadwal tidak boleh dibuat saat lab ditutup atau hari libur
+	if err := checkLabClosure(u.labClosureRepo, getLab.ID, tanggalJadwalParse, labSlot.JamMulai, labSlot.JamSelesai); err != nil {
+		return jadwalResponse, err
+	}
+
 	// Membuat struktur Jadwal dari data input
 	createJadwal := models.Jadwal{
 		TanggalJadwal:    &tanggalJadwalParse,
-		WaktuJadwal:      jadwal.WaktuJadwal,
+		WaktuJadwal:      labSlot.JamMulai,
+		WaktuSelesai:     labSlot.JamSelesai,
+		LabSlotID:        &labSlot.ID,
+		LabID:            &getLab.ID,
 		NameUser:         jadwal.NameUser,
-		NameLaboratorium: jadwal.NameLaboratorium,
+		NameLaboratorium: getLab.Name,
 		Status:           "notused",
 	}
+	if getUser != nil {
+		createJadwal.UserID = &getUser.ID
+		createJadwal.NameUser = getUser.FullName
+	}
 
 	// Menyimpan data jadwal ke repository
 	createdJadwal, err := u.jadwalRepo.CreateJadwal(createJadwal)
 	if err != nil {
 		return jadwalResponse, err
 	}
+	createdJadwal.Lab = &getLab
+	createdJadwal.User = getUser
 
 
 	// Memproses gambar berita acara jika ada
@@ -268,6 +325,14 @@ func (u *jadwalUsecase) CreateJadwal(jadwal *dtos.JadwalInput) (dtos.JadwalRespo
 		JadwalID:           int(createdJadwal.ID),
 		TanggalJadwal:      helpers.FormatDateToYMD(createdJadwal.TanggalJadwal),
 		WaktuJadwal:        createdJadwal.WaktuJadwal,
+		WaktuSelesai:       createdJadwal.WaktuSelesai,
+		LabSlotID:          createdJadwal.LabSlotID,
+			LabID:              createdJadwal.LabID,
+			UserID:             createdJadwal.UserID,
+			Lab:                toJadwalLabResponse(createdJadwal.Lab),
+			User:               toJadwalUserResponse(createdJadwal.User),
+			PeminjamanID:       createdJadwal.PeminjamanID,
+			Peminjaman:         toJadwalPeminjamanResponse(createdJadwal.Peminjaman),
 		NameUser:           createdJadwal.NameUser,
 		NameLaboratorium:   createdJadwal.NameLaboratorium,
 		BeritaAcaraImage:   beritaAcaraImageResponses,
@@ -290,7 +355,7 @@ func (u *jadwalUsecase) UpdateJadwal(id uint, jadwal dtos.JadwalInput) (dtos.Jad
 		return jadwalResponse, err
 	}
 
-	if jadwal.WaktuJadwal == "" || jadwal.NameUser == "" || jadwal.NameLaboratorium == "" || jadwal.BeritaAcaraImage == nil || jadwal.Status == "" {
+	if jadwal.WaktuJadwal == "" || (jadwal.NameUser == "" && jadwal.UserID == nil) || (jadwal.NameLaboratorium == "" && jadwal.LabID == nil) || jadwal.BeritaAcaraImage == nil || jadwal.Status == "" {
 		return jadwalResponse, errors.New("failed to update jadwal")
 	}
 
@@ -304,17 +369,41 @@ func (u *jadwalUsecase) UpdateJadwal(id uint, jadwal dtos.JadwalInput) (dtos.Jad
 		return jadwalResponse, errors.New("Failed to parse tanggal jadwal")
 	}
 
+	getLab, getUser, err := u.resolveJadwalLabUser(jadwal)
+	if err != nil {
+		return jadwalResponse, err
+	}
+
+	labSlot, err := resolveLabSlot(u.labSlotRepo, getLab.ID, tanggalJadwalParse, jadwal.WaktuJadwal, jadwal.LabSlotID)
+	if err != nil {
+		return jadwalResponse, err
+	}
+
+	// Jadwal tidak boleh dibuat saat lab ditutup atau hari libur
+	if err := checkLabClosure(u.labClosureRepo, getLab.ID, tanggalJadwalParse, labSlot.JamMulai, labSlot.JamSelesai); err != nil {
+		return jadwalResponse, err
+	}
 
 	jadwals.TanggalJadwal    = &tanggalJadwalParse
-	jadwals.WaktuJadwal      =  jadwal.WaktuJadwal
+	jadwals.WaktuJadwal      = labSlot.JamMulai
+	jadwals.WaktuSelesai     = labSlot.JamSelesai
+	jadwals.LabSlotID        = &labSlot.ID
+	jadwals.LabID            = &getLab.ID
+	jadwals.UserID           = nil
 	jadwals.NameUser         = jadwal.NameUser
-	jadwals.NameLaboratorium = jadwal.NameLaboratorium
+	jadwals.NameLaboratorium = getLab.Name
 	jadwals.Status           = jadwal.Status
+	if getUser != nil {
+		jadwals.UserID   = &getUser.ID
+		jadwals.NameUser = getUser.FullName
+	}
 
 	updatedJadwal, err := u.jadwalRepo.UpdateJadwal(jadwals)
 	if err != nil {
 		return jadwalResponse, err
 	}
+	updatedJadwal.Lab = &getLab
+	updatedJadwal.User = getUser
 
 	u.beritaAcaraImageRepo.DeleteBeritaAcaraImage(id)
 
@@ -350,6 +439,14 @@ func (u *jadwalUsecase) UpdateJadwal(id uint, jadwal dtos.JadwalInput) (dtos.Jad
 		JadwalID:           int(updatedJadwal.ID),
 		TanggalJadwal:      helpers.FormatDateToYMD(updatedJadwal.TanggalJadwal),
 		WaktuJadwal:        updatedJadwal.WaktuJadwal,
+		WaktuSelesai:       updatedJadwal.WaktuSelesai,
+		LabSlotID:          updatedJadwal.LabSlotID,
+			LabID:              updatedJadwal.LabID,
+			UserID:             updatedJadwal.UserID,
+			Lab:                toJadwalLabResponse(updatedJadwal.Lab),
+			User:               toJadwalUserResponse(updatedJadwal.User),
+			PeminjamanID:       updatedJadwal.PeminjamanID,
+			Peminjaman:         toJadwalPeminjamanResponse(updatedJadwal.Peminjaman),
 		NameUser:           updatedJadwal.NameUser,
 		NameLaboratorium:   updatedJadwal.NameLaboratorium,
 		BeritaAcaraImage:   beritaAcaraImageResponses,
@@ -400,25 +497,30 @@ func (u *jadwalUsecase) SearchJadwalAvailable(userId, page, limit int, name_labo
 
 	var jadwalResponses []dtos.JadwalResponse
 
+	jadwalIDs := make([]uint, 0, len(jadwals))
 	for _, jadwal := range jadwals {
-		getBeritaAcaraImage, err := u.beritaAcaraImageRepo.GetAllBeritaAcaraImageByID(jadwal.ID)
-		if err != nil {
-			continue
-		}
+		jadwalIDs = append(jadwalIDs, jadwal.ID)
+	}
+	beritaAcaraImages, err := loadBeritaAcaraImageResponses(u.beritaAcaraImageRepo, jadwalIDs)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		var beritaAcaraImageResponses []dtos.BeritaAcaraImageResponse
-		for _, beritaAcaraimage := range getBeritaAcaraImage {
-			beritaAcaraImageResponse := dtos.BeritaAcaraImageResponse{
-				JadwalID:    beritaAcaraimage.JadwalID,
-				BeritaAcaraImageUrl: beritaAcaraimage.BeritaAcaraImageUrl,
-			}
-			beritaAcaraImageResponses = append(beritaAcaraImageResponses, beritaAcaraImageResponse)
-		}
+	for _, jadwal := range jadwals {
+		beritaAcaraImageResponses := beritaAcaraImages[jadwal.ID]
 
 		jadwalResponse := dtos.JadwalResponse{
 			JadwalID:           int(jadwal.ID),
 			TanggalJadwal:      helpers.FormatDateToYMD(jadwal.TanggalJadwal),
 			WaktuJadwal:        jadwal.WaktuJadwal,
+			WaktuSelesai:       jadwal.WaktuSelesai,
+			LabSlotID:          jadwal.LabSlotID,
+			LabID:              jadwal.LabID,
+			UserID:             jadwal.UserID,
+			Lab:                toJadwalLabResponse(jadwal.Lab),
+			User:               toJadwalUserResponse(jadwal.User),
+			PeminjamanID:       jadwal.PeminjamanID,
+			Peminjaman:         toJadwalPeminjamanResponse(jadwal.Peminjaman),
 			NameUser:           jadwal.NameUser,
 			NameLaboratorium:   jadwal.NameLaboratorium,
 			BeritaAcaraImage:   beritaAcaraImageResponses,
@@ -434,4 +536,78 @@ func (u *jadwalUsecase) SearchJadwalAvailable(userId, page, limit int, name_labo
 
 	return jadwalResponses, count, nil
 
-}
\ No newline at end of file
+}
+
+// resolveJadwalLabUser mencari lab dan user jadwal dari ID, dengan nama lab sebagai fallback
+// untuk klien lama. User bersifat opsional karena jadwal bisa milik pihak tanpa akun.
+func (u *jadwalUsecase) resolveJadwalLabUser(input dtos.JadwalInput) (models.Lab, *models.User, error) {
+	var (
+		getLab models.Lab
+		err    error
+	)
+	if input.LabID != nil {
+		getLab, err = u.labRepo.GetLabByID(*input.LabID)
+		if err != nil {
+			return getLab, nil, errors.New("lab tidak ditemukan, pastikan lab_id benar")
+		}
+	} else {
+		if input.NameLaboratorium == "" {
+			return getLab, nil, errors.New("lab_id wajib diisi")
+		}
+		getLab, err = u.labRepo.GetLabByName(input.NameLaboratorium)
+		if err != nil {
+			return getLab, nil, errors.New("lab " + input.NameLaboratorium + " tidak ditemukan")
+		}
+	}
+
+	if input.UserID == nil {
+		if input.NameUser == "" {
+			return getLab, nil, errors.New("user_id atau name_user wajib diisi")
+		}
+		return getLab, nil, nil
+	}
+
+	getUser, err := u.userRepo.UserGetById(*input.UserID)
+	if err != nil {
+		return getLab, nil, errors.New("user tidak ditemukan, pastikan user_id benar")
+	}
+	return getLab, &getUser, nil
+}
+
+func toJadwalLabResponse(lab *models.Lab) *dtos.LabByIDResponses {
+	if lab == nil {
+		return nil
+	}
+	return &dtos.LabByIDResponses{
+		LabID:       lab.ID,
+		Name:        lab.Name,
+		Description: lab.Description,
+	}
+}
+
+func toJadwalUserResponse(user *models.User) *dtos.UserInformationResponses {
+	if user == nil {
+		return nil
+	}
+	return &dtos.UserInformationResponses{
+		ID:             user.ID,
+		FullName:       user.FullName,
+		Email:          user.Email,
+		NIMNIP:         user.NIMNIP,
+		ProfilePicture: user.ProfilePicture,
+	}
+}
+
+func toJadwalPeminjamanResponse(peminjaman *models.Peminjaman) *dtos.JadwalPeminjamanResponse {
+	if peminjaman == nil {
+		return nil
+	}
+	return &dtos.JadwalPeminjamanResponse{
+		PeminjamanID:      peminjaman.ID,
+		TanggalPeminjaman: helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
+		JamPeminjaman:     peminjaman.JamPeminjaman,
+		JamSelesai:        peminjaman.JamSelesai,
+		Description:       peminjaman.Description,
+		Status:            peminjaman.Status,
+	}
+}
@@ -0,0 +1,285 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"time"
+)
+
+type LabClosureUsecase interface {
+	GetLabClosures(labID uint, from, to string) ([]dtos.LabClosureResponse, error)
+	CreateLabClosure(input dtos.LabClosureInput) (dtos.LabClosureResponse, error)
+	UpdateLabClosure(id uint, input dtos.LabClosureInput) (dtos.LabClosureResponse, error)
+	DeleteLabClosure(id uint) error
+	ImportLabClosures(labID *uint, file io.Reader) (dtos.LabClosureImportResponse, error)
+}
+
+type labClosureUsecase struct {
+	labClosureRepo repositories.LabClosureRepository
+	labRepo        repositories.LabRepository
+}
+
+func NewLabClosureUsecase(labClosureRepo repositories.LabClosureRepository, labRepo repositories.LabRepository) LabClosureUsecase {
+	return &labClosureUsecase{labClosureRepo, labRepo}
+}
+
+func (u *labClosureUsecase) GetLabClosures(labID uint, from, to string) ([]dtos.LabClosureResponse, error) {
+	labClosureResponses := []dtos.LabClosureResponse{}
+
+	var fromDate, toDate *time.Time
+	if from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return labClosureResponses, errors.New("tanggal from invalid, gunakan format YYYY-MM-DD")
+		}
+		fromDate = &parsed
+	}
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return labClosureResponses, errors.New("tanggal to invalid, gunakan format YYYY-MM-DD")
+		}
+		toDate = &parsed
+	}
+
+	labClosures, err := u.labClosureRepo.GetLabClosures(labID, fromDate, toDate)
+	if err != nil {
+		return labClosureResponses, err
+	}
+
+	for _, labClosure := range labClosures {
+		labClosureResponses = append(labClosureResponses, toLabClosureResponse(labClosure))
+	}
+
+	return labClosureResponses, nil
+}
+
+func (u *labClosureUsecase) CreateLabClosure(input dtos.LabClosureInput) (dtos.LabClosureResponse, error) {
+	labClosure := models.LabClosure{Source: models.LabClosureSourceManual}
+	if err := u.applyLabClosureInput(&labClosure, input); err != nil {
+		return dtos.LabClosureResponse{}, err
+	}
+
+	createdLabClosure, err := u.labClosureRepo.CreateLabClosure(labClosure)
+	if err != nil {
+		return dtos.LabClosureResponse{}, err
+	}
+
+	return u.getLabClosureResponse(createdLabClosure.ID)
+}
+
+func (u *labClosureUsecase) UpdateLabClosure(id uint, input dtos.LabClosureInput) (dtos.LabClosureResponse, error) {
+	labClosure, err := u.labClosureRepo.GetLabClosureByID(id)
+	if err != nil {
+		return dtos.LabClosureResponse{}, errors.New("penutupan lab tidak ditemukan, pastikan ID benar")
+	}
+	if err := u.applyLabClosureInput(&labClosure, input); err != nil {
+		return dtos.LabClosureResponse{}, err
+	}
+
+	if _, err := u.labClosureRepo.UpdateLabClosure(labClosure); err != nil {
+		return dtos.LabClosureResponse{}, err
+	}
+
+	return u.getLabClosureResponse(labClosure.ID)
+}
+
+func (u *labClosureUsecase) DeleteLabClosure(id uint) error {
+	if _, err := u.labClosureRepo.GetLabClosureByID(id); err != nil {
+		return errors.New("penutupan lab tidak ditemukan, pastikan ID benar")
+	}
+	return u.labClosureRepo.DeleteLabClosure(id)
+}
+
+// ImportLabClosures membuat penutupan dari setiap VEVENT di file .ics. Event dengan UID yang sudah
+// pernah diimpor untuk lab yang sama diperbarui sehingga file yang sama aman diimpor ulang.
+func (u *labClosureUsecase) ImportLabClosures(labID *uint, file io.Reader) (dtos.LabClosureImportResponse, error) {
+	importResponse := dtos.LabClosureImportResponse{Closures: []dtos.LabClosureResponse{}}
+
+	if labID != nil {
+		if _, err := u.labRepo.GetLabByID(*labID); err != nil {
+			return importResponse, errors.New("lab tidak ditemukan, pastikan lab_id benar")
+		}
+	}
+
+	events, err := helpers.ParseICSEvents(file)
+	if err != nil {
+		return importResponse, fmt.Errorf("file ics tidak valid: %v", err)
+	}
+	if len(events) == 0 {
+		return importResponse, errors.New("file ics tidak berisi event")
+	}
+
+	for _, event := range events {
+		labClosure := closureFromICSEvent(event)
+		labClosure.LabID = labID
+
+		savedLabClosure, created, err := u.labClosureRepo.UpsertLabClosureByUID(labClosure)
+		if err != nil {
+			return importResponse, err
+		}
+		if created {
+			importResponse.Created++
+		} else {
+			importResponse.Updated++
+		}
+		importResponse.Closures = append(importResponse.Closures, toLabClosureResponse(savedLabClosure))
+	}
+
+	return importResponse, nil
+}
+
+func (u *labClosureUsecase) applyLabClosureInput(labClosure *models.LabClosure, input dtos.LabClosureInput) error {
+	if input.LabID != nil {
+		if _, err := u.labRepo.GetLabByID(*input.LabID); err != nil {
+			return errors.New("lab tidak ditemukan, pastikan lab_id benar")
+		}
+	}
+
+	if input.TanggalMulai == nil || *input.TanggalMulai == "" {
+		return errors.New("tanggal mulai wajib diisi")
+	}
+	tanggalMulai, err := time.Parse("2006-01-02", *input.TanggalMulai)
+	if err != nil {
+		return errors.New("failed to parse tanggal mulai")
+	}
+	tanggalSelesai := tanggalMulai
+	if input.TanggalSelesai != nil && *input.TanggalSelesai != "" {
+		tanggalSelesai, err = time.Parse("2006-01-02", *input.TanggalSelesai)
+		if err != nil {
+			return errors.New("failed to parse tanggal selesai")
+		}
+	}
+	if tanggalSelesai.Before(tanggalMulai) {
+		return errors.New("tanggal selesai harus sama atau setelah tanggal mulai")
+	}
+
+	if (input.JamMulai == "") != (input.JamSelesai == "") {
+		return errors.New("jam mulai dan jam selesai harus diisi keduanya atau dikosongkan untuk tutup seharian")
+	}
+	if input.JamMulai != "" {
+		jamMulai, err := time.Parse("15:04", input.JamMulai)
+		if err != nil {
+			return errors.New("jam mulai harus berformat HH:MM")
+		}
+		jamSelesai, err := time.Parse("15:04", input.JamSelesai)
+		if err != nil {
+			return errors.New("jam selesai harus berformat HH:MM")
+		}
+		if !jamSelesai.After(jamMulai) {
+			return errors.New("jam selesai harus setelah jam mulai")
+		}
+	}
+
+	if input.Reason == "" {
+		return errors.New("alasan penutupan wajib diisi")
+	}
+
+	labClosure.LabID = input.LabID
+	labClosure.TanggalMulai = &tanggalMulai
+	labClosure.TanggalSelesai = &tanggalSelesai
+	labClosure.JamMulai = input.JamMulai
+	labClosure.JamSelesai = input.JamSelesai
+	labClosure.Reason = input.Reason
+	return nil
+}
+
+func (u *labClosureUsecase) getLabClosureResponse(id uint) (dtos.LabClosureResponse, error) {
+	labClosure, err := u.labClosureRepo.GetLabClosureByID(id)
+	if err != nil {
+		return dtos.LabClosureResponse{}, errors.New("failed to get lab closure")
+	}
+	return toLabClosureResponse(labClosure), nil
+}
+
+// closureFromICSEvent mengubah event seharian menjadi penutupan penuh. Event berjam di hari yang
+// sama menjadi penutupan sebagian, sedangkan event berjam lintas hari dianggap menutup seharian.
+func closureFromICSEvent(event helpers.ICSEvent) models.LabClosure {
+	tanggalMulai, _ := time.Parse("2006-01-02", event.Start.Format("2006-01-02"))
+	labClosure := models.LabClosure{
+		Reason:      event.Summary,
+		Source:      models.LabClosureSourceICS,
+		ExternalUID: event.UID,
+	}
+	if labClosure.ExternalUID == "" {
+		labClosure.ExternalUID = fmt.Sprintf("%s-%s", event.Start.Format("20060102T150405"), event.Summary)
+	}
+	if labClosure.Reason == "" {
+		labClosure.Reason = "Penutupan dari kalender"
+	}
+
+	end := event.End
+	if event.AllDay || end.Format("15:04") == "00:00" {
+		end = end.AddDate(0, 0, -1)
+	}
+	tanggalSelesai, _ := time.Parse("2006-01-02", end.Format("2006-01-02"))
+	if tanggalSelesai.Before(tanggalMulai) {
+		tanggalSelesai = tanggalMulai
+	}
+
+	if !event.AllDay && tanggalSelesai.Equal(tanggalMulai) && event.End.After(event.Start) {
+		labClosure.JamMulai = event.Start.Format("15:04")
+		labClosure.JamSelesai = event.End.Format("15:04")
+		if event.End.Format("15:04") == "00:00" {
+			labClosure.JamSelesai = "24:00"
+		}
+	}
+
+	labClosure.TanggalMulai = &tanggalMulai
+	labClosure.TanggalSelesai = &tanggalSelesai
+	return labClosure
+}
+
+// closureCovers mengecek apakah penutupan berlaku pada tanggal dan rentang jam tertentu
+func closureCovers(labClosure models.LabClosure, tanggal time.Time, jamMulai, jamSelesai string) bool {
+	date := tanggal.Format("2006-01-02")
+	if labClosure.TanggalMulai == nil || labClosure.TanggalSelesai == nil ||
+		date < labClosure.TanggalMulai.Format("2006-01-02") || date > labClosure.TanggalSelesai.Format("2006-01-02") {
+		return false
+	}
+	if labClosure.JamMulai == "" || labClosure.JamSelesai == "" {
+		return true
+	}
+	return labClosure.JamMulai < jamSelesai && labClosure.JamSelesai > jamMulai
+}
+
+// checkLabClosure menolak peminjaman atau jadwal yang jatuh di dalam penutupan lab
+func checkLabClosure(labClosureRepo repositories.LabClosureRepository, labID uint, tanggal time.Time, jamMulai, jamSelesai string) error {
+	labClosures, err := labClosureRepo.GetClosuresForLab(labID, tanggal, tanggal)
+	if err != nil {
+		return errors.New("failed to check lab closure")
+	}
+	for _, labClosure := range labClosures {
+		if closureCovers(labClosure, tanggal, jamMulai, jamSelesai) {
+			return fmt.Errorf("%w: %s", helpers.ErrLabClosed, labClosure.Reason)
+		}
+	}
+	return nil
+}
+
+func toLabClosureResponse(labClosure models.LabClosure) dtos.LabClosureResponse {
+	labClosureResponse := dtos.LabClosureResponse{
+		LabClosureID:   labClosure.ID,
+		TanggalMulai:   helpers.FormatDateToYMD(labClosure.TanggalMulai),
+		TanggalSelesai: helpers.FormatDateToYMD(labClosure.TanggalSelesai),
+		JamMulai:       labClosure.JamMulai,
+		JamSelesai:     labClosure.JamSelesai,
+		Reason:         labClosure.Reason,
+		Source:         labClosure.Source,
+		CreatedAt:      labClosure.CreatedAt,
+		UpdatedAt:      labClosure.UpdatedAt,
+	}
+	if labClosure.Lab != nil {
+		labClosureResponse.Lab = &dtos.LabByIDResponses{
+			LabID:       labClosure.Lab.ID,
+			Name:        labClosure.Lab.Name,
+			Description: labClosure.Lab.Description,
+		}
+	}
+	return labClosureResponse
+}
@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	"errors"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"time"
+)
+
+type LabSlotUsecase interface {
+	GetLabSlots(labID uint) ([]dtos.LabSlotResponse, error)
+	CreateLabSlot(labID uint, input dtos.LabSlotInput) (dtos.LabSlotResponse, error)
+	UpdateLabSlot(labID, id uint, input dtos.LabSlotInput) (dtos.LabSlotResponse, error)
+	DeleteLabSlot(labID, id uint) error
+}
+
+type labSlotUsecase struct {
+	labSlotRepo repositories.LabSlotRepository
+	labRepo     repositories.LabRepository
+}
+
+func NewLabSlotUsecase(labSlotRepo repositories.LabSlotRepository, labRepo repositories.LabRepository) LabSlotUsecase {
+	return &labSlotUsecase{labSlotRepo, labRepo}
+}
+
+func (u *labSlotUsecase) GetLabSlots(labID uint) ([]dtos.LabSlotResponse, error) {
+	var labSlotResponses []dtos.LabSlotResponse
+
+	_, err := u.labRepo.GetLabByID(labID)
+	if err != nil {
+		return labSlotResponses, errors.New("lab tidak ditemukan, pastikan ID benar")
+	}
+
+	labSlots, err := u.labSlotRepo.GetLabSlotsByLabID(labID)
+	if err != nil {
+		return labSlotResponses, err
+	}
+
+	for _, labSlot := range labSlots {
+		labSlotResponses = append(labSlotResponses, toLabSlotResponse(labSlot))
+	}
+
+	return labSlotResponses, nil
+}
+
+func (u *labSlotUsecase) CreateLabSlot(labID uint, input dtos.LabSlotInput) (dtos.LabSlotResponse, error) {
+	var labSlotResponse dtos.LabSlotResponse
+
+	_, err := u.labRepo.GetLabByID(labID)
+	if err != nil {
+		return labSlotResponse, errors.New("lab tidak ditemukan, pastikan ID benar")
+	}
+
+	if input.Weekday == nil {
+		return labSlotResponse, errors.New("weekday wajib diisi (0 = Minggu sampai 6 = Sabtu)")
+	}
+
+	labSlot := models.LabSlot{
+		LabID:      labID,
+		Weekday:    *input.Weekday,
+		JamMulai:   input.JamMulai,
+		JamSelesai: input.JamSelesai,
+		IsActive:   true,
+	}
+	if input.IsActive != nil {
+		labSlot.IsActive = *input.IsActive
+	}
+
+	if err := u.validateLabSlot(labSlot); err != nil {
+		return labSlotResponse, err
+	}
+
+	createdLabSlot, err := u.labSlotRepo.CreateLabSlot(labSlot)
+	if err != nil {
+		return labSlotResponse, err
+	}
+
+	return toLabSlotResponse(createdLabSlot), nil
+}
+
+func (u *labSlotUsecase) UpdateLabSlot(labID, id uint, input dtos.LabSlotInput) (dtos.LabSlotResponse, error) {
+	var labSlotResponse dtos.LabSlotResponse
+
+	labSlot, err := u.labSlotRepo.GetLabSlotByID(id)
+	if err != nil || labSlot.LabID != labID {
+		return labSlotResponse, errors.New("slot lab tidak ditemukan, pastikan ID benar")
+	}
+
+	if input.Weekday != nil {
+		labSlot.Weekday = *input.Weekday
+	}
+	if input.JamMulai != "" {
+		labSlot.JamMulai = input.JamMulai
+	}
+	if input.JamSelesai != "" {
+		labSlot.JamSelesai = input.JamSelesai
+	}
+	if input.IsActive != nil {
+		labSlot.IsActive = *input.IsActive
+	}
+
+	if err := u.validateLabSlot(labSlot); err != nil {
+		return labSlotResponse, err
+	}
+
+	updatedLabSlot, err := u.labSlotRepo.UpdateLabSlot(labSlot)
+	if err != nil {
+		return labSlotResponse, err
+	}
+
+	return toLabSlotResponse(updatedLabSlot), nil
+}
+
+func (u *labSlotUsecase) DeleteLabSlot(labID, id uint) error {
+	labSlot, err := u.labSlotRepo.GetLabSlotByID(id)
+	if err != nil || labSlot.LabID != labID {
+		return errors.New("slot lab tidak ditemukan, pastikan ID benar")
+	}
+	return u.labSlotRepo.DeleteLabSlot(id)
+}
+
+// validateLabSlot memastikan format jam benar dan slot aktif tidak bertabrakan
+// dengan slot aktif lain pada lab dan hari yang sama
+func (u *labSlotUsecase) validateLabSlot(labSlot models.LabSlot) error {
+	if labSlot.Weekday < 0 || labSlot.Weekday > 6 {
+		return errors.New("weekday invalid (0 = Minggu sampai 6 = Sabtu)")
+	}
+
+	jamMulai, err := time.Parse("15:04", labSlot.JamMulai)
+	if err != nil {
+		return errors.New("jam mulai invalid, gunakan format HH:MM")
+	}
+	jamSelesai, err := time.Parse("15:04", labSlot.JamSelesai)
+	if err != nil {
+		return errors.New("jam selesai invalid, gunakan format HH:MM")
+	}
+	if !jamSelesai.After(jamMulai) {
+		return errors.New("jam selesai harus setelah jam mulai")
+	}
+
+	if !labSlot.IsActive {
+		return nil
+	}
+
+	labSlots, err := u.labSlotRepo.GetLabSlotsByLabID(labSlot.LabID)
+	if err != nil {
+		return err
+	}
+	for _, otherSlot := range labSlots {
+		if otherSlot.ID == labSlot.ID || !otherSlot.IsActive || otherSlot.Weekday != labSlot.Weekday {
+			continue
+		}
+		if otherSlot.JamMulai < labSlot.JamSelesai && otherSlot.JamSelesai > labSlot.JamMulai {
+			return errors.New("slot bertabrakan dengan slot " + otherSlot.JamMulai + "-" + otherSlot.JamSelesai + " pada hari yang sama")
+		}
+	}
+
+	return nil
+}
+
+// resolveLabSlot mencari slot aktif lab untuk tanggal yang dipilih, baik dari labSlotID
+// maupun dari jam mulai yang dikirim client
+func resolveLabSlot(labSlotRepo repositories.LabSlotRepository, labID uint, tanggal time.Time, jamMulai string, labSlotID *uint) (models.LabSlot, error) {
+	weekday := int(tanggal.Weekday())
+
+	if labSlotID != nil && *labSlotID != 0 {
+		labSlot, err := labSlotRepo.GetLabSlotByID(*labSlotID)
+		if err != nil || labSlot.LabID != labID || !labSlot.IsActive || labSlot.Weekday != weekday {
+			return labSlot, errors.New("slot lab tidak tersedia pada tanggal tersebut")
+		}
+		return labSlot, nil
+	}
+
+	labSlot, err := labSlotRepo.GetActiveLabSlot(labID, weekday, jamMulai)
+	if err != nil {
+		return labSlot, errors.New("jam " + jamMulai + " tidak tersedia untuk lab ini pada tanggal tersebut")
+	}
+	return labSlot, nil
+}
+
+func toLabSlotResponse(labSlot models.LabSlot) dtos.LabSlotResponse {
+	return dtos.LabSlotResponse{
+		LabSlotID:  labSlot.ID,
+		LabID:      labSlot.LabID,
+		Weekday:    labSlot.Weekday,
+		JamMulai:   labSlot.JamMulai,
+		JamSelesai: labSlot.JamSelesai,
+		IsActive:   labSlot.IsActive,
+		CreatedAt:  labSlot.CreatedAt,
+		UpdatedAt:  labSlot.UpdatedAt,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sistem_peminjaman_be/models"
 	"sistem_peminjaman_be/repositories"
 	"errors"
+	"time"
 )
 
 type LabUsecase interface {
@@ -16,6 +17,7 @@ type LabUsecase interface {
 	DeleteLab(id uint) error
 
 	SearchLabAvailable(userId, page, limit int, name string) ([]dtos.LabResponse, int, error)
+	GetLabAvailability(id uint, from, to string) (dtos.LabAvailabilityResponse, error)
 }
 
 type labUsecase struct {
@@ -24,10 +26,14 @@ type labUsecase struct {
 	historySearchRepo       repositories.HistorySearchRepository
 	userRepo                repositories.UserRepository
 	historySeenLabUsecase   HistorySeenLabUsecase
+	peminjamanRepo          repositories.PeminjamanRepository
+	jadwalRepo              repositories.JadwalRepository
+	labSlotRepo             repositories.LabSlotRepository
+	labClosureRepo          repositories.LabClosureRepository
 }
 
-func NewLabUsecase(labRepo repositories.LabRepository, labImageRepo repositories.LabImageRepository, historySearchRepo repositories.HistorySearchRepository, userRepo repositories.UserRepository, historySeenLabUsecase HistorySeenLabUsecase) LabUsecase {
-	return &labUsecase{labRepo, labImageRepo, historySearchRepo, userRepo, historySeenLabUsecase}
+func NewLabUsecase(labRepo repositories.LabRepository, labImageRepo repositories.LabImageRepository, historySearchRepo repositories.HistorySearchRepository, userRepo repositories.UserRepository, historySeenLabUsecase HistorySeenLabUsecase, peminjamanRepo repositories.PeminjamanRepository, jadwalRepo repositories.JadwalRepository, labSlotRepo repositories.LabSlotRepository, labClosureRepo repositories.LabClosureRepository) LabUsecase {
+	return &labUsecase{labRepo, labImageRepo, historySearchRepo, userRepo, historySeenLabUsecase, peminjamanRepo, jadwalRepo, labSlotRepo, labClosureRepo}
 }
 
 
@@ -39,26 +45,24 @@ func (u *labUsecase) GetAllLabs(page, limit int, name string) ([]dtos.LabRespons
 
 	var labResponses []dtos.LabResponse
 
+	labIDs := make([]uint, 0, len(labs))
 	for _, lab := range labs {
-		getImage, err := u.labImageRepo.GetAllLabImageByID(lab.ID)
-		if err != nil {
-			continue
-		}
+		labIDs = append(labIDs, lab.ID)
+	}
+	labImages, err := loadLabImageResponses(u.labImageRepo, labIDs)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		var labImageResponses []dtos.LabImageResponse
-		for _, image := range getImage {
-			labImageResponse := dtos.LabImageResponse{
-				LabID:  image.LabID,
-				ImageUrl: image.ImageUrl,
-			}
-			labImageResponses = append(labImageResponses, labImageResponse)
-		}
+	for _, lab := range labs {
+		labImageResponses := labImages[lab.ID]
 
 		labResponse := dtos.LabResponse{
 			LabID:           lab.ID,
 			Name:            lab.Name,
 			LabImage:        labImageResponses,
 			Description:     lab.Description,
+			Capacity:        lab.Capacity,
 			CreatedAt:       lab.CreatedAt,
 			UpdatedAt:       lab.UpdatedAt,
 		}
@@ -106,6 +110,7 @@ func (u *labUsecase) GetLabByID(userId, id uint) (dtos.LabByIDResponse, error) {
 		Name:            lab.Name,
 		LabImage:        labImageResponses,
 		Description:     lab.Description,
+		Capacity:        lab.Capacity,
 		CreatedAt:       lab.CreatedAt,
 		UpdatedAt:       lab.UpdatedAt,
 	}
@@ -119,9 +124,14 @@ func (u *labUsecase) CreateLab(lab *dtos.LabInput) (dtos.LabResponse, error) {
 		return labResponse, errors.New("failed to create lab")
 	}
 
+	if lab.Capacity < 0 {
+		return labResponse, errors.New("kapasitas lab tidak boleh negatif")
+	}
+
 	createLab := models.Lab{
 		Name:        lab.Name,
 		Description: lab.Description,
+		Capacity:    lab.Capacity,
 	}
 
 	createdLab, err := u.labRepo.CreateLab(createLab)
@@ -129,6 +139,12 @@ func (u *labUsecase) CreateLab(lab *dtos.LabInput) (dtos.LabResponse, error) {
 		return labResponse, err
 	}
 
+	// Lab baru langsung diberi slot bawaan yang bisa diubah admin lewat /admin/lab/:id/slots
+	err = u.labSlotRepo.CreateLabSlots(models.DefaultLabSlots(createdLab.ID))
+	if err != nil {
+		return labResponse, err
+	}
+
 	for _, labImage := range lab.LabImage {
 		if labImage.ImageUrl == "" {
 			return labResponse, errors.New("failed to create lab")
@@ -162,6 +178,7 @@ func (u *labUsecase) CreateLab(lab *dtos.LabInput) (dtos.LabResponse, error) {
 		Name:            createdLab.Name,
 		LabImage:        labImageResponses,
 		Description:     createdLab.Description,
+		Capacity:        createdLab.Capacity,
 		CreatedAt:       createdLab.CreatedAt,
 		UpdatedAt:       createdLab.UpdatedAt,
 	}
@@ -177,6 +194,10 @@ func (u *labUsecase) UpdateLab(id uint, lab dtos.LabInput) (dtos.LabResponse, er
 		return labResponse, errors.New("failed to update lab")
 	}
 
+	if lab.Capacity < 0 {
+		return labResponse, errors.New("kapasitas lab tidak boleh negatif")
+	}
+
 	labs, err := u.labRepo.GetLabByID(id)
 	if err != nil {
 		return labResponse, err
@@ -184,6 +205,7 @@ func (u *labUsecase) UpdateLab(id uint, lab dtos.LabInput) (dtos.LabResponse, er
 
 	labs.Name = lab.Name
 	labs.Description = lab.Description
+	labs.Capacity = lab.Capacity
 
 	updatedLab, err := u.labRepo.UpdateLab(labs)
 	if err != nil {
@@ -225,6 +247,7 @@ func (u *labUsecase) UpdateLab(id uint, lab dtos.LabInput) (dtos.LabResponse, er
 		Name:            updatedLab.Name,
 		LabImage:        labImageResponses,
 		Description:     updatedLab.Description,
+		Capacity:        updatedLab.Capacity,
 		CreatedAt:       updatedLab.CreatedAt,
 		UpdatedAt:       updatedLab.UpdatedAt,
 	}
@@ -255,26 +278,24 @@ func (u *labUsecase) SearchLabAvailable(userId, page, limit int, name string) ([
 
 	var labResponses []dtos.LabResponse
 
+	labIDs := make([]uint, 0, len(labs))
 	for _, lab := range labs {
-		getImage, err := u.labImageRepo.GetAllLabImageByID(lab.ID)
-		if err != nil {
-			continue
-		}
+		labIDs = append(labIDs, lab.ID)
+	}
+	labImages, err := loadLabImageResponses(u.labImageRepo, labIDs)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		var labImageResponses []dtos.LabImageResponse
-		for _, image := range getImage {
-			labImageResponse := dtos.LabImageResponse{
-				LabID:    image.LabID,
-				ImageUrl: image.ImageUrl,
-			}
-			labImageResponses = append(labImageResponses, labImageResponse)
-		}
+	for _, lab := range labs {
+		labImageResponses := labImages[lab.ID]
 
 		labResponse := dtos.LabResponse{
 			LabID:           lab.ID,
 			Name:            lab.Name,
 			LabImage:        labImageResponses,
 			Description:     lab.Description,
+			Capacity:        lab.Capacity,
 			CreatedAt:       lab.CreatedAt,
 			UpdatedAt:       lab.UpdatedAt,
 		}
@@ -286,4 +307,143 @@ func (u *labUsecase) SearchLabAvailable(userId, page, limit int, name string) ([
 
 	return labResponses, count, nil
 
-}
\ No newline at end of file
+}
+
+// maxAvailabilityDays membatasi rentang tanggal kalender ketersediaan lab
+const maxAvailabilityDays = 92
+
+func (u *labUsecase) GetLabAvailability(id uint, from, to string) (dtos.LabAvailabilityResponse, error) {
+	var availabilityResponse dtos.LabAvailabilityResponse
+
+	lab, err := u.labRepo.GetLabByID(id)
+	if err != nil {
+		return availabilityResponse, errors.New("lab tidak ditemukan, pastikan ID benar")
+	}
+
+	// Default rentang adalah 7 hari mulai hari ini
+	fromDate, err := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	if err != nil {
+		return availabilityResponse, err
+	}
+	if from != "" {
+		fromDate, err = time.Parse("2006-01-02", from)
+		if err != nil {
+			return availabilityResponse, errors.New("tanggal from invalid, gunakan format YYYY-MM-DD")
+		}
+	}
+
+	toDate := fromDate.AddDate(0, 0, 6)
+	if to != "" {
+		toDate, err = time.Parse("2006-01-02", to)
+		if err != nil {
+			return availabilityResponse, errors.New("tanggal to invalid, gunakan format YYYY-MM-DD")
+		}
+	}
+
+	if toDate.Before(fromDate) {
+		return availabilityResponse, errors.New("tanggal to harus setelah tanggal from")
+	}
+	if toDate.Sub(fromDate).Hours()/24 >= maxAvailabilityDays {
+		return availabilityResponse, errors.New("rentang tanggal maksimal 92 hari")
+	}
+
+	peminjamanUsages, err := u.peminjamanRepo.GetSlotUsageByLab(lab.ID, fromDate, toDate)
+	if err != nil {
+		return availabilityResponse, err
+	}
+
+	jadwalUsages, err := u.jadwalRepo.GetSlotUsageByLab(lab.ID, fromDate, toDate)
+	if err != nil {
+		return availabilityResponse, err
+	}
+
+	labSlots, err := u.labSlotRepo.GetLabSlotsByLabID(lab.ID)
+	if err != nil {
+		return availabilityResponse, err
+	}
+
+	labClosures, err := u.labClosureRepo.GetClosuresForLab(lab.ID, fromDate, toDate)
+	if err != nil {
+		return availabilityResponse, err
+	}
+
+	// Mengelompokkan hasil agregasi per tanggal
+	usagesByDate := map[string][]dtos.LabSlotUsage{}
+	for _, usage := range append(peminjamanUsages, jadwalUsages...) {
+		usagesByDate[usage.Tanggal] = append(usagesByDate[usage.Tanggal], usage)
+	}
+
+	var dates []dtos.LabAvailabilityDate
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		tanggal := date.Format("2006-01-02")
+
+		var dateClosures []models.LabClosure
+		var closureResponses []dtos.LabClosureResponse
+		for _, labClosure := range labClosures {
+			if closureCovers(labClosure, date, "00:00", "24:00") {
+				dateClosures = append(dateClosures, labClosure)
+				closureResponses = append(closureResponses, toLabClosureResponse(labClosure))
+			}
+		}
+
+		var slots []dtos.LabAvailabilitySlot
+		for _, labSlot := range labSlots {
+			if !labSlot.IsActive || labSlot.Weekday != int(date.Weekday()) {
+				continue
+			}
+
+			// Slot dianggap terpakai jika rentang jamnya beririsan dengan peminjaman/jadwal
+			var usage dtos.LabSlotUsage
+			for _, dateUsage := range usagesByDate[tanggal] {
+				if dateUsage.Jam < labSlot.JamSelesai && dateUsage.JamSelesai > labSlot.JamMulai {
+					usage.Requested += dateUsage.Requested
+					usage.Booked += dateUsage.Booked
+					usage.Scheduled += dateUsage.Scheduled
+				}
+			}
+
+			closed := false
+			for _, labClosure := range dateClosures {
+				if closureCovers(labClosure, date, labSlot.JamMulai, labSlot.JamSelesai) {
+					closed = true
+					break
+				}
+			}
+
+			status := "free"
+			switch {
+			case closed:
+				status = "closed"
+			case usage.Scheduled > 0:
+				status = "scheduled"
+			case usage.Booked > 0:
+				status = "booked"
+			case usage.Requested > 0:
+				status = "requested"
+			}
+
+			slots = append(slots, dtos.LabAvailabilitySlot{
+				LabSlotID:  labSlot.ID,
+				Jam:        labSlot.JamMulai,
+				JamSelesai: labSlot.JamSelesai,
+				Status:     status,
+			})
+		}
+
+		dates = append(dates, dtos.LabAvailabilityDate{
+			Tanggal:  tanggal,
+			Slots:    slots,
+			Closures: closureResponses,
+		})
+	}
+
+	availabilityResponse = dtos.LabAvailabilityResponse{
+		LabID: lab.ID,
+		Name:  lab.Name,
+		From:  fromDate.Format("2006-01-02"),
+		To:    toDate.Format("2006-01-02"),
+		Dates: dates,
+	}
+
+	return availabilityResponse, nil
+}
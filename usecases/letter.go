@@ -0,0 +1,336 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"strings"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+type LetterUsecase interface {
+	GetPeminjamanLetter(userID, peminjamanID uint) ([]byte, error)
+	VerifyLetter(code string) (dtos.LetterVerificationResponse, error)
+	GetLetterTemplates() ([]dtos.LetterTemplateResponse, error)
+	CreateLetterTemplate(input dtos.LetterTemplateInput) (dtos.LetterTemplateResponse, error)
+	UpdateLetterTemplate(id uint, input dtos.LetterTemplateInput) (dtos.LetterTemplateResponse, error)
+	DeleteLetterTemplate(id uint) error
+}
+
+type letterUsecase struct {
+	letterTemplateRepo   repositories.LetterTemplateRepository
+	peminjamanLetterRepo repositories.PeminjamanLetterRepository
+	peminjamanRepo       repositories.PeminjamanRepository
+	participantRepo      repositories.PeminjamanParticipantRepository
+	statusLogRepo        repositories.PeminjamanStatusLogRepository
+	userRepo             repositories.UserRepository
+	labRepo              repositories.LabRepository
+	verifyBaseURL        string
+}
+
+func NewLetterUsecase(letterTemplateRepo repositories.LetterTemplateRepository, peminjamanLetterRepo repositories.PeminjamanLetterRepository, peminjamanRepo repositories.PeminjamanRepository, participantRepo repositories.PeminjamanParticipantRepository, statusLogRepo repositories.PeminjamanStatusLogRepository, userRepo repositories.UserRepository, labRepo repositories.LabRepository, verifyBaseURL string) LetterUsecase {
+	return &letterUsecase{letterTemplateRepo, peminjamanLetterRepo, peminjamanRepo, participantRepo, statusLogRepo, userRepo, labRepo, verifyBaseURL}
+}
+
+// GetPeminjamanLetter membuat PDF surat peminjaman untuk peminjam atau peserta kelompok. Surat
+// hanya tersedia setelah peminjaman diterima, dan kode verifikasinya tetap sama setiap diunduh.
+func (u *letterUsecase) GetPeminjamanLetter(userID, peminjamanID uint) ([]byte, error) {
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanByID(peminjamanID, userID)
+	if err != nil {
+		isParticipant, checkErr := u.participantRepo.IsParticipant(peminjamanID, userID)
+		if checkErr != nil || !isParticipant {
+			return nil, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+		}
+		peminjaman, err = u.peminjamanRepo.GetPeminjamanID(peminjamanID)
+		if err != nil {
+			return nil, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+		}
+	}
+	if !letterAvailable(peminjaman.Status) {
+		return nil, fmt.Errorf("%w: surat hanya tersedia untuk peminjaman yang diterima", helpers.ErrInvalidStatusTransition)
+	}
+
+	letterTemplate, err := u.letterTemplateRepo.GetActiveLetterTemplate()
+	if err != nil {
+		letterTemplate = defaultLetterTemplate()
+	}
+
+	letter, err := u.peminjamanLetterRepo.GetLetterByPeminjamanID(peminjaman.ID)
+	if err != nil {
+		letter, err = u.issueLetter(peminjaman, letterTemplate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	borrower, err := u.userRepo.UserGetById2(peminjaman.UserID)
+	if err != nil {
+		return nil, errors.New("failed to get peminjam")
+	}
+	lab, err := u.labRepo.GetLabByID(peminjaman.LabID)
+	if err != nil {
+		return nil, errors.New("failed to get lab")
+	}
+	approver := u.getApproverName(peminjaman.ID)
+
+	replacer := strings.NewReplacer(
+		"{{nama}}", borrower.FullName,
+		"{{nim}}", borrower.NIMNIP,
+		"{{lab}}", lab.Name,
+		"{{tanggal}}", formatIndonesianDate(peminjaman.TanggalPeminjaman),
+		"{{jam_mulai}}", peminjaman.JamPeminjaman,
+		"{{jam_selesai}}", peminjaman.JamSelesai,
+		"{{approver}}", approver,
+		"{{nomor}}", letter.Number,
+		"{{keperluan}}", peminjaman.Description,
+	)
+
+	signatoryName := letterTemplate.SignatoryName
+	if signatoryName == "" {
+		signatoryName = approver
+	}
+
+	verifyURL := fmt.Sprintf("%s/api/v1/public/verify/%s", u.verifyBaseURL, letter.Code)
+	qrCode, err := qrcode.Encode(verifyURL, qrcode.Medium, checkinQRCodeSize)
+	if err != nil {
+		return nil, errors.New("failed to generate QR code")
+	}
+
+	pdf, err := helpers.RenderLetterPDF(helpers.LetterPDF{
+		InstitutionName:    letterTemplate.InstitutionName,
+		InstitutionAddress: letterTemplate.InstitutionAddress,
+		Title:              letterTemplate.Title,
+		Number:             letter.Number,
+		Body:               replacer.Replace(letterTemplate.Body),
+		Closing:            replacer.Replace(letterTemplate.Closing),
+		PlaceDate:          formatIndonesianDate(letter.IssuedAt),
+		SignatoryName:      signatoryName,
+		SignatoryTitle:     letterTemplate.SignatoryTitle,
+		QRCode:             qrCode,
+		VerifyCode:         letter.Code,
+		VerifyURL:          verifyURL,
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate letter")
+	}
+	return pdf, nil
+}
+
+// VerifyLetter mencocokkan kode QR dengan surat yang pernah diterbitkan. Surat yang peminjamannya
+// sudah dibatalkan tetap ditemukan tetapi ditandai tidak valid.
+func (u *letterUsecase) VerifyLetter(code string) (dtos.LetterVerificationResponse, error) {
+	letter, err := u.peminjamanLetterRepo.GetLetterByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return dtos.LetterVerificationResponse{}, errors.New("surat tidak ditemukan, pastikan kode verifikasi benar")
+	}
+
+	peminjaman := letter.Peminjaman
+	return dtos.LetterVerificationResponse{
+		Valid:             letterAvailable(peminjaman.Status),
+		Code:              letter.Code,
+		Number:            letter.Number,
+		IssuedAt:          letter.IssuedAt,
+		PeminjamanID:      peminjaman.ID,
+		Status:            peminjaman.Status,
+		BorrowerName:      peminjaman.User.FullName,
+		NIMNIP:            peminjaman.User.NIMNIP,
+		Lab:               peminjaman.Lab.Name,
+		TanggalPeminjaman: helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
+		JamPeminjaman:     peminjaman.JamPeminjaman,
+		JamSelesai:        peminjaman.JamSelesai,
+		Approver:          u.getApproverName(peminjaman.ID),
+	}, nil
+}
+
+func (u *letterUsecase) GetLetterTemplates() ([]dtos.LetterTemplateResponse, error) {
+	letterTemplateResponses := []dtos.LetterTemplateResponse{}
+
+	letterTemplates, err := u.letterTemplateRepo.GetLetterTemplates()
+	if err != nil {
+		return letterTemplateResponses, err
+	}
+
+	for _, letterTemplate := range letterTemplates {
+		letterTemplateResponses = append(letterTemplateResponses, toLetterTemplateResponse(letterTemplate))
+	}
+
+	return letterTemplateResponses, nil
+}
+
+func (u *letterUsecase) CreateLetterTemplate(input dtos.LetterTemplateInput) (dtos.LetterTemplateResponse, error) {
+	var letterTemplate models.LetterTemplate
+	if err := applyLetterTemplateInput(&letterTemplate, input); err != nil {
+		return dtos.LetterTemplateResponse{}, err
+	}
+
+	createdLetterTemplate, err := u.letterTemplateRepo.CreateLetterTemplate(letterTemplate)
+	if err != nil {
+		return dtos.LetterTemplateResponse{}, err
+	}
+
+	return toLetterTemplateResponse(createdLetterTemplate), nil
+}
+
+func (u *letterUsecase) UpdateLetterTemplate(id uint, input dtos.LetterTemplateInput) (dtos.LetterTemplateResponse, error) {
+	letterTemplate, err := u.letterTemplateRepo.GetLetterTemplateByID(id)
+	if err != nil {
+		return dtos.LetterTemplateResponse{}, errors.New("template surat tidak ditemukan, pastikan ID benar")
+	}
+	if err := applyLetterTemplateInput(&letterTemplate, input); err != nil {
+		return dtos.LetterTemplateResponse{}, err
+	}
+
+	updatedLetterTemplate, err := u.letterTemplateRepo.UpdateLetterTemplate(letterTemplate)
+	if err != nil {
+		return dtos.LetterTemplateResponse{}, err
+	}
+
+	return toLetterTemplateResponse(updatedLetterTemplate), nil
+}
+
+func (u *letterUsecase) DeleteLetterTemplate(id uint) error {
+	if _, err := u.letterTemplateRepo.GetLetterTemplateByID(id); err != nil {
+		return errors.New("template surat tidak ditemukan, pastikan ID benar")
+	}
+	return u.letterTemplateRepo.DeleteLetterTemplate(id)
+}
+
+func (u *letterUsecase) issueLetter(peminjaman models.Peminjaman, letterTemplate models.LetterTemplate) (models.PeminjamanLetter, error) {
+	code, err := generateLetterCode()
+	if err != nil {
+		return models.PeminjamanLetter{}, errors.New("failed to generate letter code")
+	}
+
+	now := time.Now()
+	letter := models.PeminjamanLetter{
+		PeminjamanID: peminjaman.ID,
+		Code:         code,
+		IssuedAt:     &now,
+	}
+	if letterTemplate.ID != 0 {
+		letter.LetterTemplateID = &letterTemplate.ID
+	}
+
+	letter, err = u.peminjamanLetterRepo.CreateLetter(letter)
+	if err != nil {
+		// Permintaan bersamaan bisa lebih dulu menerbitkan surat untuk peminjaman yang sama
+		if existing, getErr := u.peminjamanLetterRepo.GetLetterByPeminjamanID(peminjaman.ID); getErr == nil {
+			return existing, nil
+		}
+		return models.PeminjamanLetter{}, errors.New("failed to issue letter")
+	}
+
+	// Nomor surat memakai ID surat agar berurutan
+	letter.Number = fmt.Sprintf("%04d/PL/LAB/%d", letter.ID, now.Year())
+	return u.peminjamanLetterRepo.UpdateLetter(letter)
+}
+
+// getApproverName mengambil nama admin yang terakhir menerima peminjaman dari riwayat status
+func (u *letterUsecase) getApproverName(peminjamanID uint) string {
+	statusLogs, err := u.statusLogRepo.GetStatusLogsByPeminjamanID(peminjamanID)
+	if err != nil {
+		return "Admin Laboratorium"
+	}
+
+	var actorID *uint
+	for _, statusLog := range statusLogs {
+		if statusLog.ToStatus == models.PeminjamanStatusAccept && statusLog.ActorID != nil {
+			actorID = statusLog.ActorID
+		}
+	}
+	if actorID == nil {
+		return "Admin Laboratorium"
+	}
+
+	approver, err := u.userRepo.UserGetById2(*actorID)
+	if err != nil || approver.FullName == "" {
+		return "Admin Laboratorium"
+	}
+	return approver.FullName
+}
+
+func applyLetterTemplateInput(letterTemplate *models.LetterTemplate, input dtos.LetterTemplateInput) error {
+	if input.Name == "" {
+		return errors.New("nama template wajib diisi")
+	}
+	if input.Title == "" {
+		return errors.New("judul surat wajib diisi")
+	}
+	if input.Body == "" {
+		return errors.New("isi surat wajib diisi")
+	}
+
+	letterTemplate.Name = input.Name
+	letterTemplate.InstitutionName = input.InstitutionName
+	letterTemplate.InstitutionAddress = input.InstitutionAddress
+	letterTemplate.Title = input.Title
+	letterTemplate.Body = input.Body
+	letterTemplate.Closing = input.Closing
+	letterTemplate.SignatoryName = input.SignatoryName
+	letterTemplate.SignatoryTitle = input.SignatoryTitle
+	letterTemplate.IsActive = input.IsActive
+	return nil
+}
+
+// defaultLetterTemplate dipakai selama admin belum mengaktifkan template surat
+func defaultLetterTemplate() models.LetterTemplate {
+	return models.LetterTemplate{
+		Name:            "Default",
+		InstitutionName: "Laboratorium",
+		Title:           "SURAT IZIN PEMINJAMAN LABORATORIUM",
+		Body: "Yang bertanda tangan di bawah ini menerangkan bahwa:\n\n" +
+			"Nama: {{nama}}\nNIM/NIP: {{nim}}\n\n" +
+			"diberikan izin untuk menggunakan {{lab}} pada tanggal {{tanggal}} pukul {{jam_mulai}} - {{jam_selesai}} " +
+			"dengan keperluan {{keperluan}}. Peminjaman ini telah disetujui oleh {{approver}}.",
+		Closing:        "Demikian surat ini dibuat untuk dipergunakan sebagaimana mestinya.",
+		SignatoryTitle: "Kepala Laboratorium",
+	}
+}
+
+func letterAvailable(status string) bool {
+	return status == models.PeminjamanStatusAccept || status == models.PeminjamanStatusInUse || status == models.PeminjamanStatusFinished
+}
+
+func generateLetterCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(buf)), nil
+}
+
+func formatIndonesianDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d %s %d", date.Day(), indonesianMonths[date.Month()-1], date.Year())
+}
+
+func toLetterTemplateResponse(letterTemplate models.LetterTemplate) dtos.LetterTemplateResponse {
+	return dtos.LetterTemplateResponse{
+		LetterTemplateID:   letterTemplate.ID,
+		Name:               letterTemplate.Name,
+		InstitutionName:    letterTemplate.InstitutionName,
+		InstitutionAddress: letterTemplate.InstitutionAddress,
+		Title:              letterTemplate.Title,
+		Body:               letterTemplate.Body,
+		Closing:            letterTemplate.Closing,
+		SignatoryName:      letterTemplate.SignatoryName,
+		SignatoryTitle:     letterTemplate.SignatoryTitle,
+		IsActive:           letterTemplate.IsActive,
+		CreatedAt:          letterTemplate.CreatedAt,
+		UpdatedAt:          letterTemplate.UpdatedAt,
+	}
+}
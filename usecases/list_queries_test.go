@@ -0,0 +1,193 @@
+package usecases_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"sistem_peminjaman_be/testutil"
+	"sistem_peminjaman_be/usecases"
+
+	"gorm.io/gorm"
+)
+
+// queryCounter menghitung query yang dijalankan GORM lewat callback, termasuk query Preload dan Count
+type queryCounter struct {
+	count int64
+}
+
+func countQueries(t *testing.T, db *gorm.DB) *queryCounter {
+	t.Helper()
+
+	counter := &queryCounter{}
+	increment := func(*gorm.DB) { atomic.AddInt64(&counter.count, 1) }
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_query", increment); err != nil {
+		t.Fatalf("gagal memasang callback query: %v", err)
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:count_row", increment); err != nil {
+		t.Fatalf("gagal memasang callback row: %v", err)
+	}
+	return counter
+}
+
+// run mengembalikan jumlah query yang dijalankan fn
+func (c *queryCounter) run(t *testing.T, fn func() error) int64 {
+	t.Helper()
+
+	atomic.StoreInt64(&c.count, 0)
+	if err := fn(); err != nil {
+		t.Fatalf("list gagal: %v", err)
+	}
+	return atomic.LoadInt64(&c.count)
+}
+
+// seedListData membuat n lab, user, peminjaman, dan jadwal yang masing-masing punya lampiran
+// sehingga setiap relasi yang dimuat list benar-benar terisi
+func seedListData(t *testing.T, db *gorm.DB, n int) {
+	t.Helper()
+
+	tanggal := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	for i := 0; i < n; i++ {
+		lab := models.Lab{Name: fmt.Sprintf("Lab List %d", i)}
+		if err := db.Create(&lab).Error; err != nil {
+			t.Fatalf("gagal membuat lab: %v", err)
+		}
+		if err := db.Create(&models.LabImage{LabID: lab.ID, ImageUrl: "lab.png"}).Error; err != nil {
+			t.Fatalf("gagal membuat gambar lab: %v", err)
+		}
+
+		user := models.User{FullName: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("list%d@test.local", i), Role: models.UserRoleUser}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("gagal membuat user: %v", err)
+		}
+
+		peminjaman := models.Peminjaman{
+			UserID:            user.ID,
+			LabID:             lab.ID,
+			TanggalPeminjaman: &tanggal,
+			JamPeminjaman:     "08:00",
+			JamSelesai:        "10:00",
+			Status:            models.PeminjamanStatusAccept,
+		}
+		if err := db.Create(&peminjaman).Error; err != nil {
+			t.Fatalf("gagal membuat peminjaman: %v", err)
+		}
+		if err := db.Create(&models.SuratRekomendasiImage{PeminjamanID: peminjaman.ID, SuratRekomendasiImageUrl: "surat.pdf"}).Error; err != nil {
+			t.Fatalf("gagal membuat surat rekomendasi: %v", err)
+		}
+
+		jadwal := models.Jadwal{
+			TanggalJadwal:    &tanggal,
+			WaktuJadwal:      "08:00",
+			WaktuSelesai:     "10:00",
+			LabID:            &lab.ID,
+			UserID:           &user.ID,
+			PeminjamanID:     &peminjaman.ID,
+			NameUser:         user.FullName,
+			NameLaboratorium: lab.Name,
+			Status:           models.JadwalStatusNotUsed,
+		}
+		if err := db.Omit("Lab", "User", "Peminjaman").Create(&jadwal).Error; err != nil {
+			t.Fatalf("gagal membuat jadwal: %v", err)
+		}
+		if err := db.Create(&models.BeritaAcaraImage{JadwalID: jadwal.ID, BeritaAcaraImageUrl: "berita.pdf"}).Error; err != nil {
+			t.Fatalf("gagal membuat berita acara: %v", err)
+		}
+	}
+}
+
+// assertConstantQueries memastikan jumlah query satu halaman tidak bertambah mengikuti ukuran halaman
+func assertConstantQueries(t *testing.T, counter *queryCounter, list func(limit int) error) {
+	t.Helper()
+
+	var baseline int64
+	for _, limit := range []int{1, 5, 20} {
+		queries := counter.run(t, func() error { return list(limit) })
+		if queries == 0 {
+			t.Fatalf("callback tidak mencatat query apa pun")
+		}
+		if limit == 1 {
+			baseline = queries
+			continue
+		}
+		if queries != baseline {
+			t.Fatalf("halaman %d baris menjalankan %d query, halaman 1 baris %d query", limit, queries, baseline)
+		}
+	}
+}
+
+func TestListQueriesConstantPerPage(t *testing.T) {
+	db := testutil.OpenTestDB(t, "usecases")
+	seedListData(t, db, 20)
+	counter := countQueries(t, db)
+
+	labRepo := repositories.NewLabRepository(db)
+	labImageRepo := repositories.NewLabImageRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	peminjamanRepo := repositories.NewPeminjamanRepository(db)
+	jadwalRepo := repositories.NewJadwalRepository(db)
+	labSlotRepo := repositories.NewLabSlotRepository(db)
+	labClosureRepo := repositories.NewLabClosureRepository(db)
+
+	peminjamanUsecase := usecases.NewPeminjamanUsecase(
+		peminjamanRepo,
+		repositories.NewSuratRekomendasiImageRepository(db),
+		labRepo,
+		labImageRepo,
+		userRepo,
+		labSlotRepo,
+		repositories.NewPeminjamanSeriesRepository(db),
+		repositories.NewPeminjamanStatusLogRepository(db),
+		repositories.NewTemplateMessageRepository(db),
+		repositories.NewNotificationRepository(db),
+		repositories.NewApprovalRepository(db),
+		repositories.NewPeminjamanWaitlistRepository(db),
+		jadwalRepo,
+		repositories.NewDamageCaseRepository(db),
+		repositories.NewPenaltyRepository(db),
+		repositories.NewBookingQuotaRepository(db),
+		labClosureRepo,
+		repositories.NewPeminjamanParticipantRepository(db),
+		repositories.NewPeminjamanRescheduleRepository(db),
+		usecases.PeminjamanCancelPolicy{},
+		usecases.CheckinPolicy{},
+		usecases.PenaltyPolicy{},
+	)
+	jadwalUsecase := usecases.NewJadwalUsecase(jadwalRepo, repositories.NewBeritaAcaraImageRepository(db), userRepo, labRepo, labSlotRepo, labClosureRepo)
+	historySeenLabUsecase := usecases.NewHistorySeenLabUsecase(repositories.NewHistorySeenLabRepository(db), labRepo, labImageRepo)
+	labUsecase := usecases.NewLabUsecase(labRepo, labImageRepo, repositories.NewHistorySearchRepository(db), userRepo, historySeenLabUsecase, peminjamanRepo, jadwalRepo, labSlotRepo, labClosureRepo)
+
+	t.Run("peminjaman", func(t *testing.T) {
+		assertConstantQueries(t, counter, func(limit int) error {
+			peminjamans, _, err := peminjamanUsecase.GetPeminjamansByAdmin(1, limit, dtos.PeminjamanFilter{})
+			if err == nil && len(peminjamans) != limit {
+				return fmt.Errorf("dapat %d peminjaman, seharusnya %d", len(peminjamans), limit)
+			}
+			return err
+		})
+	})
+
+	t.Run("jadwal", func(t *testing.T) {
+		assertConstantQueries(t, counter, func(limit int) error {
+			jadwals, _, err := jadwalUsecase.GetAllJadwals(1, limit, "")
+			if err == nil && len(jadwals) != limit {
+				return fmt.Errorf("dapat %d jadwal, seharusnya %d", len(jadwals), limit)
+			}
+			return err
+		})
+	})
+
+	t.Run("lab", func(t *testing.T) {
+		assertConstantQueries(t, counter, func(limit int) error {
+			labs, _, err := labUsecase.GetAllLabs(1, limit, "")
+			if err == nil && len(labs) != limit {
+				return fmt.Errorf("dapat %d lab, seharusnya %d", len(labs), limit)
+			}
+			return err
+		})
+	})
+}
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/models"
 	"sistem_peminjaman_be/repositories"
 	"strings"
 )
@@ -73,4 +74,45 @@ func (u *notificationUsecase) GetNotificationByUserID(id uint) (dtos.Notificatio
 	}
 
 	return notificationResponse, nil
-}
\ No newline at end of file
+}
+// notifyUsers membuat satu template pesan lalu mengirimkannya sebagai notifikasi ke setiap user
+func notifyUsers(templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, userIDs []uint, title, content string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	template, err := templateMessageRepo.CreateTemplateMessage(models.TemplateMessage{
+		Title:   title,
+		Content: content,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		_, err := notificationRepo.CreateNotification(models.Notification{
+			UserID:     userID,
+			TemplateID: template.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifyAdmins mengirim notifikasi ke semua admin
+func notifyAdmins(templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, userRepo repositories.UserRepository, title, content string) error {
+	admins, err := userRepo.UserGetByRole("admin")
+	if err != nil {
+		return err
+	}
+
+	var adminIDs []uint
+	for _, admin := range admins {
+		adminIDs = append(adminIDs, admin.ID)
+	}
+
+	return notifyUsers(templateMessageRepo, notificationRepo, adminIDs, title, content)
+}
@@ -2,6 +2,8 @@ package usecases
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"sistem_peminjaman_be/dtos"
 	"sistem_peminjaman_be/helpers"
 	"sistem_peminjaman_be/models"
@@ -11,14 +13,33 @@ import (
 )
 
 type PeminjamanUsecase interface {
-	GetPeminjamans(page, limit int, userID uint, nameLaboratorium, status string) ([]dtos.PeminjamanResponse, int, error)
+	GetPeminjamans(page, limit int, userID uint, filter dtos.PeminjamanFilter) ([]dtos.PeminjamanResponse, int, error)
 	GetPeminjamanByID(userId, peminjamanId uint) (dtos.PeminjamanResponse, error)
-	GetPeminjamansByAdmin(page, limit int, search, status string) ([]dtos.PeminjamanResponse, int, error)
+	GetPeminjamansByAdmin(page, limit int, filter dtos.PeminjamanFilter) ([]dtos.PeminjamanResponse, int, error)
 	GetPeminjamansDetailByAdmin(peminjamanId uint) (dtos.PeminjamanResponse, error)
 	AdminGetPeminjamanByID(peminjamanId uint)  (dtos.PeminjamanResponse, error)
 	CreatePeminjaman(userID uint, peminjaman *dtos.PeminjamanInput) (dtos.PeminjamanResponse, error)
-	AdminUpdatePeminjaman(id uint, peminjaman dtos.PeminjamanInput) (dtos.PeminjamanResponse, error)
-	UpdatePeminjaman(id uint, peminjaman dtos.PeminjamanInput) (dtos.StatusResponse, error)
+	AdminUpdatePeminjaman(id, adminID uint, peminjaman dtos.PeminjamanInput) (dtos.PeminjamanResponse, error)
+	UpdatePeminjaman(id, adminID uint, peminjaman dtos.PeminjamanInput) (dtos.StatusResponse, error)
+	CancelPeminjaman(userID, id uint, input dtos.PeminjamanCancelInput) (dtos.PeminjamanCancelResponse, error)
+	TransitionPeminjamanStatus(id uint, toStatus string, actorID *uint, actorRole, reason string) (dtos.StatusResponse, error)
+	TransitionPeminjamanStatusWithApproval(approval models.PeminjamanApproval, toStatus string, reason string) (dtos.StatusResponse, error)
+	BulkUpdatePeminjamanStatus(adminID uint, input dtos.PeminjamanBulkStatusInput) (dtos.PeminjamanBulkStatusResponse, error)
+	RequestReschedule(userID, id uint, input dtos.PeminjamanRescheduleInput) (dtos.PeminjamanRescheduleResponse, error)
+	GetReschedules(page, limit int, status string) ([]dtos.PeminjamanRescheduleResponse, int, error)
+	DecideReschedule(adminID, id uint, input dtos.PeminjamanRescheduleDecisionInput) (dtos.PeminjamanRescheduleResponse, error)
+	ApplyRescheduleWithApproval(reschedule models.PeminjamanReschedule, approval models.PeminjamanApproval) (dtos.PeminjamanRescheduleResponse, error)
+	RejectRescheduleWithApproval(reschedule models.PeminjamanReschedule, approval models.PeminjamanApproval) (dtos.PeminjamanRescheduleResponse, error)
+	JoinWaitlist(userID uint, input dtos.PeminjamanWaitlistInput) (dtos.PeminjamanWaitlistResponse, error)
+	GetWaitlists(userID uint) ([]dtos.PeminjamanWaitlistResponse, error)
+	LeaveWaitlist(userID, id uint) error
+	CreatePeminjamanSeries(userID uint, peminjaman *dtos.PeminjamanInput) (dtos.PeminjamanSeriesResponse, error)
+	GetPeminjamanSeriesByID(userID, seriesID uint) (dtos.PeminjamanSeriesResponse, error)
+	UpdatePeminjamanSeries(seriesID, adminID uint, input dtos.PeminjamanSeriesStatusInput) (dtos.PeminjamanSeriesResponse, error)
+	GetPeminjamanQRCode(userID, id uint) ([]byte, error)
+	GetJadwalQRCode(userID, id uint) ([]byte, error)
+	Checkin(actorID uint, role string, input dtos.CheckinInput) (dtos.CheckinResponse, error)
+	Checkout(actorID uint, role string, input dtos.CheckinInput) (dtos.CheckinResponse, error)
 }
 
 type peminjamanUsecase struct {
@@ -27,66 +48,77 @@ type peminjamanUsecase struct {
 	labRepo                   repositories.LabRepository
 	labImageRepo              repositories.LabImageRepository
 	userRepo                  repositories.UserRepository
+	labSlotRepo               repositories.LabSlotRepository
+	peminjamanSeriesRepo      repositories.PeminjamanSeriesRepository
+	peminjamanStatusLogRepo   repositories.PeminjamanStatusLogRepository
+	templateMessageRepo       repositories.TemplateMessageRepository
+	notificationRepo          repositories.NotificationRepository
+	approvalRepo              repositories.ApprovalRepository
+	peminjamanWaitlistRepo    repositories.PeminjamanWaitlistRepository
+	jadwalRepo                repositories.JadwalRepository
+	damageCaseRepo            repositories.DamageCaseRepository
+	penaltyRepo               repositories.PenaltyRepository
+	bookingQuotaRepo          repositories.BookingQuotaRepository
+	labClosureRepo            repositories.LabClosureRepository
+	participantRepo           repositories.PeminjamanParticipantRepository
+	peminjamanRescheduleRepo  repositories.PeminjamanRescheduleRepository
+	cancelPolicy              PeminjamanCancelPolicy
+	checkinPolicy             CheckinPolicy
+	penaltyPolicy             PenaltyPolicy
 }
 
-func NewPeminjamanUsecase(peminjamanRepo repositories.PeminjamanRepository, suratRekomendasiImageRepo repositories.SuratRekomendasiImageRepository, labRepo repositories.LabRepository, labImageRepo repositories.LabImageRepository, userRepo repositories.UserRepository) PeminjamanUsecase {
-	return &peminjamanUsecase{peminjamanRepo, suratRekomendasiImageRepo, labRepo, labImageRepo, userRepo}
+// PeminjamanCancelPolicy mengatur kapan user masih boleh membatalkan peminjaman.
+// Cutoff adalah batas terakhir sebelum peminjaman dimulai, pembatalan di dalam LateWindow
+// tetap diterima tetapi ditandai sebagai pembatalan terlambat.
+type PeminjamanCancelPolicy struct {
+	Cutoff     time.Duration
+	LateWindow time.Duration
 }
 
-func (u *peminjamanUsecase) GetPeminjamans(page, limit int, userID uint, nameLaboratorium, status string) ([]dtos.PeminjamanResponse, int, error) {
+func NewPeminjamanUsecase(peminjamanRepo repositories.PeminjamanRepository, suratRekomendasiImageRepo repositories.SuratRekomendasiImageRepository, labRepo repositories.LabRepository, labImageRepo repositories.LabImageRepository, userRepo repositories.UserRepository, labSlotRepo repositories.LabSlotRepository, peminjamanSeriesRepo repositories.PeminjamanSeriesRepository, peminjamanStatusLogRepo repositories.PeminjamanStatusLogRepository, templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, approvalRepo repositories.ApprovalRepository, peminjamanWaitlistRepo repositories.PeminjamanWaitlistRepository, jadwalRepo repositories.JadwalRepository, damageCaseRepo repositories.DamageCaseRepository, penaltyRepo repositories.PenaltyRepository, bookingQuotaRepo repositories.BookingQuotaRepository, labClosureRepo repositories.LabClosureRepository, participantRepo repositories.PeminjamanParticipantRepository, peminjamanRescheduleRepo repositories.PeminjamanRescheduleRepository, cancelPolicy PeminjamanCancelPolicy, checkinPolicy CheckinPolicy, penaltyPolicy PenaltyPolicy) PeminjamanUsecase {
+	return &peminjamanUsecase{peminjamanRepo, suratRekomendasiImageRepo, labRepo, labImageRepo, userRepo, labSlotRepo, peminjamanSeriesRepo, peminjamanStatusLogRepo, templateMessageRepo, notificationRepo, approvalRepo, peminjamanWaitlistRepo, jadwalRepo, damageCaseRepo, penaltyRepo, bookingQuotaRepo, labClosureRepo, participantRepo, peminjamanRescheduleRepo, cancelPolicy, checkinPolicy, penaltyPolicy}
+}
+
+func (u *peminjamanUsecase) GetPeminjamans(page, limit int, userID uint, filter dtos.PeminjamanFilter) ([]dtos.PeminjamanResponse, int, error) {
 	var peminjamanResponses []dtos.PeminjamanResponse
 
-	peminjamans, _, err := u.peminjamanRepo.GetPeminjamans(page, limit, userID, status)
-	if err != nil {
+	if err := validatePeminjamanFilter(filter); err != nil {
 		return peminjamanResponses, 0, err
 	}
+	filter.UserID = userID
 
-	for _, peminjaman := range peminjamans {
-
-		getSuratRekomendasiImage, err := u.suratRekomendasiImageRepo.GetAllSuratRekomendasiImageByID(peminjaman.ID)
-		if err != nil {
-			continue
-		}
-
-		var suratRekomendasiImageResponses []dtos.SuratRekomendasiImageResponse
-		for _, suratRekomendasiImage := range getSuratRekomendasiImage {
-			suratRekomendasiImageResponse := dtos.SuratRekomendasiImageResponse{
-				PeminjamanID:             suratRekomendasiImage.PeminjamanID,
-				SuratRekomendasiImageUrl: suratRekomendasiImage.SuratRekomendasiImageUrl,
-			}
-			suratRekomendasiImageResponses = append(suratRekomendasiImageResponses, suratRekomendasiImageResponse)
-		}
-
-		getLab, err := u.labRepo.GetLabByID2(peminjaman.LabID)
-		if err != nil {
-			return peminjamanResponses, 0, err
-		}
-
-		if nameLaboratorium != "" && !strings.Contains(strings.ToLower(getLab.Name), strings.ToLower(nameLaboratorium)) {
-			continue
-		}
+	peminjamans, count, err := u.peminjamanRepo.GetPeminjamans(page, limit, filter)
+	if err != nil {
+		return peminjamanResponses, 0, err
+	}
 
-		getLabImage, err := u.labImageRepo.GetAllLabImageByID(peminjaman.LabID)
-		if err != nil {
-			continue
-		}
+	relations, err := u.loadPeminjamanListRelations(peminjamans, false)
+	if err != nil {
+		return peminjamanResponses, 0, err
+	}
 
-		var labImageResponses []dtos.LabImageResponse
-		for _, labImage := range getLabImage {
-			labImageResponse := dtos.LabImageResponse{
-				LabID:    labImage.LabID,
-				ImageUrl: labImage.ImageUrl,
-			}
-			labImageResponses = append(labImageResponses, labImageResponse)
+	for _, peminjaman := range peminjamans {
+		getLab, ok := relations.labs[peminjaman.LabID]
+		if !ok {
+			return peminjamanResponses, 0, errors.New("lab peminjaman tidak ditemukan")
 		}
+		suratRekomendasiImageResponses := relations.suratRekomendasiImages[peminjaman.ID]
+		labImageResponses := relations.labImages[peminjaman.LabID]
 
 		peminjamanResponse := dtos.PeminjamanResponse{
 			PeminjamanID:          int(peminjaman.ID),
 			TanggalPeminjaman:     helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
 			JamPeminjaman:         peminjaman.JamPeminjaman,
+			JamSelesai:            peminjaman.JamSelesai,
+			LabSlotID:             peminjaman.LabSlotID,
 			SuratRekomendasiImage: suratRekomendasiImageResponses,
 			Description:           peminjaman.Description,
 			Status:                peminjaman.Status,
+			SeriesID:              peminjaman.PeminjamanSeriesID,
+			CancelReason:          peminjaman.CancelReason,
+			CancelledAt:           peminjaman.CancelledAt,
+			LateCancellation:      peminjaman.LateCancellation,
+			Usage:                 toPeminjamanUsageResponse(peminjaman),
 			Lab: dtos.LabByIDResponses{
 				LabID:       getLab.ID,
 				Name:        getLab.Name,
@@ -99,14 +131,14 @@ func (u *peminjamanUsecase) GetPeminjamans(page, limit int, userID uint, nameLab
 		peminjamanResponses = append(peminjamanResponses, peminjamanResponse)
 	}
 
-	return peminjamanResponses, len(peminjamanResponses), nil
+	return peminjamanResponses, count, nil
 }
 
 func (u *peminjamanUsecase) GetPeminjamanByID(userId, peminjamanId uint) (dtos.PeminjamanResponse, error) {
     peminjamanResponses := dtos.PeminjamanResponse{}
 
-    // Mendapatkan data peminjaman berdasarkan ID
-    peminjaman, err := u.peminjamanRepo.GetPeminjamanByID(peminjamanId, userId)
+    // Mendapatkan data peminjaman berdasarkan ID, peserta kelompok juga boleh melihat peminjaman
+    peminjaman, err := u.getPeminjamanForMember(peminjamanId, userId)
     if err != nil {
         if strings.Contains(err.Error(), "not found") {
             return peminjamanResponses, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
@@ -150,14 +182,45 @@ func (u *peminjamanUsecase) GetPeminjamanByID(userId, peminjamanId uint) (dtos.P
         labImageResponses = append(labImageResponses, labImageResponse)
     }
 
+    statusLogResponses, err := u.getStatusLogResponses(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get status log")
+    }
+
+    approvals, err := u.approvalRepo.GetPeminjamanApprovals(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get approval")
+    }
+
+    participants, err := u.participantRepo.GetParticipantsByPeminjamanID(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get participants")
+    }
+
+    rescheduleResponses, err := u.getRescheduleResponses(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get reschedule")
+    }
+
     // Membuat respons peminjaman
     peminjamanResponse := dtos.PeminjamanResponse{
         PeminjamanID:          int(peminjaman.ID),
         TanggalPeminjaman:     helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
         JamPeminjaman:         peminjaman.JamPeminjaman,
+        JamSelesai:            peminjaman.JamSelesai,
+        LabSlotID:             peminjaman.LabSlotID,
         SuratRekomendasiImage: suratRekomendasiImageResponses,
         Description:           peminjaman.Description,
         Status:                peminjaman.Status,
+        SeriesID:              peminjaman.PeminjamanSeriesID,
+        CancelReason:          peminjaman.CancelReason,
+        CancelledAt:           peminjaman.CancelledAt,
+        LateCancellation:      peminjaman.LateCancellation,
+        Usage:                 toPeminjamanUsageResponse(peminjaman),
+        Approvals:             toPeminjamanApprovalResponses(approvals),
+        StatusLogs:            statusLogResponses,
+        Participants:          toPeminjamanParticipantResponses(participants),
+        Reschedules:           rescheduleResponses,
         Lab: dtos.LabByIDResponses{
             LabID:       getLab.ID,
             Name:        getLab.Name,
@@ -224,14 +287,45 @@ func (u *peminjamanUsecase) AdminGetPeminjamanByID(peminjamanId uint) (dtos.Pemi
         labImageResponses = append(labImageResponses, labImageResponse)
     }
 
+    statusLogResponses, err := u.getStatusLogResponses(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get status log")
+    }
+
+    approvals, err := u.approvalRepo.GetPeminjamanApprovals(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get approval")
+    }
+
+    participants, err := u.participantRepo.GetParticipantsByPeminjamanID(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get participants")
+    }
+
+    rescheduleResponses, err := u.getRescheduleResponses(peminjaman.ID)
+    if err != nil {
+        return peminjamanResponses, errors.New("failed to get reschedule")
+    }
+
     // Membuat respons peminjaman
     peminjamanResponse := dtos.PeminjamanResponse{
         PeminjamanID:          int(peminjaman.ID),
         TanggalPeminjaman:     helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
         JamPeminjaman:         peminjaman.JamPeminjaman,
+        JamSelesai:            peminjaman.JamSelesai,
+        LabSlotID:             peminjaman.LabSlotID,
         SuratRekomendasiImage: suratRekomendasiImageResponses,
         Description:           peminjaman.Description,
         Status:                peminjaman.Status,
+        SeriesID:              peminjaman.PeminjamanSeriesID,
+        CancelReason:          peminjaman.CancelReason,
+        CancelledAt:           peminjaman.CancelledAt,
+        LateCancellation:      peminjaman.LateCancellation,
+        Usage:                 toPeminjamanUsageResponse(peminjaman),
+        Approvals:             toPeminjamanApprovalResponses(approvals),
+        StatusLogs:            statusLogResponses,
+        Participants:          toPeminjamanParticipantResponses(participants),
+        Reschedules:           rescheduleResponses,
         Lab: dtos.LabByIDResponses{
             LabID:       lab.ID,
             Name:        lab.Name,
@@ -254,67 +348,51 @@ func (u *peminjamanUsecase) AdminGetPeminjamanByID(peminjamanId uint) (dtos.Pemi
 
 
 
-func (u *peminjamanUsecase) GetPeminjamansByAdmin(page, limit int, search, status string) ([]dtos.PeminjamanResponse, int, error) {
+func (u *peminjamanUsecase) GetPeminjamansByAdmin(page, limit int, filter dtos.PeminjamanFilter) ([]dtos.PeminjamanResponse, int, error) {
 	var peminjamanResponses []dtos.PeminjamanResponse
 
-	peminjamans, _, err := u.peminjamanRepo.GetPeminjamans(page, limit, 1, status)
-	if err != nil {
+	if err := validatePeminjamanFilter(filter); err != nil {
 		return peminjamanResponses, 0, err
 	}
+	filter.UserID = 0
 
-	for _, peminjaman := range peminjamans {
-
-		getSuratRekomendasiImage, err := u.suratRekomendasiImageRepo.GetAllSuratRekomendasiImageByID(peminjaman.ID)
-		if err != nil {
-			continue
-		}
-
-		var suratRekomendasiImageResponses []dtos.SuratRekomendasiImageResponse
-		for _, suratRekomendasiImage := range getSuratRekomendasiImage {
-			suratRekomendasiImageResponse := dtos.SuratRekomendasiImageResponse{
-				PeminjamanID:             suratRekomendasiImage.PeminjamanID,
-				SuratRekomendasiImageUrl: suratRekomendasiImage.SuratRekomendasiImageUrl,
-			}
-			suratRekomendasiImageResponses = append(suratRekomendasiImageResponses, suratRekomendasiImageResponse)
-		}
-
-		getLab, err := u.labRepo.GetLabByID2(peminjaman.LabID)
-		if err != nil {
-			return peminjamanResponses, 0, err
-		}
-
-		if search != "" &&
-			!strings.Contains(strings.ToLower(getLab.Name), strings.ToLower(search)) {
-			continue // Skip hotel order if it doesn't match the search query
-		}
+	peminjamans, count, err := u.peminjamanRepo.GetPeminjamans(page, limit, filter)
+	if err != nil {
+		return peminjamanResponses, 0, err
+	}
 
-		getUser, err := u.userRepo.UserGetById2(peminjaman.UserID)
-		if err != nil {
-			return peminjamanResponses, 0, err
-		}
+	relations, err := u.loadPeminjamanListRelations(peminjamans, true)
+	if err != nil {
+		return peminjamanResponses, 0, err
+	}
 
-		getLabImage, err := u.labImageRepo.GetAllLabImageByID(peminjaman.LabID)
-		if err != nil {
-			continue
+	for _, peminjaman := range peminjamans {
+		getLab, ok := relations.labs[peminjaman.LabID]
+		if !ok {
+			return peminjamanResponses, 0, errors.New("lab peminjaman tidak ditemukan")
 		}
-
-		var labImageResponses []dtos.LabImageResponse
-		for _, labImage := range getLabImage {
-			labImageResponse := dtos.LabImageResponse{
-				LabID:    labImage.LabID,
-				ImageUrl: labImage.ImageUrl,
-			}
-			labImageResponses = append(labImageResponses, labImageResponse)
+		getUser, ok := relations.users[peminjaman.UserID]
+		if !ok {
+			return peminjamanResponses, 0, errors.New("user peminjaman tidak ditemukan")
 		}
+		suratRekomendasiImageResponses := relations.suratRekomendasiImages[peminjaman.ID]
+		labImageResponses := relations.labImages[peminjaman.LabID]
 
 		// Membuat respons peminjaman
 		peminjamanResponse := dtos.PeminjamanResponse{
 			PeminjamanID:          int(peminjaman.ID),
 			TanggalPeminjaman:     helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
 			JamPeminjaman:         peminjaman.JamPeminjaman,
+			JamSelesai:            peminjaman.JamSelesai,
+			LabSlotID:             peminjaman.LabSlotID,
 			SuratRekomendasiImage: suratRekomendasiImageResponses,
 			Description:           peminjaman.Description,
 			Status:                peminjaman.Status,
+			SeriesID:              peminjaman.PeminjamanSeriesID,
+			CancelReason:          peminjaman.CancelReason,
+			CancelledAt:           peminjaman.CancelledAt,
+			LateCancellation:      peminjaman.LateCancellation,
+			Usage:                 toPeminjamanUsageResponse(peminjaman),
 			Lab: dtos.LabByIDResponses{
 				LabID:       getLab.ID,
 				Name:        getLab.Name,
@@ -335,7 +413,7 @@ func (u *peminjamanUsecase) GetPeminjamansByAdmin(page, limit int, search, statu
 		peminjamanResponses = append(peminjamanResponses, peminjamanResponse)
 	}
 
-	return peminjamanResponses, len(peminjamanResponses), nil
+	return peminjamanResponses, count, nil
 
 }
 
@@ -389,9 +467,16 @@ func (u *peminjamanUsecase) GetPeminjamansDetailByAdmin(peminjamanId uint) (dtos
 		PeminjamanID:          int(peminjaman.ID),
 		TanggalPeminjaman:     helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
 		JamPeminjaman:         peminjaman.JamPeminjaman,
+		JamSelesai:            peminjaman.JamSelesai,
+		LabSlotID:             peminjaman.LabSlotID,
 		SuratRekomendasiImage: suratRekomendasiImageResponses,
 		Description:           peminjaman.Description,
 		Status:                peminjaman.Status,
+		SeriesID:              peminjaman.PeminjamanSeriesID,
+		CancelReason:          peminjaman.CancelReason,
+		CancelledAt:           peminjaman.CancelledAt,
+		LateCancellation:      peminjaman.LateCancellation,
+		Usage:                 toPeminjamanUsageResponse(peminjaman),
 		Lab: dtos.LabByIDResponses{
 			LabID:       getLab.ID,
 			Name:        getLab.Name,
@@ -415,12 +500,26 @@ func (u *peminjamanUsecase) CreatePeminjaman(userID uint, PeminjamanInput *dtos.
 		return peminjamanResponse, errors.New("failed to get user")
 	}
 
+	// User dengan kasus kerusakan terbuka atau poin penalti melewati batas tidak bisa mengajukan peminjaman baru
+	if err := checkBorrowingStanding(u.damageCaseRepo, u.penaltyRepo, u.penaltyPolicy, getUsers.ID); err != nil {
+		return peminjamanResponse, err
+	}
+
 	// Mendapatkan data lab berdasarkan ID yang diberikan di input
 	getLabs, err := u.labRepo.GetLabByID(uint(PeminjamanInput.LabID))
 	if err != nil {
 		return peminjamanResponse, errors.New("failed to get lab")
 	}
 
+	// Peminjaman kelompok mencatat peserta dan jumlahnya tidak boleh melebihi kapasitas lab
+	participants, err := resolveParticipants(u.userRepo, getUsers, PeminjamanInput.Participants)
+	if err != nil {
+		return peminjamanResponse, err
+	}
+	if err := checkLabCapacity(getLabs, participants); err != nil {
+		return peminjamanResponse, err
+	}
+
 	// Cek apakah tanggal peminjaman valid
 	if PeminjamanInput.TanggalPeminjaman == nil || *PeminjamanInput.TanggalPeminjaman < time.Now().Format("2006-01-02") {
 		return peminjamanResponse, errors.New("tanggal peminjaman invalid")
@@ -437,19 +536,44 @@ func (u *peminjamanUsecase) CreatePeminjaman(userID uint, PeminjamanInput *dtos.
 		return peminjamanResponse, errors.New("tanggal peminjaman harus setelah tanggal sekarang")
 	}
 
+	// Memastikan jam peminjaman sesuai slot yang dikonfigurasi untuk lab pada hari tersebut
+	labSlot, err := resolveLabSlot(u.labSlotRepo, getLabs.ID, tanggalPeminjamanParse, PeminjamanInput.JamPeminjaman, PeminjamanInput.LabSlotID)
+	if err != nil {
+		return peminjamanResponse, err
+	}
+
+	// Menolak peminjaman pada hari libur atau saat lab ditutup
+	if err := checkLabClosure(u.labClosureRepo, getLabs.ID, tanggalPeminjamanParse, labSlot.JamMulai, labSlot.JamSelesai); err != nil {
+		return peminjamanResponse, err
+	}
+
+	// Menyalin rantai persetujuan lab agar tiap tahap bisa diputuskan approver
+	approvals, err := approvalChainForLab(u.approvalRepo, getLabs.ID)
+	if err != nil {
+		return peminjamanResponse, errors.New("failed to get approval chain")
+	}
+
 	// Membuat struktur Peminjaman dari data input
 	createPeminjaman := models.Peminjaman{
 		UserID:            getUsers.ID,
 		LabID:             getLabs.ID,  // Menggunakan ID lab yang sudah didapatkan
 		TanggalPeminjaman: &tanggalPeminjamanParse,
-		JamPeminjaman:     PeminjamanInput.JamPeminjaman,
+		JamPeminjaman:     labSlot.JamMulai,
+		JamSelesai:        labSlot.JamSelesai,
+		LabSlotID:         &labSlot.ID,
 		Description:       PeminjamanInput.Description,
-		Status:            "request",
+		Status:            models.PeminjamanStatusRequest,
+		Approvals:         approvals,
+		Participants:      participants,
 	}
 
-	// Menyimpan data peminjaman ke repository
-	createdPeminjaman, err := u.peminjamanRepo.CreatePeminjaman(createPeminjaman)
+	// Menyimpan data peminjaman ke repository, ditolak jika slot sudah dipakai atau peminjaman ini
+	// tidak lagi masuk kuota role user dan lab
+	createdPeminjaman, err := u.peminjamanRepo.CreatePeminjamanIfAvailable(createPeminjaman, bookingQuotaCheck(getUsers, getLabs.ID, tanggalPeminjamanParse, labSlot.JamMulai, labSlot.JamSelesai, nil))
 	if err != nil {
+		if errors.Is(err, helpers.ErrSlotConflict) || errors.Is(err, helpers.ErrQuotaExceeded) {
+			return peminjamanResponse, err
+		}
 		return peminjamanResponse, errors.New("failed to create peminjaman")
 	}
 
@@ -504,9 +628,13 @@ func (u *peminjamanUsecase) CreatePeminjaman(userID uint, PeminjamanInput *dtos.
 		PeminjamanID:          int(peminjaman.ID),
 		TanggalPeminjaman:     helpers.FormatDateToYMD(createdPeminjaman.TanggalPeminjaman),
 		JamPeminjaman:         createPeminjaman.JamPeminjaman,
+		JamSelesai:            createPeminjaman.JamSelesai,
+		LabSlotID:             createPeminjaman.LabSlotID,
 		SuratRekomendasiImage: suratRekomendasiImageResponses,
 		Description:           createPeminjaman.Description,
 		Status:                createPeminjaman.Status,
+		SeriesID:              createPeminjaman.PeminjamanSeriesID,
+		Participants:          toPeminjamanParticipantResponses(createdPeminjaman.Participants),
 		Lab: dtos.LabByIDResponses{
 			LabID:       getLabs.ID,
 			Name:        getLabs.Name,
@@ -517,11 +645,18 @@ func (u *peminjamanUsecase) CreatePeminjaman(userID uint, PeminjamanInput *dtos.
 		UpdatedAt: createdPeminjaman.UpdatedAt,
 	}
 
+	// Sisa kuota setelah peminjaman ini ikut dikembalikan agar frontend bisa langsung menampilkannya
+	quota, err := quotaStatuses(u.bookingQuotaRepo, getUsers, getLabs.ID, tanggalPeminjamanParse)
+	if err != nil {
+		log.Printf("gagal menghitung kuota user %d: %v", getUsers.ID, err)
+	}
+	peminjamanResponse.Quota = quota
+
 	return peminjamanResponse, nil
 }
 
 
-func (u *peminjamanUsecase) UpdatePeminjaman(id uint, peminjaman dtos.PeminjamanInput) (dtos.StatusResponse, error) {
+func (u *peminjamanUsecase) UpdatePeminjaman(id, adminID uint, peminjaman dtos.PeminjamanInput) (dtos.StatusResponse, error) {
 	var peminjamans models.Peminjaman
 	var statusResponse dtos.StatusResponse
 
@@ -535,10 +670,7 @@ func (u *peminjamanUsecase) UpdatePeminjaman(id uint, peminjaman dtos.Peminjaman
 		return statusResponse, errors.New("failed to update peminjaman")
 	}
 
-	
-	peminjamans.Status = peminjaman.Status
-
-	updatedPeminjaman, err := u.peminjamanRepo.UpdatePeminjaman(peminjamans)
+	updatedPeminjaman, err := u.transitionStatus(peminjamans, peminjaman.Status, &adminID, ActorRoleAdmin, peminjaman.Reason)
 	if err != nil {
 		return statusResponse, err
 	}
@@ -552,7 +684,84 @@ func (u *peminjamanUsecase) UpdatePeminjaman(id uint, peminjaman dtos.Peminjaman
 
 
 
-func (u *peminjamanUsecase) AdminUpdatePeminjaman(id uint, peminjaman dtos.PeminjamanInput) (dtos.PeminjamanResponse, error) {
+// CancelPeminjaman membatalkan peminjaman milik user selama belum melewati batas cutoff.
+// Slot otomatis terbebas karena status cancelled tidak dihitung saat cek bentrok, lalu admin diberi notifikasi.
+func (u *peminjamanUsecase) CancelPeminjaman(userID, id uint, input dtos.PeminjamanCancelInput) (dtos.PeminjamanCancelResponse, error) {
+	var cancelResponse dtos.PeminjamanCancelResponse
+
+	if strings.TrimSpace(input.Reason) == "" {
+		return cancelResponse, errors.New("alasan pembatalan wajib diisi")
+	}
+
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanByID(id, userID)
+	if err != nil {
+		return cancelResponse, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+	}
+
+	startAt, err := peminjamanStartAt(peminjaman)
+	if err != nil {
+		return cancelResponse, err
+	}
+
+	now := time.Now()
+	remaining := startAt.Sub(now)
+	if remaining < u.cancelPolicy.Cutoff {
+		return cancelResponse, fmt.Errorf("%w: pembatalan hanya bisa dilakukan paling lambat %s sebelum peminjaman dimulai", helpers.ErrInvalidStatusTransition, u.cancelPolicy.Cutoff)
+	}
+
+	peminjaman.CancelReason = input.Reason
+	peminjaman.CancelledAt = &now
+	peminjaman.LateCancellation = remaining < u.cancelPolicy.LateWindow
+
+	cancelledPeminjaman, err := u.transitionStatus(peminjaman, models.PeminjamanStatusCancelled, &userID, ActorRoleUser, input.Reason, "cancel_reason", "cancelled_at", "late_cancellation")
+	if err != nil {
+		return cancelResponse, err
+	}
+
+	getUser, err := u.userRepo.UserGetById(userID)
+	if err != nil {
+		return cancelResponse, err
+	}
+	getLab, err := u.labRepo.GetLabByID2(cancelledPeminjaman.LabID)
+	if err != nil {
+		return cancelResponse, err
+	}
+
+	title := "Peminjaman Dibatalkan"
+	if cancelledPeminjaman.LateCancellation {
+		title = "Peminjaman Dibatalkan (Terlambat)"
+	}
+	content := fmt.Sprintf("%s membatalkan peminjaman %s pada %s jam %s-%s. Alasan: %s",
+		getUser.FullName, getLab.Name, helpers.FormatDateToYMD(cancelledPeminjaman.TanggalPeminjaman),
+		cancelledPeminjaman.JamPeminjaman, cancelledPeminjaman.JamSelesai, input.Reason)
+	if err := notifyAdmins(u.templateMessageRepo, u.notificationRepo, u.userRepo, title, content); err != nil {
+		return cancelResponse, err
+	}
+
+	cancelResponse = dtos.PeminjamanCancelResponse{
+		PeminjamanID:     cancelledPeminjaman.ID,
+		Status:           cancelledPeminjaman.Status,
+		CancelReason:     cancelledPeminjaman.CancelReason,
+		CancelledAt:      cancelledPeminjaman.CancelledAt,
+		LateCancellation: cancelledPeminjaman.LateCancellation,
+	}
+
+	return cancelResponse, nil
+}
+
+// peminjamanStartAt menggabungkan tanggal dan jam mulai peminjaman menjadi satu waktu lokal
+func peminjamanStartAt(peminjaman models.Peminjaman) (time.Time, error) {
+	return sessionTime(peminjaman.TanggalPeminjaman, peminjaman.JamPeminjaman)
+}
+
+func sessionTime(tanggal *time.Time, jam string) (time.Time, error) {
+	if tanggal == nil {
+		return time.Time{}, errors.New("tanggal peminjaman kosong")
+	}
+	return time.ParseInLocation("2006-01-02 15:04", helpers.FormatDateToYMD(tanggal)+" "+jam, time.Local)
+}
+
+func (u *peminjamanUsecase) AdminUpdatePeminjaman(id, adminID uint, peminjaman dtos.PeminjamanInput) (dtos.PeminjamanResponse, error) {
 	var peminjamans models.Peminjaman
 	var peminjamanResponse dtos.PeminjamanResponse
 
@@ -566,11 +775,12 @@ func (u *peminjamanUsecase) AdminUpdatePeminjaman(id uint, peminjaman dtos.Pemin
 		return peminjamanResponse, errors.New("failed to update peminjaman")
 	}
 
-	peminjamans.Status = peminjaman.Status
-
-	updatedPeminjaman, err := u.peminjamanRepo.UpdatePeminjaman(peminjamans)
-	if err != nil {
-		return peminjamanResponse, err
+	updatedPeminjaman := peminjamans
+	if peminjaman.Status != peminjamans.Status {
+		updatedPeminjaman, err = u.transitionStatus(peminjamans, peminjaman.Status, &adminID, ActorRoleAdmin, peminjaman.Reason)
+		if err != nil {
+			return peminjamanResponse, err
+		}
 	}
 
 
@@ -627,9 +837,12 @@ func (u *peminjamanUsecase) AdminUpdatePeminjaman(id uint, peminjaman dtos.Pemin
 		PeminjamanID:          int(updatedPeminjaman.ID),
 		TanggalPeminjaman:     helpers.FormatDateToYMD(updatedPeminjaman.TanggalPeminjaman),
 		JamPeminjaman:         updatedPeminjaman.JamPeminjaman,
+		JamSelesai:            updatedPeminjaman.JamSelesai,
+		LabSlotID:             updatedPeminjaman.LabSlotID,
 		SuratRekomendasiImage: suratRekomendasiImageResponses,
 		Description:           updatedPeminjaman.Description,
 		Status:                updatedPeminjaman.Status,
+		SeriesID:              updatedPeminjaman.PeminjamanSeriesID,
 		Lab: dtos.LabByIDResponses{
 			LabID:       getLab.ID,
 			Name:        getLab.Name,
@@ -643,4 +856,315 @@ func (u *peminjamanUsecase) AdminUpdatePeminjaman(id uint, peminjaman dtos.Pemin
 	return peminjamanResponse, nil
 }
 
+// maxSeriesOccurrences membatasi jumlah pertemuan dalam satu peminjaman berulang (satu semester)
+const maxSeriesOccurrences = 26
+
+func (u *peminjamanUsecase) CreatePeminjamanSeries(userID uint, PeminjamanInput *dtos.PeminjamanInput) (dtos.PeminjamanSeriesResponse, error) {
+	var seriesResponse dtos.PeminjamanSeriesResponse
+	recurrence := PeminjamanInput.Recurrence
+
+	getUsers, err := u.userRepo.UserGetById(userID)
+	if err != nil {
+		return seriesResponse, errors.New("failed to get user")
+	}
+
+	if err := checkBorrowingStanding(u.damageCaseRepo, u.penaltyRepo, u.penaltyPolicy, getUsers.ID); err != nil {
+		return seriesResponse, err
+	}
+
+	getLabs, err := u.labRepo.GetLabByID(uint(PeminjamanInput.LabID))
+	if err != nil {
+		return seriesResponse, errors.New("failed to get lab")
+	}
+
+	participants, err := resolveParticipants(u.userRepo, getUsers, PeminjamanInput.Participants)
+	if err != nil {
+		return seriesResponse, err
+	}
+	if err := checkLabCapacity(getLabs, participants); err != nil {
+		return seriesResponse, err
+	}
+
+	// Cek apakah tanggal peminjaman pertama valid
+	if PeminjamanInput.TanggalPeminjaman == nil || *PeminjamanInput.TanggalPeminjaman < time.Now().Format("2006-01-02") {
+		return seriesResponse, errors.New("tanggal peminjaman invalid")
+	}
+
+	tanggalMulai, err := time.Parse("2006-01-02", *PeminjamanInput.TanggalPeminjaman)
+	if err != nil {
+		return seriesResponse, errors.New("failed to parse tanggal peminjaman")
+	}
+
+	if tanggalMulai.Before(time.Now()) {
+		return seriesResponse, errors.New("tanggal peminjaman harus setelah tanggal sekarang")
+	}
+
+	// Menentukan jarak antar pertemuan
+	var intervalDays int
+	switch recurrence.Frequency {
+	case "weekly":
+		intervalDays = 7
+	case "biweekly":
+		intervalDays = 14
+	default:
+		return seriesResponse, errors.New("frequency harus weekly atau biweekly")
+	}
+
+	var until *time.Time
+	if recurrence.Until != nil && *recurrence.Until != "" {
+		untilParse, err := time.Parse("2006-01-02", *recurrence.Until)
+		if err != nil {
+			return seriesResponse, errors.New("failed to parse tanggal until")
+		}
+		if untilParse.Before(tanggalMulai) {
+			return seriesResponse, errors.New("tanggal until harus setelah tanggal peminjaman")
+		}
+		until = &untilParse
+	}
+
+	if recurrence.Count < 0 || (until == nil && recurrence.Count == 0) {
+		return seriesResponse, errors.New("until atau count wajib diisi")
+	}
+
+	var dates []time.Time
+	for date := tanggalMulai; ; date = date.AddDate(0, 0, intervalDays) {
+		if until != nil && date.After(*until) {
+			break
+		}
+		if recurrence.Count > 0 && len(dates) >= recurrence.Count {
+			break
+		}
+		if len(dates) >= maxSeriesOccurrences {
+			return seriesResponse, fmt.Errorf("peminjaman berulang maksimal %d pertemuan", maxSeriesOccurrences)
+		}
+		dates = append(dates, date)
+	}
+
+	// Semua pertemuan jatuh pada hari yang sama sehingga cukup memakai slot dari tanggal pertama
+	labSlot, err := resolveLabSlot(u.labSlotRepo, getLabs.ID, tanggalMulai, PeminjamanInput.JamPeminjaman, PeminjamanInput.LabSlotID)
+	if err != nil {
+		return seriesResponse, err
+	}
+
+	for _, suratRekomendasiImage := range PeminjamanInput.SuratRekomendasiImage {
+		if suratRekomendasiImage.SuratRekomendasiImageUrl == "" {
+			return seriesResponse, errors.New("surat rekomendasi image URL is empty")
+		}
+	}
+
+	createdSeries, err := u.peminjamanSeriesRepo.CreatePeminjamanSeries(models.PeminjamanSeries{
+		UserID:        getUsers.ID,
+		LabID:         getLabs.ID,
+		Frequency:     recurrence.Frequency,
+		TanggalMulai:  &tanggalMulai,
+		Until:         until,
+		Count:         recurrence.Count,
+		JamPeminjaman: labSlot.JamMulai,
+		JamSelesai:    labSlot.JamSelesai,
+		Description:   PeminjamanInput.Description,
+	})
+	if err != nil {
+		return seriesResponse, errors.New("failed to create peminjaman series")
+	}
+
+	approvals, err := approvalChainForLab(u.approvalRepo, getLabs.ID)
+	if err != nil {
+		return seriesResponse, errors.New("failed to get approval chain")
+	}
+
+	// Setiap pertemuan dibuat sebagai peminjaman tersendiri, pertemuan yang bentrok dicatat di laporan
+	var (
+		occurrences  []dtos.PeminjamanSeriesOccurrence
+		createdCount int
+		firstErr     error
+	)
+	for _, date := range dates {
+		tanggalPeminjaman := date
+		if err := checkLabClosure(u.labClosureRepo, getLabs.ID, tanggalPeminjaman, labSlot.JamMulai, labSlot.JamSelesai); err != nil {
+			occurrences = append(occurrences, dtos.PeminjamanSeriesOccurrence{
+				TanggalPeminjaman: helpers.FormatDateToYMD(&tanggalPeminjaman),
+				Status:            "closed",
+				Message:           err.Error(),
+			})
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		createdPeminjaman, err := u.peminjamanRepo.CreatePeminjamanIfAvailable(models.Peminjaman{
+			UserID:             getUsers.ID,
+			LabID:              getLabs.ID,
+			TanggalPeminjaman:  &tanggalPeminjaman,
+			JamPeminjaman:      labSlot.JamMulai,
+			JamSelesai:         labSlot.JamSelesai,
+			LabSlotID:          &labSlot.ID,
+			Description:        PeminjamanInput.Description,
+			Status:             models.PeminjamanStatusRequest,
+			PeminjamanSeriesID: &createdSeries.ID,
+			Approvals:          append([]models.PeminjamanApproval(nil), approvals...),
+			Participants:       append([]models.PeminjamanParticipant(nil), participants...),
+		}, bookingQuotaCheck(getUsers, getLabs.ID, tanggalPeminjaman, labSlot.JamMulai, labSlot.JamSelesai, nil))
+		if err != nil {
+			occurrence := dtos.PeminjamanSeriesOccurrence{
+				TanggalPeminjaman: helpers.FormatDateToYMD(&tanggalPeminjaman),
+				Status:            "failed",
+				Message:           "failed to create peminjaman",
+			}
+			switch {
+			case errors.Is(err, helpers.ErrSlotConflict):
+				occurrence.Status = "conflict"
+				occurrence.Message = err.Error()
+			case errors.Is(err, helpers.ErrQuotaExceeded):
+				occurrence.Status = "quota_exceeded"
+				occurrence.Message = err.Error()
+			}
+			occurrences = append(occurrences, occurrence)
+			if firstErr == nil {
+				firstErr = err
+				if occurrence.Status == "failed" {
+					firstErr = errors.New(occurrence.Message)
+				}
+			}
+			continue
+		}
+
+		for _, suratRekomendasiImage := range PeminjamanInput.SuratRekomendasiImage {
+			peminjamanImage := models.SuratRekomendasiImage{
+				PeminjamanID:             createdPeminjaman.ID,
+				SuratRekomendasiImageUrl: suratRekomendasiImage.SuratRekomendasiImageUrl,
+			}
+			if _, err := u.suratRekomendasiImageRepo.CreateSuratRekomendasiImage(peminjamanImage); err != nil {
+				return seriesResponse, errors.New("failed to create surat rekomendasi image")
+			}
+		}
+
+		occurrences = append(occurrences, dtos.PeminjamanSeriesOccurrence{
+			PeminjamanID:      createdPeminjaman.ID,
+			TanggalPeminjaman: helpers.FormatDateToYMD(createdPeminjaman.TanggalPeminjaman),
+			Status:            createdPeminjaman.Status,
+		})
+		createdCount++
+	}
+
+	// Series tanpa satu pun pertemuan dihapus lagi, alasan pertemuan pertama yang gagal dikembalikan
+	if createdCount == 0 {
+		if err := u.peminjamanSeriesRepo.DeletePeminjamanSeries(createdSeries.ID); err != nil {
+			return seriesResponse, errors.New("failed to delete empty peminjaman series")
+		}
+		return seriesResponse, fmt.Errorf("tidak ada pertemuan yang berhasil dibuat: %w", firstErr)
+	}
+
+	return u.toPeminjamanSeriesResponse(createdSeries, occurrences)
+}
+
+func (u *peminjamanUsecase) GetPeminjamanSeriesByID(userID, seriesID uint) (dtos.PeminjamanSeriesResponse, error) {
+	var seriesResponse dtos.PeminjamanSeriesResponse
+
+	series, err := u.peminjamanSeriesRepo.GetPeminjamanSeriesByID(seriesID, userID)
+	if err != nil {
+		return seriesResponse, errors.New("peminjaman series tidak ditemukan, pastikan ID benar")
+	}
+
+	peminjamans, err := u.peminjamanSeriesRepo.GetPeminjamansBySeriesID(series.ID)
+	if err != nil {
+		return seriesResponse, err
+	}
+
+	var occurrences []dtos.PeminjamanSeriesOccurrence
+	for _, peminjaman := range peminjamans {
+		occurrences = append(occurrences, dtos.PeminjamanSeriesOccurrence{
+			PeminjamanID:      peminjaman.ID,
+			TanggalPeminjaman: helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
+			Status:            peminjaman.Status,
+		})
+	}
+
+	return u.toPeminjamanSeriesResponse(series, occurrences)
+}
+
+// UpdatePeminjamanSeries menerapkan status yang sama ke semua pertemuan yang masih berstatus request,
+// pertemuan yang sudah diproses satu per satu tidak diubah
+func (u *peminjamanUsecase) UpdatePeminjamanSeries(seriesID, adminID uint, input dtos.PeminjamanSeriesStatusInput) (dtos.PeminjamanSeriesResponse, error) {
+	var seriesResponse dtos.PeminjamanSeriesResponse
+
+	if input.Status == "" {
+		return seriesResponse, errors.New("status wajib diisi")
+	}
+
+	series, err := u.peminjamanSeriesRepo.GetPeminjamanSeriesByID(seriesID, 0)
+	if err != nil {
+		return seriesResponse, errors.New("peminjaman series tidak ditemukan, pastikan ID benar")
+	}
+
+	peminjamans, err := u.peminjamanSeriesRepo.GetPeminjamansBySeriesID(series.ID)
+	if err != nil {
+		return seriesResponse, err
+	}
+
+	var occurrences []dtos.PeminjamanSeriesOccurrence
+	for _, peminjaman := range peminjamans {
+		occurrence := dtos.PeminjamanSeriesOccurrence{
+			PeminjamanID:      peminjaman.ID,
+			TanggalPeminjaman: helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman),
+			Status:            peminjaman.Status,
+		}
+
+		if peminjaman.Status == models.PeminjamanStatusRequest {
+			statusResponse, err := u.UpdatePeminjaman(peminjaman.ID, adminID, dtos.PeminjamanInput{Status: input.Status, Reason: input.Reason})
+			if err != nil {
+				occurrence.Message = err.Error()
+			} else {
+				occurrence.Status = statusResponse.Status
+			}
+		}
+
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return u.toPeminjamanSeriesResponse(series, occurrences)
+}
+
+func (u *peminjamanUsecase) toPeminjamanSeriesResponse(series models.PeminjamanSeries, occurrences []dtos.PeminjamanSeriesOccurrence) (dtos.PeminjamanSeriesResponse, error) {
+	var seriesResponse dtos.PeminjamanSeriesResponse
+
+	getLab, err := u.labRepo.GetLabByID2(series.LabID)
+	if err != nil {
+		return seriesResponse, errors.New("failed to get lab")
+	}
+
+	getLabImage, err := u.labImageRepo.GetAllLabImageByID(series.LabID)
+	if err != nil {
+		return seriesResponse, errors.New("failed to get lab image")
+	}
+
+	var labImageResponses []dtos.LabImageResponse
+	for _, labImage := range getLabImage {
+		labImageResponse := dtos.LabImageResponse{
+			LabID:    labImage.LabID,
+			ImageUrl: labImage.ImageUrl,
+		}
+		labImageResponses = append(labImageResponses, labImageResponse)
+	}
 
+	seriesResponse = dtos.PeminjamanSeriesResponse{
+		SeriesID:      series.ID,
+		Frequency:     series.Frequency,
+		TanggalMulai:  helpers.FormatDateToYMD(series.TanggalMulai),
+		Until:         helpers.FormatDateToYMD(series.Until),
+		Count:         series.Count,
+		JamPeminjaman: series.JamPeminjaman,
+		JamSelesai:    series.JamSelesai,
+		Description:   series.Description,
+		Lab: dtos.LabByIDResponses{
+			LabID:       getLab.ID,
+			Name:        getLab.Name,
+			LabImage:    labImageResponses,
+			Description: getLab.Description,
+		},
+		Occurrences: occurrences,
+		CreatedAt:   series.CreatedAt,
+		UpdatedAt:   series.UpdatedAt,
+	}
+
+	return seriesResponse, nil
+}
@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+)
+
+const (
+	BulkModeAtomic  = "atomic"
+	BulkModePerItem = "per_item"
+
+	BulkResultUpdated  = "updated"
+	BulkResultFailed   = "failed"
+	BulkResultConflict = "conflict"
+	BulkResultSkipped  = "skipped"
+)
+
+// maxBulkItems membatasi jumlah peminjaman dalam satu permintaan bulk
+const maxBulkItems = 500
+
+// BulkUpdatePeminjamanStatus memindahkan status banyak peminjaman sekaligus. Peminjaman yang akan
+// diterima dicek bentrok dengan peminjaman lain di permintaan yang sama, item yang lebih dulu
+// disebut yang diprioritaskan.
+func (u *peminjamanUsecase) BulkUpdatePeminjamanStatus(adminID uint, input dtos.PeminjamanBulkStatusInput) (dtos.PeminjamanBulkStatusResponse, error) {
+	bulkResponse := dtos.PeminjamanBulkStatusResponse{Mode: input.Mode, Status: input.Status, Results: []dtos.PeminjamanBulkStatusResult{}}
+	if bulkResponse.Mode == "" {
+		bulkResponse.Mode = BulkModePerItem
+	}
+	if bulkResponse.Mode != BulkModeAtomic && bulkResponse.Mode != BulkModePerItem {
+		return bulkResponse, errors.New("mode harus atomic atau per_item")
+	}
+	if input.Status == "" {
+		return bulkResponse, errors.New("status wajib diisi")
+	}
+
+	// ID ganda hanya diproses sekali dengan urutan kemunculan pertama
+	var ids []uint
+	seen := map[uint]bool{}
+	for _, id := range input.IDs {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return bulkResponse, errors.New("ids wajib diisi")
+	}
+	if len(ids) > maxBulkItems {
+		return bulkResponse, fmt.Errorf("maksimal %d peminjaman dalam satu permintaan", maxBulkItems)
+	}
+
+	peminjamans, err := u.peminjamanRepo.GetPeminjamansByIDs(ids)
+	if err != nil {
+		return bulkResponse, errors.New("failed to get peminjaman")
+	}
+	peminjamanByID := map[uint]models.Peminjaman{}
+	for _, peminjaman := range peminjamans {
+		peminjamanByID[peminjaman.ID] = peminjaman
+	}
+
+	// Validasi semua item terlebih dahulu agar mode atomic bisa menolak tanpa mengubah apa pun
+	var (
+		valid    []models.Peminjaman
+		accepted []models.Peminjaman
+	)
+	for _, id := range ids {
+		result := dtos.PeminjamanBulkStatusResult{PeminjamanID: id, Status: input.Status}
+
+		peminjaman, ok := peminjamanByID[id]
+		if !ok {
+			result.Result = BulkResultFailed
+			result.Message = "peminjaman tidak ditemukan"
+			bulkResponse.Results = append(bulkResponse.Results, result)
+			continue
+		}
+		result.FromStatus = peminjaman.Status
+
+		if err := u.validateTransition(peminjaman, input.Status, ActorRoleAdmin); err != nil {
+			result.Result = BulkResultFailed
+			result.Message = err.Error()
+			bulkResponse.Results = append(bulkResponse.Results, result)
+			continue
+		}
+
+		if input.Status == models.PeminjamanStatusAccept {
+			if conflictID, conflict := bulkSlotConflict(accepted, peminjaman); conflict {
+				result.Result = BulkResultConflict
+				result.Message = fmt.Sprintf("%s dengan peminjaman %d pada permintaan yang sama", helpers.ErrSlotConflict.Error(), conflictID)
+				bulkResponse.Results = append(bulkResponse.Results, result)
+				continue
+			}
+			accepted = append(accepted, peminjaman)
+		}
+
+		valid = append(valid, peminjaman)
+		bulkResponse.Results = append(bulkResponse.Results, result)
+	}
+
+	if bulkResponse.Mode == BulkModeAtomic {
+		u.applyBulkAtomic(adminID, input, valid, &bulkResponse)
+	} else {
+		u.applyBulkPerItem(adminID, input, valid, &bulkResponse)
+	}
+
+	bulkResponse.Total = len(bulkResponse.Results)
+	for _, result := range bulkResponse.Results {
+		if result.Result == BulkResultUpdated {
+			bulkResponse.Succeeded++
+		} else {
+			bulkResponse.Failed++
+		}
+	}
+
+	return bulkResponse, nil
+}
+
+func (u *peminjamanUsecase) applyBulkPerItem(adminID uint, input dtos.PeminjamanBulkStatusInput, valid []models.Peminjaman, bulkResponse *dtos.PeminjamanBulkStatusResponse) {
+	for _, peminjaman := range valid {
+		result := findBulkResult(bulkResponse, peminjaman.ID)
+		if _, err := u.transitionStatus(peminjaman, input.Status, &adminID, ActorRoleAdmin, input.Reason); err != nil {
+			result.Result = BulkResultFailed
+			result.Message = err.Error()
+			continue
+		}
+		result.Result = BulkResultUpdated
+	}
+}
+
+// applyBulkAtomic menyimpan semua perubahan dalam satu transaksi, tidak ada yang disimpan jika
+// validasi salah satu item gagal
+func (u *peminjamanUsecase) applyBulkAtomic(adminID uint, input dtos.PeminjamanBulkStatusInput, valid []models.Peminjaman, bulkResponse *dtos.PeminjamanBulkStatusResponse) {
+	skipAll := func(message string) {
+		for i := range bulkResponse.Results {
+			if bulkResponse.Results[i].Result == "" {
+				bulkResponse.Results[i].Result = BulkResultSkipped
+				bulkResponse.Results[i].Message = message
+			}
+		}
+	}
+
+	if len(valid) != len(bulkResponse.Results) {
+		skipAll("tidak diproses karena ada peminjaman lain yang gagal divalidasi")
+		return
+	}
+
+	statusLogs := make([]models.PeminjamanStatusLog, 0, len(valid))
+	for _, peminjaman := range valid {
+		statusLogs = append(statusLogs, models.PeminjamanStatusLog{
+			ActorID:    &adminID,
+			ActorRole:  ActorRoleAdmin,
+			FromStatus: peminjaman.Status,
+			ToStatus:   input.Status,
+			Reason:     input.Reason,
+		})
+	}
+
+	updatedPeminjamans, err := u.peminjamanRepo.UpdatePeminjamanStatuses(valid, statusLogs)
+	if err != nil {
+		skipAll("transaksi dibatalkan: " + err.Error())
+		return
+	}
+
+	for _, updatedPeminjaman := range updatedPeminjamans {
+		findBulkResult(bulkResponse, updatedPeminjaman.ID).Result = BulkResultUpdated
+		u.afterTransition(updatedPeminjaman, input.Status)
+	}
+}
+
+// bulkSlotConflict mencari peminjaman yang sudah akan diterima pada lab, tanggal, dan jam yang beririsan
+func bulkSlotConflict(accepted []models.Peminjaman, peminjaman models.Peminjaman) (uint, bool) {
+	for _, other := range accepted {
+		if other.LabID != peminjaman.LabID ||
+			helpers.FormatDateToYMD(other.TanggalPeminjaman) != helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman) {
+			continue
+		}
+		if other.JamPeminjaman < peminjaman.JamSelesai && other.JamSelesai > peminjaman.JamPeminjaman {
+			return other.ID, true
+		}
+	}
+	return 0, false
+}
+
+func findBulkResult(bulkResponse *dtos.PeminjamanBulkStatusResponse, id uint) *dtos.PeminjamanBulkStatusResult {
+	for i := range bulkResponse.Results {
+		if bulkResponse.Results[i].PeminjamanID == id {
+			return &bulkResponse.Results[i]
+		}
+	}
+	return &dtos.PeminjamanBulkStatusResult{}
+}
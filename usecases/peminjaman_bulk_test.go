@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+func TestBulkSlotConflict(t *testing.T) {
+	tanggal := time.Date(2030, 1, 10, 0, 0, 0, 0, time.Local)
+	besok := tanggal.AddDate(0, 0, 1)
+	newPeminjaman := func(id, labID uint, date *time.Time, jamMulai, jamSelesai string) models.Peminjaman {
+		return models.Peminjaman{LabID: labID, TanggalPeminjaman: date, JamPeminjaman: jamMulai, JamSelesai: jamSelesai, Model: gorm.Model{ID: id}}
+	}
+
+	accepted := []models.Peminjaman{
+		newPeminjaman(1, 1, &tanggal, "08:00", "10:00"),
+		newPeminjaman(2, 2, &tanggal, "10:00", "12:00"),
+	}
+
+	tests := []struct {
+		name       string
+		peminjaman models.Peminjaman
+		wantID     uint
+		wantFound  bool
+	}{
+		{name: "beririsan di lab yang sama", peminjaman: newPeminjaman(3, 1, &tanggal, "09:00", "11:00"), wantID: 1, wantFound: true},
+		{name: "berada di dalam sesi lain", peminjaman: newPeminjaman(3, 2, &tanggal, "10:30", "11:00"), wantID: 2, wantFound: true},
+		{name: "bersebelahan tidak bentrok", peminjaman: newPeminjaman(3, 1, &tanggal, "10:00", "12:00")},
+		{name: "lab lain", peminjaman: newPeminjaman(3, 3, &tanggal, "08:00", "10:00")},
+		{name: "tanggal lain", peminjaman: newPeminjaman(3, 1, &besok, "08:00", "10:00")},
+	}
+
+	for _, test := range tests {
+		id, found := bulkSlotConflict(accepted, test.peminjaman)
+		if id != test.wantID || found != test.wantFound {
+			t.Errorf("%s: didapat (%d, %v), seharusnya (%d, %v)", test.name, id, found, test.wantID, test.wantFound)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	CheckinKindPeminjaman = "peminjaman"
+	CheckinKindJadwal     = "jadwal"
+
+	checkinTokenSubject = "checkin"
+	checkinQRCodeSize   = 256
+)
+
+// CheckinPolicy mengatur token check-in. Token berlaku mulai EarlyWindow sebelum sesi dimulai
+// sampai GracePeriod setelah sesi selesai, dan ditandatangani dengan Secret.
+type CheckinPolicy struct {
+	Secret      []byte
+	EarlyWindow time.Duration
+	GracePeriod time.Duration
+}
+
+type checkinClaims struct {
+	Kind string `json:"kind"`
+	ID   uint   `json:"id"`
+	jwt.RegisteredClaims
+}
+
+// GetPeminjamanQRCode membuat QR berisi token check-in untuk peminjaman yang sudah diterima.
+// userID 0 berarti admin yang boleh mengambil QR peminjaman siapa saja.
+func (u *peminjamanUsecase) GetPeminjamanQRCode(userID, id uint) ([]byte, error) {
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanByID(id, userID)
+	if err != nil {
+		return nil, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+	}
+	if peminjaman.Status != models.PeminjamanStatusAccept && peminjaman.Status != models.PeminjamanStatusInUse {
+		return nil, fmt.Errorf("%w: QR check-in hanya tersedia untuk peminjaman yang diterima", helpers.ErrInvalidStatusTransition)
+	}
+
+	return u.checkinQRCode(CheckinKindPeminjaman, peminjaman.ID, peminjaman.TanggalPeminjaman, peminjaman.JamPeminjaman, peminjaman.JamSelesai)
+}
+
+// GetJadwalQRCode membuat QR check-in untuk jadwal. Jadwal yang berasal dari peminjaman memakai
+// token peminjamannya agar penggunaan tercatat di kedua data.
+func (u *peminjamanUsecase) GetJadwalQRCode(userID, id uint) ([]byte, error) {
+	jadwal, err := u.jadwalRepo.GetJadwalByID(id)
+	if err != nil {
+		return nil, errors.New("jadwal tidak ditemukan, pastikan ID benar")
+	}
+	if userID != 0 && (jadwal.UserID == nil || *jadwal.UserID != userID) {
+		return nil, errors.New("jadwal tidak ditemukan, pastikan ID benar")
+	}
+	if jadwal.PeminjamanID != nil {
+		return u.GetPeminjamanQRCode(userID, *jadwal.PeminjamanID)
+	}
+	if jadwal.Status != models.JadwalStatusNotUsed && jadwal.Status != models.JadwalStatusInUsed {
+		return nil, fmt.Errorf("%w: QR check-in hanya tersedia untuk jadwal yang belum selesai", helpers.ErrInvalidStatusTransition)
+	}
+
+	return u.checkinQRCode(CheckinKindJadwal, jadwal.ID, jadwal.TanggalJadwal, jadwal.WaktuJadwal, jadwal.WaktuSelesai)
+}
+
+func (u *peminjamanUsecase) checkinQRCode(kind string, id uint, tanggal *time.Time, jamMulai, jamSelesai string) ([]byte, error) {
+	startAt, err := sessionTime(tanggal, jamMulai)
+	if err != nil {
+		return nil, err
+	}
+	endAt, err := sessionTime(tanggal, jamSelesai)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := endAt.Add(u.checkinPolicy.GracePeriod)
+	if !expiresAt.After(time.Now()) {
+		return nil, errors.New("sesi sudah berakhir")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, checkinClaims{
+		Kind: kind,
+		ID:   id,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   checkinTokenSubject,
+			NotBefore: jwt.NewNumericDate(startAt.Add(-u.checkinPolicy.EarlyWindow)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signed, err := token.SignedString(u.checkinPolicy.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.Encode(signed, qrcode.Medium, checkinQRCodeSize)
+}
+
+func (u *peminjamanUsecase) parseCheckinToken(tokenString string) (checkinClaims, error) {
+	var claims checkinClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return u.checkinPolicy.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithSubject(checkinTokenSubject), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return claims, helpers.ErrInvalidCheckinToken
+	}
+	return claims, nil
+}
+
+// Checkin mencatat waktu mulai pemakaian lab dari token QR. Endpoint ini bisa dipanggil oleh
+// pemilik peminjaman maupun kiosk/asisten lab yang memindai QR tersebut.
+func (u *peminjamanUsecase) Checkin(actorID uint, role string, input dtos.CheckinInput) (dtos.CheckinResponse, error) {
+	claims, err := u.parseCheckinToken(input.Token)
+	if err != nil {
+		return dtos.CheckinResponse{}, err
+	}
+
+	now := time.Now()
+	if claims.Kind == CheckinKindJadwal {
+		return u.checkinJadwal(claims.ID, now)
+	}
+
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanID(claims.ID)
+	if err != nil {
+		return dtos.CheckinResponse{}, errors.New("peminjaman tidak ditemukan")
+	}
+
+	peminjaman.ActualStartAt = &now
+	peminjaman.CheckedInByID = &actorID
+	updatedPeminjaman, err := u.transitionStatus(peminjaman, models.PeminjamanStatusInUse, &actorID, checkinActorRole(role), "check-in", "actual_start_at", "checked_in_by_id")
+	if err != nil {
+		return dtos.CheckinResponse{}, err
+	}
+
+	return toPeminjamanCheckinResponse(updatedPeminjaman), nil
+}
+
+// Checkout mencatat waktu selesai pemakaian lab dan menutup peminjaman
+func (u *peminjamanUsecase) Checkout(actorID uint, role string, input dtos.CheckinInput) (dtos.CheckinResponse, error) {
+	claims, err := u.parseCheckinToken(input.Token)
+	if err != nil {
+		return dtos.CheckinResponse{}, err
+	}
+
+	now := time.Now()
+	if claims.Kind == CheckinKindJadwal {
+		return u.checkoutJadwal(claims.ID, now)
+	}
+
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanID(claims.ID)
+	if err != nil {
+		return dtos.CheckinResponse{}, errors.New("peminjaman tidak ditemukan")
+	}
+
+	peminjaman.ActualEndAt = &now
+	peminjaman.CheckedOutByID = &actorID
+	updatedPeminjaman, err := u.transitionStatus(peminjaman, models.PeminjamanStatusFinished, &actorID, checkinActorRole(role), "check-out", "actual_end_at", "checked_out_by_id")
+	if err != nil {
+		return dtos.CheckinResponse{}, err
+	}
+
+	return toPeminjamanCheckinResponse(updatedPeminjaman), nil
+}
+
+func (u *peminjamanUsecase) checkinJadwal(id uint, now time.Time) (dtos.CheckinResponse, error) {
+	jadwal, err := u.jadwalRepo.GetJadwalByID(id)
+	if err != nil {
+		return dtos.CheckinResponse{}, errors.New("jadwal tidak ditemukan")
+	}
+	if jadwal.ActualStartAt != nil || (jadwal.Status != models.JadwalStatusNotUsed && jadwal.Status != models.JadwalStatusInUsed) {
+		return dtos.CheckinResponse{}, fmt.Errorf("%w: jadwal sudah check-in atau sudah selesai", helpers.ErrInvalidStatusTransition)
+	}
+
+	jadwal.ActualStartAt = &now
+	jadwal.Status = models.JadwalStatusInUsed
+	updatedJadwal, err := u.jadwalRepo.UpdateJadwal(jadwal)
+	if err != nil {
+		return dtos.CheckinResponse{}, err
+	}
+	return toJadwalCheckinResponse(updatedJadwal), nil
+}
+
+func (u *peminjamanUsecase) checkoutJadwal(id uint, now time.Time) (dtos.CheckinResponse, error) {
+	jadwal, err := u.jadwalRepo.GetJadwalByID(id)
+	if err != nil {
+		return dtos.CheckinResponse{}, errors.New("jadwal tidak ditemukan")
+	}
+	if jadwal.ActualStartAt == nil || jadwal.ActualEndAt != nil {
+		return dtos.CheckinResponse{}, fmt.Errorf("%w: jadwal belum check-in atau sudah check-out", helpers.ErrInvalidStatusTransition)
+	}
+
+	jadwal.ActualEndAt = &now
+	jadwal.Status = models.JadwalStatusFinished
+	updatedJadwal, err := u.jadwalRepo.UpdateJadwal(jadwal)
+	if err != nil {
+		return dtos.CheckinResponse{}, err
+	}
+	return toJadwalCheckinResponse(updatedJadwal), nil
+}
+
+func checkinActorRole(role string) string {
+	if role == models.UserRoleAdmin {
+		return ActorRoleAdmin
+	}
+	return ActorRoleUser
+}
+
+func toPeminjamanCheckinResponse(peminjaman models.Peminjaman) dtos.CheckinResponse {
+	return dtos.CheckinResponse{
+		Kind:          CheckinKindPeminjaman,
+		ID:            peminjaman.ID,
+		Status:        peminjaman.Status,
+		ActualStartAt: peminjaman.ActualStartAt,
+		ActualEndAt:   peminjaman.ActualEndAt,
+	}
+}
+
+func toJadwalCheckinResponse(jadwal models.Jadwal) dtos.CheckinResponse {
+	return dtos.CheckinResponse{
+		Kind:          CheckinKindJadwal,
+		ID:            jadwal.ID,
+		Status:        jadwal.Status,
+		ActualStartAt: jadwal.ActualStartAt,
+		ActualEndAt:   jadwal.ActualEndAt,
+	}
+}
+
+func toPeminjamanUsageResponse(peminjaman models.Peminjaman) *dtos.PeminjamanUsageResponse {
+	if peminjaman.ActualStartAt == nil {
+		return nil
+	}
+
+	usage := &dtos.PeminjamanUsageResponse{
+		ActualStartAt:  peminjaman.ActualStartAt,
+		ActualEndAt:    peminjaman.ActualEndAt,
+		CheckedInByID:  peminjaman.CheckedInByID,
+		CheckedOutByID: peminjaman.CheckedOutByID,
+	}
+	if peminjaman.ActualEndAt != nil {
+		usage.DurationMinutes = int(peminjaman.ActualEndAt.Sub(*peminjaman.ActualStartAt).Minutes())
+	}
+	return usage
+}
@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"errors"
+	"sistem_peminjaman_be/dtos"
+	"strings"
+	"time"
+)
+
+// validatePeminjamanFilter memastikan rentang tanggal dan arah urutan valid sebelum dijalankan di SQL
+func validatePeminjamanFilter(filter dtos.PeminjamanFilter) error {
+	var fromDate, toDate time.Time
+	var err error
+	if filter.From != "" {
+		fromDate, err = time.Parse("2006-01-02", filter.From)
+		if err != nil {
+			return errors.New("tanggal from invalid, gunakan format YYYY-MM-DD")
+		}
+	}
+	if filter.To != "" {
+		toDate, err = time.Parse("2006-01-02", filter.To)
+		if err != nil {
+			return errors.New("tanggal to invalid, gunakan format YYYY-MM-DD")
+		}
+	}
+	if filter.From != "" && filter.To != "" && toDate.Before(fromDate) {
+		return errors.New("tanggal to harus sama atau setelah tanggal from")
+	}
+	if filter.Order != "" && !strings.EqualFold(filter.Order, "asc") && !strings.EqualFold(filter.Order, "desc") {
+		return errors.New("order harus asc atau desc")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"testing"
+
+	"sistem_peminjaman_be/dtos"
+)
+
+func TestValidatePeminjamanFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  dtos.PeminjamanFilter
+		wantErr bool
+	}{
+		{name: "kosong", filter: dtos.PeminjamanFilter{}},
+		{name: "rentang valid", filter: dtos.PeminjamanFilter{From: "2030-01-01", To: "2030-01-31"}},
+		{name: "tanggal sama", filter: dtos.PeminjamanFilter{From: "2030-01-01", To: "2030-01-01"}},
+		{name: "hanya from", filter: dtos.PeminjamanFilter{From: "2030-01-01"}},
+		{name: "order huruf besar", filter: dtos.PeminjamanFilter{Order: "DESC"}},
+		{name: "from salah format", filter: dtos.PeminjamanFilter{From: "01-01-2030"}, wantErr: true},
+		{name: "to salah format", filter: dtos.PeminjamanFilter{To: "2030/01/31"}, wantErr: true},
+		{name: "to sebelum from", filter: dtos.PeminjamanFilter{From: "2030-01-31", To: "2030-01-01"}, wantErr: true},
+		{name: "order tidak dikenal", filter: dtos.PeminjamanFilter{Order: "random"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := validatePeminjamanFilter(test.filter)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: error %v, seharusnya error %v", test.name, err, test.wantErr)
+		}
+	}
+}
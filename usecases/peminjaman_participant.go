@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"strings"
+)
+
+// resolveParticipants mencocokkan peserta ber-NIM dengan user terdaftar, peserta tanpa NIM dicatat
+// sebagai tamu. Peminjam sendiri dan NIM yang sama tidak dicatat dua kali.
+func resolveParticipants(userRepo repositories.UserRepository, booker models.User, inputs []dtos.PeminjamanParticipantInput) ([]models.PeminjamanParticipant, error) {
+	var participants []models.PeminjamanParticipant
+	seen := map[uint]bool{booker.ID: true}
+
+	for _, input := range inputs {
+		nimnip := strings.TrimSpace(input.NIMNIP)
+		name := strings.TrimSpace(input.Name)
+
+		if nimnip == "" {
+			if name == "" {
+				return nil, errors.New("peserta tamu wajib diisi nama")
+			}
+			participants = append(participants, models.PeminjamanParticipant{Name: name})
+			continue
+		}
+
+		user, err := userRepo.UserGetByNIMNIP(nimnip)
+		if err != nil {
+			return nil, fmt.Errorf("peserta dengan NIM %s tidak terdaftar", nimnip)
+		}
+		if seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+
+		userID := user.ID
+		participants = append(participants, models.PeminjamanParticipant{
+			UserID: &userID,
+			NIMNIP: user.NIMNIP,
+			Name:   user.FullName,
+		})
+	}
+
+	return participants, nil
+}
+
+// checkLabCapacity menghitung peminjam ditambah seluruh peserta terhadap kapasitas lab
+func checkLabCapacity(lab models.Lab, participants []models.PeminjamanParticipant) error {
+	attendees := len(participants) + 1
+	if lab.Capacity > 0 && attendees > lab.Capacity {
+		return fmt.Errorf("%w: %d peserta, kapasitas %s %d orang", helpers.ErrLabCapacityExceeded, attendees, lab.Name, lab.Capacity)
+	}
+	return nil
+}
+
+func toPeminjamanParticipantResponses(participants []models.PeminjamanParticipant) []dtos.PeminjamanParticipantResponse {
+	var participantResponses []dtos.PeminjamanParticipantResponse
+	for _, participant := range participants {
+		participantResponses = append(participantResponses, dtos.PeminjamanParticipantResponse{
+			UserID: participant.UserID,
+			NIMNIP: participant.NIMNIP,
+			Name:   participant.Name,
+			Guest:  participant.UserID == nil,
+		})
+	}
+	return participantResponses
+}
+
+// getPeminjamanForMember mengambil peminjaman milik user atau peminjaman kelompok yang mencatat user sebagai peserta
+func (u *peminjamanUsecase) getPeminjamanForMember(id, userID uint) (models.Peminjaman, error) {
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanByID(id, userID)
+	if err == nil || userID == 0 {
+		return peminjaman, err
+	}
+
+	isParticipant, checkErr := u.participantRepo.IsParticipant(id, userID)
+	if checkErr != nil || !isParticipant {
+		return peminjaman, err
+	}
+	return u.peminjamanRepo.GetPeminjamanID(id)
+}
@@ -0,0 +1,313 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RequestReschedule mencatat usulan pindah tanggal/slot dari peminjam. Peminjaman lama tetap berlaku
+// sampai admin menyetujui usulan, dan hanya boleh ada satu usulan pending per peminjaman.
+func (u *peminjamanUsecase) RequestReschedule(userID, id uint, input dtos.PeminjamanRescheduleInput) (dtos.PeminjamanRescheduleResponse, error) {
+	var rescheduleResponse dtos.PeminjamanRescheduleResponse
+
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanByID(id, userID)
+	if err != nil {
+		return rescheduleResponse, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+	}
+	if peminjaman.Status != models.PeminjamanStatusRequest && peminjaman.Status != models.PeminjamanStatusAccept {
+		return rescheduleResponse, fmt.Errorf("%w: hanya peminjaman berstatus request atau accept yang bisa dijadwalkan ulang", helpers.ErrInvalidStatusTransition)
+	}
+
+	pending, err := u.peminjamanRescheduleRepo.CountPendingReschedules(peminjaman.ID)
+	if err != nil {
+		return rescheduleResponse, errors.New("failed to check reschedule")
+	}
+	if pending > 0 {
+		return rescheduleResponse, errors.New("masih ada usulan jadwal ulang yang menunggu keputusan admin")
+	}
+
+	if strings.TrimSpace(input.Reason) == "" {
+		return rescheduleResponse, errors.New("alasan jadwal ulang wajib diisi")
+	}
+
+	if input.TanggalPeminjaman == nil || *input.TanggalPeminjaman < time.Now().Format("2006-01-02") {
+		return rescheduleResponse, errors.New("tanggal peminjaman invalid")
+	}
+	tanggalPeminjaman, err := time.Parse("2006-01-02", *input.TanggalPeminjaman)
+	if err != nil {
+		return rescheduleResponse, errors.New("failed to parse tanggal peminjaman")
+	}
+
+	labSlot, err := resolveLabSlot(u.labSlotRepo, peminjaman.LabID, tanggalPeminjaman, input.JamPeminjaman, input.LabSlotID)
+	if err != nil {
+		return rescheduleResponse, err
+	}
+
+	startAt, err := sessionTime(&tanggalPeminjaman, labSlot.JamMulai)
+	if err != nil || !startAt.After(time.Now()) {
+		return rescheduleResponse, errors.New("tanggal peminjaman harus setelah tanggal sekarang")
+	}
+	if helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman) == *input.TanggalPeminjaman && peminjaman.JamPeminjaman == labSlot.JamMulai && peminjaman.JamSelesai == labSlot.JamSelesai {
+		return rescheduleResponse, errors.New("tanggal dan slot usulan sama dengan peminjaman saat ini")
+	}
+
+	if err := checkLabClosure(u.labClosureRepo, peminjaman.LabID, tanggalPeminjaman, labSlot.JamMulai, labSlot.JamSelesai); err != nil {
+		return rescheduleResponse, err
+	}
+
+	// Peminjaman ini sendiri tidak dihitung agar usulan boleh beririsan dengan slot lamanya
+	available, err := u.peminjamanRepo.IsSlotAvailable(peminjaman.LabID, tanggalPeminjaman, labSlot.JamMulai, labSlot.JamSelesai, peminjaman.ID)
+	if err != nil {
+		return rescheduleResponse, err
+	}
+	if !available {
+		return rescheduleResponse, helpers.ErrSlotConflict
+	}
+
+	createdReschedule, err := u.peminjamanRescheduleRepo.CreateReschedule(models.PeminjamanReschedule{
+		PeminjamanID:      peminjaman.ID,
+		RequestedByID:     userID,
+		FromTanggal:       peminjaman.TanggalPeminjaman,
+		FromJamPeminjaman: peminjaman.JamPeminjaman,
+		FromJamSelesai:    peminjaman.JamSelesai,
+		FromLabSlotID:     peminjaman.LabSlotID,
+		TanggalPeminjaman: &tanggalPeminjaman,
+		JamPeminjaman:     labSlot.JamMulai,
+		JamSelesai:        labSlot.JamSelesai,
+		LabSlotID:         &labSlot.ID,
+		Reason:            input.Reason,
+		Status:            models.RescheduleStatusPending,
+	})
+	if err != nil {
+		return rescheduleResponse, errors.New("failed to create reschedule")
+	}
+
+	content := fmt.Sprintf("Peminjaman #%d mengajukan jadwal ulang dari %s %s ke %s %s: %s", peminjaman.ID, helpers.FormatDateToYMD(peminjaman.TanggalPeminjaman), peminjaman.JamPeminjaman, *input.TanggalPeminjaman, labSlot.JamMulai, input.Reason)
+	if err := notifyAdmins(u.templateMessageRepo, u.notificationRepo, u.userRepo, "Usulan Jadwal Ulang Peminjaman", content); err != nil {
+		log.Printf("gagal mengirim notifikasi jadwal ulang peminjaman %d: %v", peminjaman.ID, err)
+	}
+
+	return toPeminjamanRescheduleResponse(createdReschedule), nil
+}
+
+func (u *peminjamanUsecase) GetReschedules(page, limit int, status string) ([]dtos.PeminjamanRescheduleResponse, int, error) {
+	rescheduleResponses := []dtos.PeminjamanRescheduleResponse{}
+
+	reschedules, count, err := u.peminjamanRescheduleRepo.GetReschedules(page, limit, status)
+	if err != nil {
+		return rescheduleResponses, 0, err
+	}
+
+	for _, reschedule := range reschedules {
+		rescheduleResponses = append(rescheduleResponses, toPeminjamanRescheduleResponse(reschedule))
+	}
+
+	return rescheduleResponses, count, nil
+}
+
+// DecideReschedule menyetujui atau menolak usulan jadwal ulang. Untuk lab dengan rantai persetujuan, usulan
+// yang disetujui admin diteruskan ke rantai tersebut dan peminjaman tetap berlaku pada jadwal lamanya sampai
+// tahap terakhir menyetujui. Tanpa rantai, peminjaman langsung dipindah lewat applyReschedule.
+func (u *peminjamanUsecase) DecideReschedule(adminID, id uint, input dtos.PeminjamanRescheduleDecisionInput) (dtos.PeminjamanRescheduleResponse, error) {
+	var rescheduleResponse dtos.PeminjamanRescheduleResponse
+
+	reschedule, err := u.peminjamanRescheduleRepo.GetRescheduleByID(id)
+	if err != nil {
+		return rescheduleResponse, errors.New("usulan jadwal ulang tidak ditemukan, pastikan ID benar")
+	}
+	if reschedule.Status != models.RescheduleStatusPending {
+		return rescheduleResponse, fmt.Errorf("%w: usulan jadwal ulang sudah diputuskan", helpers.ErrInvalidStatusTransition)
+	}
+
+	var title, content string
+	switch input.Decision {
+	case "approve":
+		if err := u.checkRescheduleTarget(reschedule); err != nil {
+			return rescheduleResponse, err
+		}
+
+		approvals, err := approvalChainForLab(u.approvalRepo, reschedule.Peminjaman.LabID)
+		if err != nil {
+			return rescheduleResponse, errors.New("failed to get approval steps")
+		}
+
+		if len(approvals) > 0 {
+			// Slot dicek lebih awal agar rantai tidak berjalan untuk slot yang sudah terpakai,
+			// pengecekan yang mengikat tetap dijalankan ulang saat tahap terakhir disetujui
+			available, err := u.peminjamanRepo.IsSlotAvailable(reschedule.Peminjaman.LabID, *reschedule.TanggalPeminjaman, reschedule.JamPeminjaman, reschedule.JamSelesai, reschedule.PeminjamanID)
+			if err != nil {
+				return rescheduleResponse, err
+			}
+			if !available {
+				return rescheduleResponse, helpers.ErrSlotConflict
+			}
+
+			if err := u.peminjamanRescheduleRepo.StartRescheduleApproval(reschedule, approvals); err != nil {
+				if errors.Is(err, helpers.ErrInvalidStatusTransition) {
+					return rescheduleResponse, err
+				}
+				return rescheduleResponse, errors.New("failed to start reschedule approval")
+			}
+
+			title = "Jadwal Ulang Menunggu Persetujuan"
+			content = fmt.Sprintf("Usulan jadwal ulang peminjaman #%d ke %s %s-%s diteruskan ke rantai persetujuan lab. Peminjaman tetap pada %s %s sampai semua tahap menyetujui. %s",
+				reschedule.PeminjamanID, helpers.FormatDateToYMD(reschedule.TanggalPeminjaman), reschedule.JamPeminjaman, reschedule.JamSelesai, helpers.FormatDateToYMD(reschedule.FromTanggal), reschedule.FromJamPeminjaman, input.Note)
+		} else {
+			updatedPeminjaman, err := u.applyReschedule(reschedule, adminID, input.Note, nil)
+			if err != nil {
+				return rescheduleResponse, err
+			}
+
+			title = "Jadwal Ulang Disetujui"
+			content = fmt.Sprintf("Peminjaman #%d dipindahkan ke %s %s-%s. %s", updatedPeminjaman.ID, helpers.FormatDateToYMD(updatedPeminjaman.TanggalPeminjaman), updatedPeminjaman.JamPeminjaman, updatedPeminjaman.JamSelesai, input.Note)
+		}
+	case "reject":
+		if err := u.peminjamanRescheduleRepo.RejectReschedule(reschedule, adminID, input.Note, nil); err != nil {
+			if errors.Is(err, helpers.ErrInvalidStatusTransition) {
+				return rescheduleResponse, err
+			}
+			return rescheduleResponse, errors.New("failed to update reschedule")
+		}
+
+		title = "Jadwal Ulang Ditolak"
+		content = fmt.Sprintf("Usulan jadwal ulang peminjaman #%d ditolak, peminjaman tetap pada %s %s. %s", reschedule.PeminjamanID, helpers.FormatDateToYMD(reschedule.FromTanggal), reschedule.FromJamPeminjaman, input.Note)
+	default:
+		return rescheduleResponse, errors.New("decision harus approve atau reject")
+	}
+
+	return u.rescheduleDecided(reschedule, title, content)
+}
+
+// ApplyRescheduleWithApproval menyimpan persetujuan tahap terakhir rantai jadwal ulang bersama perpindahan
+// peminjaman ke tanggal dan slot usulan
+func (u *peminjamanUsecase) ApplyRescheduleWithApproval(reschedule models.PeminjamanReschedule, approval models.PeminjamanApproval) (dtos.PeminjamanRescheduleResponse, error) {
+	if err := u.checkRescheduleTarget(reschedule); err != nil {
+		return dtos.PeminjamanRescheduleResponse{}, err
+	}
+
+	updatedPeminjaman, err := u.applyReschedule(reschedule, *approval.DecidedByID, approval.Comment, &approval)
+	if err != nil {
+		return dtos.PeminjamanRescheduleResponse{}, err
+	}
+
+	content := fmt.Sprintf("Peminjaman #%d dipindahkan ke %s %s-%s setelah disetujui pada tahap %s. %s", updatedPeminjaman.ID, helpers.FormatDateToYMD(updatedPeminjaman.TanggalPeminjaman), updatedPeminjaman.JamPeminjaman, updatedPeminjaman.JamSelesai, approval.Name, approval.Comment)
+	return u.rescheduleDecided(reschedule, "Jadwal Ulang Disetujui", content)
+}
+
+// RejectRescheduleWithApproval menyimpan penolakan salah satu tahap rantai jadwal ulang. Peminjaman tidak
+// pernah dipindahkan selama rantai berjalan sehingga tetap berlaku pada jadwal lamanya.
+func (u *peminjamanUsecase) RejectRescheduleWithApproval(reschedule models.PeminjamanReschedule, approval models.PeminjamanApproval) (dtos.PeminjamanRescheduleResponse, error) {
+	err := u.peminjamanRescheduleRepo.RejectReschedule(reschedule, *approval.DecidedByID, approval.Comment, &approval)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.PeminjamanRescheduleResponse{}, errors.New("tahap persetujuan sudah diputuskan")
+		}
+		if errors.Is(err, helpers.ErrInvalidStatusTransition) {
+			return dtos.PeminjamanRescheduleResponse{}, err
+		}
+		return dtos.PeminjamanRescheduleResponse{}, errors.New("failed to update reschedule")
+	}
+
+	content := fmt.Sprintf("Usulan jadwal ulang peminjaman #%d ditolak pada tahap %s, peminjaman tetap pada %s %s. %s", reschedule.PeminjamanID, approval.Name, helpers.FormatDateToYMD(reschedule.FromTanggal), reschedule.FromJamPeminjaman, approval.Comment)
+	return u.rescheduleDecided(reschedule, "Jadwal Ulang Ditolak", content)
+}
+
+// checkRescheduleTarget memastikan lab tidak tutup dan peserta masih muat pada tanggal dan slot usulan
+func (u *peminjamanUsecase) checkRescheduleTarget(reschedule models.PeminjamanReschedule) error {
+	if err := checkLabClosure(u.labClosureRepo, reschedule.Peminjaman.LabID, *reschedule.TanggalPeminjaman, reschedule.JamPeminjaman, reschedule.JamSelesai); err != nil {
+		return err
+	}
+
+	getLab, err := u.labRepo.GetLabByID(reschedule.Peminjaman.LabID)
+	if err != nil {
+		return errors.New("failed to get lab")
+	}
+	participants, err := u.participantRepo.GetParticipantsByPeminjamanID(reschedule.PeminjamanID)
+	if err != nil {
+		return errors.New("failed to get participants")
+	}
+	return checkLabCapacity(getLab, participants)
+}
+
+// applyReschedule memindahkan peminjaman dengan kuota peminjam dicek ulang untuk tanggal baru, lalu
+// menawarkan slot lamanya ke antrean waitlist
+func (u *peminjamanUsecase) applyReschedule(reschedule models.PeminjamanReschedule, decidedByID uint, note string, approval *models.PeminjamanApproval) (models.Peminjaman, error) {
+	getUser, err := u.userRepo.UserGetById(reschedule.Peminjaman.UserID)
+	if err != nil {
+		return models.Peminjaman{}, errors.New("failed to get user")
+	}
+
+	previous := reschedule.Peminjaman
+	checkQuota := bookingQuotaCheck(getUser, reschedule.Peminjaman.LabID, *reschedule.TanggalPeminjaman, reschedule.JamPeminjaman, reschedule.JamSelesai, &previous)
+	updatedPeminjaman, err := u.peminjamanRescheduleRepo.ApplyReschedule(reschedule, decidedByID, note, approval, checkQuota)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) && approval != nil {
+			return updatedPeminjaman, errors.New("tahap persetujuan sudah diputuskan")
+		}
+		if errors.Is(err, helpers.ErrSlotConflict) || errors.Is(err, helpers.ErrQuotaExceeded) || errors.Is(err, helpers.ErrInvalidStatusTransition) {
+			return updatedPeminjaman, err
+		}
+		return updatedPeminjaman, errors.New("failed to apply reschedule")
+	}
+
+	u.promoteWaitlist(previous)
+	return updatedPeminjaman, nil
+}
+
+// rescheduleDecided mengabari peminjam lalu mengembalikan usulan terbaru
+func (u *peminjamanUsecase) rescheduleDecided(reschedule models.PeminjamanReschedule, title, content string) (dtos.PeminjamanRescheduleResponse, error) {
+	if err := notifyUsers(u.templateMessageRepo, u.notificationRepo, []uint{reschedule.RequestedByID}, title, content); err != nil {
+		log.Printf("gagal mengirim notifikasi jadwal ulang %d: %v", reschedule.ID, err)
+	}
+
+	decidedReschedule, err := u.peminjamanRescheduleRepo.GetRescheduleByID(reschedule.ID)
+	if err != nil {
+		return dtos.PeminjamanRescheduleResponse{}, errors.New("failed to get reschedule")
+	}
+	return toPeminjamanRescheduleResponse(decidedReschedule), nil
+}
+
+func (u *peminjamanUsecase) getRescheduleResponses(peminjamanID uint) ([]dtos.PeminjamanRescheduleResponse, error) {
+	var rescheduleResponses []dtos.PeminjamanRescheduleResponse
+
+	reschedules, err := u.peminjamanRescheduleRepo.GetReschedulesByPeminjamanID(peminjamanID)
+	if err != nil {
+		return rescheduleResponses, err
+	}
+
+	for _, reschedule := range reschedules {
+		rescheduleResponses = append(rescheduleResponses, toPeminjamanRescheduleResponse(reschedule))
+	}
+	return rescheduleResponses, nil
+}
+
+func toPeminjamanRescheduleResponse(reschedule models.PeminjamanReschedule) dtos.PeminjamanRescheduleResponse {
+	return dtos.PeminjamanRescheduleResponse{
+		RescheduleID:      reschedule.ID,
+		PeminjamanID:      reschedule.PeminjamanID,
+		LabID:             reschedule.Peminjaman.LabID,
+		LabName:           reschedule.Peminjaman.Lab.Name,
+		RequestedByID:     reschedule.RequestedByID,
+		FromTanggal:       helpers.FormatDateToYMD(reschedule.FromTanggal),
+		FromJamPeminjaman: reschedule.FromJamPeminjaman,
+		FromJamSelesai:    reschedule.FromJamSelesai,
+		TanggalPeminjaman: helpers.FormatDateToYMD(reschedule.TanggalPeminjaman),
+		JamPeminjaman:     reschedule.JamPeminjaman,
+		JamSelesai:        reschedule.JamSelesai,
+		LabSlotID:         reschedule.LabSlotID,
+		Reason:            reschedule.Reason,
+		Status:            reschedule.Status,
+		DecidedByID:       reschedule.DecidedByID,
+		DecisionNote:      reschedule.DecisionNote,
+		DecidedAt:         reschedule.DecidedAt,
+		CreatedAt:         reschedule.CreatedAt,
+	}
+}
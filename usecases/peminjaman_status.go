@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ActorRoleUser     = "user"
+	ActorRoleAdmin    = "admin"
+	ActorRoleApprover = "approver"
+	ActorRoleSystem   = "system"
+)
+
+// peminjamanTransitions berisi perpindahan status yang diizinkan beserta role yang boleh melakukannya
+var peminjamanTransitions = map[string]map[string][]string{
+	models.PeminjamanStatusRequest: {
+		models.PeminjamanStatusAccept:    {ActorRoleAdmin, ActorRoleApprover},
+		models.PeminjamanStatusReject:    {ActorRoleAdmin, ActorRoleApprover},
+		models.PeminjamanStatusCancelled: {ActorRoleUser, ActorRoleAdmin},
+		models.PeminjamanStatusExpired:   {ActorRoleSystem},
+	},
+	models.PeminjamanStatusAccept: {
+		models.PeminjamanStatusCancelled: {ActorRoleUser, ActorRoleAdmin},
+		models.PeminjamanStatusInUse:     {ActorRoleUser, ActorRoleAdmin, ActorRoleSystem},
+		models.PeminjamanStatusNoShow:    {ActorRoleAdmin, ActorRoleSystem},
+	},
+	models.PeminjamanStatusInUse: {
+		models.PeminjamanStatusFinished: {ActorRoleUser, ActorRoleAdmin, ActorRoleSystem},
+	},
+}
+
+// transitionStatus memvalidasi perpindahan status lalu menyimpannya bersama log status.
+// Perpindahan yang tidak ada di tabel menghasilkan ErrInvalidStatusTransition, sedangkan
+// role yang tidak berhak menghasilkan ErrStatusTransitionForbidden.
+func (u *peminjamanUsecase) transitionStatus(peminjaman models.Peminjaman, toStatus string, actorID *uint, actorRole, reason string, columns ...string) (models.Peminjaman, error) {
+	if err := u.validateTransition(peminjaman, toStatus, actorRole); err != nil {
+		return peminjaman, err
+	}
+
+	statusLog := models.PeminjamanStatusLog{
+		ActorID:    actorID,
+		ActorRole:  actorRole,
+		FromStatus: peminjaman.Status,
+		ToStatus:   toStatus,
+		Reason:     reason,
+	}
+
+	updatedPeminjaman, err := u.peminjamanRepo.UpdatePeminjamanStatus(peminjaman, statusLog, columns...)
+	if err != nil {
+		return updatedPeminjaman, err
+	}
+
+	u.afterTransition(updatedPeminjaman, toStatus)
+	return updatedPeminjaman, nil
+}
+
+func (u *peminjamanUsecase) validateTransition(peminjaman models.Peminjaman, toStatus, actorRole string) error {
+	allowedRoles, ok := peminjamanTransitions[peminjaman.Status][toStatus]
+	if !ok {
+		return helpers.ErrInvalidStatusTransition
+	}
+
+	allowed := false
+	for _, role := range allowedRoles {
+		if role == actorRole {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return helpers.ErrStatusTransitionForbidden
+	}
+
+	// Peminjaman dengan rantai persetujuan hanya boleh diterima lewat tahap persetujuan terakhir
+	if toStatus == models.PeminjamanStatusAccept && actorRole != ActorRoleApprover {
+		approvals, err := u.approvalRepo.GetPeminjamanApprovals(peminjaman.ID)
+		if err != nil {
+			return err
+		}
+		if len(approvals) > 0 {
+			return fmt.Errorf("%w: peminjaman masih menunggu rantai persetujuan", helpers.ErrInvalidStatusTransition)
+		}
+	}
+
+	return nil
+}
+
+// afterTransition menjalankan efek samping setelah status tersimpan. Jadwal lab sudah dibuat atau
+// dibatalkan oleh repository di dalam transaksi perubahan status.
+func (u *peminjamanUsecase) afterTransition(updatedPeminjaman models.Peminjaman, toStatus string) {
+	if toStatus == models.PeminjamanStatusNoShow {
+		issuePenaltyAsync(u.penaltyRepo, u.templateMessageRepo, u.notificationRepo, u.penaltyPolicy, models.Penalty{
+			UserID:       updatedPeminjaman.UserID,
+			Source:       models.PenaltySourceNoShow,
+			Points:       u.penaltyPolicy.NoShowPoints,
+			Reason:       fmt.Sprintf("Tidak check-in pada peminjaman tanggal %s jam %s", helpers.FormatDateToYMD(updatedPeminjaman.TanggalPeminjaman), updatedPeminjaman.JamPeminjaman),
+			PeminjamanID: &updatedPeminjaman.ID,
+		})
+	}
+
+	// Slot yang terbebas langsung ditawarkan ke antrean waitlist
+	if toStatus == models.PeminjamanStatusReject || toStatus == models.PeminjamanStatusCancelled {
+		u.promoteWaitlist(updatedPeminjaman)
+	}
+}
+
+func (u *peminjamanUsecase) getStatusLogResponses(peminjamanID uint) ([]dtos.PeminjamanStatusLogResponse, error) {
+	var statusLogResponses []dtos.PeminjamanStatusLogResponse
+
+	statusLogs, err := u.peminjamanStatusLogRepo.GetStatusLogsByPeminjamanID(peminjamanID)
+	if err != nil {
+		return statusLogResponses, err
+	}
+
+	for _, statusLog := range statusLogs {
+		statusLogResponses = append(statusLogResponses, dtos.PeminjamanStatusLogResponse{
+			ActorID:    statusLog.ActorID,
+			ActorRole:  statusLog.ActorRole,
+			FromStatus: statusLog.FromStatus,
+			ToStatus:   statusLog.ToStatus,
+			Reason:     statusLog.Reason,
+			CreatedAt:  statusLog.CreatedAt,
+		})
+	}
+
+	return statusLogResponses, nil
+}
+
+// TransitionPeminjamanStatus dipakai usecase lain (approval, scheduler) untuk memindahkan status peminjaman
+func (u *peminjamanUsecase) TransitionPeminjamanStatus(id uint, toStatus string, actorID *uint, actorRole, reason string) (dtos.StatusResponse, error) {
+	var statusResponse dtos.StatusResponse
+
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanID(id)
+	if err != nil {
+		return statusResponse, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+	}
+
+	updatedPeminjaman, err := u.transitionStatus(peminjaman, toStatus, actorID, actorRole, reason)
+	if err != nil {
+		return statusResponse, err
+	}
+
+	statusResponse.Status = updatedPeminjaman.Status
+	return statusResponse, nil
+}
+
+// TransitionPeminjamanStatusWithApproval dipakai usecase approval untuk menyimpan keputusan tahap sekaligus
+// memindahkan status peminjaman, keduanya batal bersama jika salah satu gagal
+func (u *peminjamanUsecase) TransitionPeminjamanStatusWithApproval(approval models.PeminjamanApproval, toStatus string, reason string) (dtos.StatusResponse, error) {
+	var statusResponse dtos.StatusResponse
+
+	peminjaman, err := u.peminjamanRepo.GetPeminjamanID(approval.PeminjamanID)
+	if err != nil {
+		return statusResponse, errors.New("peminjaman tidak ditemukan, pastikan ID benar")
+	}
+
+	if err := u.validateTransition(peminjaman, toStatus, ActorRoleApprover); err != nil {
+		return statusResponse, err
+	}
+
+	statusLog := models.PeminjamanStatusLog{
+		ActorID:    approval.DecidedByID,
+		ActorRole:  ActorRoleApprover,
+		FromStatus: peminjaman.Status,
+		ToStatus:   toStatus,
+		Reason:     reason,
+	}
+
+	updatedPeminjaman, err := u.peminjamanRepo.UpdatePeminjamanStatusWithApproval(peminjaman, statusLog, approval)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statusResponse, errors.New("tahap persetujuan sudah diputuskan")
+		}
+		return statusResponse, err
+	}
+
+	u.afterTransition(updatedPeminjaman, toStatus)
+
+	statusResponse.Status = updatedPeminjaman.Status
+	return statusResponse, nil
+}
@@ -0,0 +1,226 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"time"
+)
+
+// JoinWaitlist memasukkan user ke antrean slot lab yang sedang dipakai. Slot yang masih kosong
+// harus diajukan langsung lewat peminjaman biasa.
+func (u *peminjamanUsecase) JoinWaitlist(userID uint, input dtos.PeminjamanWaitlistInput) (dtos.PeminjamanWaitlistResponse, error) {
+	var waitlistResponse dtos.PeminjamanWaitlistResponse
+
+	// User yang sedang dibatasi tidak bisa mengantre karena antreannya akan dipromosikan menjadi peminjaman
+	if err := checkBorrowingStanding(u.damageCaseRepo, u.penaltyRepo, u.penaltyPolicy, userID); err != nil {
+		return waitlistResponse, err
+	}
+
+	getLab, err := u.labRepo.GetLabByID(uint(input.LabID))
+	if err != nil {
+		return waitlistResponse, errors.New("failed to get lab")
+	}
+
+	if input.TanggalPeminjaman == nil || *input.TanggalPeminjaman < time.Now().Format("2006-01-02") {
+		return waitlistResponse, errors.New("tanggal peminjaman invalid")
+	}
+
+	tanggalPeminjaman, err := time.Parse("2006-01-02", *input.TanggalPeminjaman)
+	if err != nil {
+		return waitlistResponse, errors.New("failed to parse tanggal peminjaman")
+	}
+
+	labSlot, err := resolveLabSlot(u.labSlotRepo, getLab.ID, tanggalPeminjaman, input.JamPeminjaman, input.LabSlotID)
+	if err != nil {
+		return waitlistResponse, err
+	}
+
+	if err := checkLabClosure(u.labClosureRepo, getLab.ID, tanggalPeminjaman, labSlot.JamMulai, labSlot.JamSelesai); err != nil {
+		return waitlistResponse, err
+	}
+
+	available, err := u.peminjamanRepo.IsSlotAvailable(getLab.ID, tanggalPeminjaman, labSlot.JamMulai, labSlot.JamSelesai, 0)
+	if err != nil {
+		return waitlistResponse, err
+	}
+	if available {
+		return waitlistResponse, errors.New("slot masih tersedia, silakan ajukan peminjaman langsung")
+	}
+
+	waitingEntries, err := u.peminjamanWaitlistRepo.GetWaitingEntries(getLab.ID, tanggalPeminjaman, labSlot.JamMulai, labSlot.JamSelesai)
+	if err != nil {
+		return waitlistResponse, err
+	}
+	for _, entry := range waitingEntries {
+		if entry.UserID == userID {
+			return waitlistResponse, errors.New("anda sudah masuk antrean untuk slot ini")
+		}
+	}
+
+	createdWaitlist, err := u.peminjamanWaitlistRepo.CreateWaitlist(models.PeminjamanWaitlist{
+		UserID:            userID,
+		LabID:             getLab.ID,
+		TanggalPeminjaman: &tanggalPeminjaman,
+		JamPeminjaman:     labSlot.JamMulai,
+		JamSelesai:        labSlot.JamSelesai,
+		LabSlotID:         &labSlot.ID,
+		Description:       input.Description,
+		Status:            models.WaitlistStatusWaiting,
+	})
+	if err != nil {
+		return waitlistResponse, errors.New("failed to join waitlist")
+	}
+
+	return u.toWaitlistResponse(createdWaitlist)
+}
+
+func (u *peminjamanUsecase) GetWaitlists(userID uint) ([]dtos.PeminjamanWaitlistResponse, error) {
+	var waitlistResponses []dtos.PeminjamanWaitlistResponse
+
+	waitlists, err := u.peminjamanWaitlistRepo.GetWaitlistsByUserID(userID)
+	if err != nil {
+		return waitlistResponses, err
+	}
+
+	for _, waitlist := range waitlists {
+		waitlistResponse, err := u.toWaitlistResponse(waitlist)
+		if err != nil {
+			return waitlistResponses, err
+		}
+		waitlistResponses = append(waitlistResponses, waitlistResponse)
+	}
+
+	return waitlistResponses, nil
+}
+
+func (u *peminjamanUsecase) LeaveWaitlist(userID, id uint) error {
+	waitlist, err := u.peminjamanWaitlistRepo.GetWaitlistByID(id, userID)
+	if err != nil {
+		return errors.New("antrean tidak ditemukan, pastikan ID benar")
+	}
+	if waitlist.Status != models.WaitlistStatusWaiting {
+		return errors.New("antrean sudah tidak menunggu")
+	}
+
+	waitlist.Status = models.WaitlistStatusCancelled
+	_, err = u.peminjamanWaitlistRepo.UpdateWaitlist(waitlist)
+	return err
+}
+
+// promoteWaitlist dipanggil setelah slot peminjaman terbebas (ditolak atau dibatalkan).
+// Antrean paling awal yang masih layak dijadikan peminjaman request lalu user diberi notifikasi,
+// antrean milik user yang sedang dibatasi atau kuotanya habis dilewati dan tetap menunggu.
+// Kegagalan promosi tidak membatalkan perubahan status yang sudah tersimpan, cukup dicatat di log.
+func (u *peminjamanUsecase) promoteWaitlist(freed models.Peminjaman) {
+	if freed.TanggalPeminjaman == nil {
+		return
+	}
+
+	entries, err := u.peminjamanWaitlistRepo.GetWaitingEntries(freed.LabID, *freed.TanggalPeminjaman, freed.JamPeminjaman, freed.JamSelesai)
+	if err != nil {
+		log.Printf("gagal mengambil antrean peminjaman %d: %v", freed.ID, err)
+		return
+	}
+
+	for _, entry := range entries {
+		startAt, err := peminjamanStartAt(models.Peminjaman{TanggalPeminjaman: entry.TanggalPeminjaman, JamPeminjaman: entry.JamPeminjaman})
+		if err != nil || !startAt.After(time.Now()) {
+			entry.Status = models.WaitlistStatusExpired
+			if _, err := u.peminjamanWaitlistRepo.UpdateWaitlist(entry); err != nil {
+				log.Printf("gagal menandai antrean %d kedaluwarsa: %v", entry.ID, err)
+			}
+			continue
+		}
+
+		if err := checkBorrowingStanding(u.damageCaseRepo, u.penaltyRepo, u.penaltyPolicy, entry.UserID); err != nil {
+			log.Printf("antrean %d dilewati: %v", entry.ID, err)
+			continue
+		}
+
+		getUser, err := u.userRepo.UserGetById(entry.UserID)
+		if err != nil {
+			log.Printf("gagal mengambil user antrean %d: %v", entry.ID, err)
+			continue
+		}
+
+		approvals, err := approvalChainForLab(u.approvalRepo, entry.LabID)
+		if err != nil {
+			log.Printf("gagal mengambil rantai persetujuan lab %d: %v", entry.LabID, err)
+			return
+		}
+
+		promoted, err := u.peminjamanWaitlistRepo.PromoteWaitlist(entry, models.Peminjaman{
+			UserID:            entry.UserID,
+			LabID:             entry.LabID,
+			TanggalPeminjaman: entry.TanggalPeminjaman,
+			JamPeminjaman:     entry.JamPeminjaman,
+			JamSelesai:        entry.JamSelesai,
+			LabSlotID:         entry.LabSlotID,
+			Description:       entry.Description,
+			Status:            models.PeminjamanStatusRequest,
+			Approvals:         approvals,
+		}, bookingQuotaCheck(getUser, entry.LabID, *entry.TanggalPeminjaman, entry.JamPeminjaman, entry.JamSelesai, nil))
+		if errors.Is(err, helpers.ErrSlotConflict) {
+			// Slot masih terpakai peminjaman lain, antrean tetap menunggu
+			return
+		}
+		if errors.Is(err, helpers.ErrQuotaExceeded) {
+			log.Printf("antrean %d dilewati: %v", entry.ID, err)
+			continue
+		}
+		if err != nil {
+			log.Printf("gagal mempromosikan antrean %d: %v", entry.ID, err)
+			continue
+		}
+
+		content := fmt.Sprintf("Slot %s-%s tanggal %s yang anda antre sudah tersedia dan otomatis diajukan sebagai peminjaman.",
+			promoted.JamPeminjaman, promoted.JamSelesai, helpers.FormatDateToYMD(promoted.TanggalPeminjaman))
+		if err := notifyUsers(u.templateMessageRepo, u.notificationRepo, []uint{entry.UserID}, "Antrean Peminjaman Dipromosikan", content); err != nil {
+			log.Printf("gagal mengirim notifikasi antrean %d: %v", entry.ID, err)
+		}
+		return
+	}
+}
+
+func (u *peminjamanUsecase) toWaitlistResponse(waitlist models.PeminjamanWaitlist) (dtos.PeminjamanWaitlistResponse, error) {
+	var waitlistResponse dtos.PeminjamanWaitlistResponse
+
+	getLab, err := u.labRepo.GetLabByID2(waitlist.LabID)
+	if err != nil {
+		return waitlistResponse, errors.New("failed to get lab")
+	}
+
+	position := 0
+	if waitlist.Status == models.WaitlistStatusWaiting {
+		ahead, err := u.peminjamanWaitlistRepo.CountWaitingAhead(waitlist)
+		if err != nil {
+			return waitlistResponse, err
+		}
+		position = ahead + 1
+	}
+
+	waitlistResponse = dtos.PeminjamanWaitlistResponse{
+		WaitlistID:        waitlist.ID,
+		TanggalPeminjaman: helpers.FormatDateToYMD(waitlist.TanggalPeminjaman),
+		JamPeminjaman:     waitlist.JamPeminjaman,
+		JamSelesai:        waitlist.JamSelesai,
+		LabSlotID:         waitlist.LabSlotID,
+		Description:       waitlist.Description,
+		Status:            waitlist.Status,
+		Position:          position,
+		PeminjamanID:      waitlist.PeminjamanID,
+		PromotedAt:        waitlist.PromotedAt,
+		Lab: dtos.LabByIDResponses{
+			LabID:       getLab.ID,
+			Name:        getLab.Name,
+			Description: getLab.Description,
+		},
+		CreatedAt: waitlist.CreatedAt,
+	}
+
+	return waitlistResponse, nil
+}
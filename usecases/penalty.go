@@ -0,0 +1,322 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sistem_peminjaman_be/dtos"
+	"sistem_peminjaman_be/helpers"
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"time"
+)
+
+type PenaltyUsecase interface {
+	GetPenalties(page, limit int, userID uint, status string) ([]dtos.PenaltyResponse, int, error)
+	GetPenaltySummary(userID uint) (dtos.PenaltySummaryResponse, error)
+	CreatePenalty(adminID uint, input dtos.PenaltyInput) (dtos.PenaltyResponse, error)
+	WaivePenalty(adminID, id uint, input dtos.PenaltyWaiveInput) (dtos.PenaltyResponse, error)
+	AppealPenalty(userID, id uint, input dtos.PenaltyAppealInput) (dtos.PenaltyResponse, error)
+	DecidePenaltyAppeal(adminID, id uint, input dtos.PenaltyAppealDecisionInput) (dtos.PenaltyResponse, error)
+}
+
+// PenaltyPolicy mengatur berapa poin yang diberikan untuk setiap pelanggaran, masa berlakunya,
+// dan batas poin yang membuat user ditangguhkan. Threshold 0 berarti penangguhan dimatikan.
+type PenaltyPolicy struct {
+	Threshold        int
+	Expiry           time.Duration
+	NoShowPoints     int
+	LateReturnPoints int
+	DamagePoints     int
+	MissingPoints    int
+}
+
+type penaltyUsecase struct {
+	penaltyRepo         repositories.PenaltyRepository
+	userRepo            repositories.UserRepository
+	templateMessageRepo repositories.TemplateMessageRepository
+	notificationRepo    repositories.NotificationRepository
+	penaltyPolicy       PenaltyPolicy
+}
+
+func NewPenaltyUsecase(penaltyRepo repositories.PenaltyRepository, userRepo repositories.UserRepository, templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, penaltyPolicy PenaltyPolicy) PenaltyUsecase {
+	return &penaltyUsecase{penaltyRepo, userRepo, templateMessageRepo, notificationRepo, penaltyPolicy}
+}
+
+// GetPenalties mengambil ledger penalti, userID 0 untuk semua user
+func (u *penaltyUsecase) GetPenalties(page, limit int, userID uint, status string) ([]dtos.PenaltyResponse, int, error) {
+	var penaltyResponses []dtos.PenaltyResponse
+
+	penalties, count, err := u.penaltyRepo.GetPenalties(page, limit, userID, status)
+	if err != nil {
+		return penaltyResponses, 0, err
+	}
+
+	now := time.Now()
+	for _, penalty := range penalties {
+		penaltyResponses = append(penaltyResponses, toPenaltyResponse(penalty, now))
+	}
+
+	return penaltyResponses, count, nil
+}
+
+// GetPenaltySummary mengembalikan total poin yang masih berlaku, status penangguhan, dan seluruh riwayat penalti user
+func (u *penaltyUsecase) GetPenaltySummary(userID uint) (dtos.PenaltySummaryResponse, error) {
+	summaryResponse := dtos.PenaltySummaryResponse{
+		UserID:    userID,
+		Threshold: u.penaltyPolicy.Threshold,
+		Penalties: []dtos.PenaltyResponse{},
+	}
+
+	now := time.Now()
+	totalPoints, err := u.penaltyRepo.SumActivePenaltyPoints(userID, now)
+	if err != nil {
+		return summaryResponse, err
+	}
+	summaryResponse.TotalPoints = totalPoints
+	summaryResponse.Suspended = u.penaltyPolicy.suspended(totalPoints)
+
+	penalties, err := u.penaltyRepo.GetPenaltiesByUserID(userID)
+	if err != nil {
+		return summaryResponse, err
+	}
+	for _, penalty := range penalties {
+		summaryResponse.Penalties = append(summaryResponse.Penalties, toPenaltyResponse(penalty, now))
+	}
+
+	return summaryResponse, nil
+}
+
+func (u *penaltyUsecase) CreatePenalty(adminID uint, input dtos.PenaltyInput) (dtos.PenaltyResponse, error) {
+	if _, err := u.userRepo.UserGetById(input.UserID); err != nil {
+		return dtos.PenaltyResponse{}, errors.New("user tidak ditemukan, pastikan user_id benar")
+	}
+	if input.Points <= 0 {
+		return dtos.PenaltyResponse{}, errors.New("poin penalti harus lebih dari 0")
+	}
+	if input.Reason == "" {
+		return dtos.PenaltyResponse{}, errors.New("alasan penalti wajib diisi")
+	}
+
+	penalty := models.Penalty{
+		UserID:     input.UserID,
+		Source:     models.PenaltySourceManual,
+		Points:     input.Points,
+		Reason:     input.Reason,
+		IssuedByID: &adminID,
+	}
+	if input.ExpiresAt != nil && *input.ExpiresAt != "" {
+		expiresAt, err := time.Parse("2006-01-02", *input.ExpiresAt)
+		if err != nil {
+			return dtos.PenaltyResponse{}, errors.New("tanggal expires_at invalid, gunakan format YYYY-MM-DD")
+		}
+		if !expiresAt.After(time.Now()) {
+			return dtos.PenaltyResponse{}, errors.New("tanggal expires_at harus setelah hari ini")
+		}
+		penalty.ExpiresAt = &expiresAt
+	}
+
+	createdPenalty, err := issuePenalty(u.penaltyRepo, u.templateMessageRepo, u.notificationRepo, u.penaltyPolicy, penalty)
+	if err != nil {
+		return dtos.PenaltyResponse{}, errors.New("failed to create penalty")
+	}
+
+	return toPenaltyResponse(createdPenalty, time.Now()), nil
+}
+
+// WaivePenalty menghapus poin penalti sehingga tidak lagi dihitung untuk penangguhan
+func (u *penaltyUsecase) WaivePenalty(adminID, id uint, input dtos.PenaltyWaiveInput) (dtos.PenaltyResponse, error) {
+	penalty, err := u.penaltyRepo.GetPenaltyByID(id)
+	if err != nil {
+		return dtos.PenaltyResponse{}, errors.New("penalti tidak ditemukan, pastikan ID benar")
+	}
+	if penalty.Status == models.PenaltyStatusWaived {
+		return dtos.PenaltyResponse{}, fmt.Errorf("%w: penalti sudah dihapus", helpers.ErrInvalidStatusTransition)
+	}
+
+	return u.resolvePenalty(penalty, adminID, models.PenaltyStatusWaived, input.Note)
+}
+
+// AppealPenalty mengajukan banding atas penalti milik user sendiri. Banding hanya bisa diajukan sekali.
+func (u *penaltyUsecase) AppealPenalty(userID, id uint, input dtos.PenaltyAppealInput) (dtos.PenaltyResponse, error) {
+	penalty, err := u.penaltyRepo.GetPenaltyByID(id)
+	if err != nil || penalty.UserID != userID {
+		return dtos.PenaltyResponse{}, errors.New("penalti tidak ditemukan, pastikan ID benar")
+	}
+	if penalty.Status != models.PenaltyStatusActive {
+		return dtos.PenaltyResponse{}, fmt.Errorf("%w: hanya penalti aktif yang bisa diajukan banding", helpers.ErrInvalidStatusTransition)
+	}
+	if penalty.AppealedAt != nil {
+		return dtos.PenaltyResponse{}, fmt.Errorf("%w: banding untuk penalti ini sudah pernah diajukan", helpers.ErrInvalidStatusTransition)
+	}
+	if input.Reason == "" {
+		return dtos.PenaltyResponse{}, errors.New("alasan banding wajib diisi")
+	}
+
+	now := time.Now()
+	penalty.Status = models.PenaltyStatusAppealed
+	penalty.AppealReason = input.Reason
+	penalty.AppealedAt = &now
+
+	updatedPenalty, err := u.penaltyRepo.UpdatePenalty(penalty)
+	if err != nil {
+		return dtos.PenaltyResponse{}, err
+	}
+
+	content := fmt.Sprintf("Ada banding penalti baru (%d poin): %s", updatedPenalty.Points, input.Reason)
+	if err := notifyAdmins(u.templateMessageRepo, u.notificationRepo, u.userRepo, "Banding Penalti", content); err != nil {
+		log.Printf("gagal mengirim notifikasi banding penalti %d: %v", updatedPenalty.ID, err)
+	}
+
+	return toPenaltyResponse(updatedPenalty, now), nil
+}
+
+// DecidePenaltyAppeal menerima banding (penalti dihapus) atau menolaknya (penalti kembali aktif)
+func (u *penaltyUsecase) DecidePenaltyAppeal(adminID, id uint, input dtos.PenaltyAppealDecisionInput) (dtos.PenaltyResponse, error) {
+	penalty, err := u.penaltyRepo.GetPenaltyByID(id)
+	if err != nil {
+		return dtos.PenaltyResponse{}, errors.New("penalti tidak ditemukan, pastikan ID benar")
+	}
+	if penalty.Status != models.PenaltyStatusAppealed {
+		return dtos.PenaltyResponse{}, fmt.Errorf("%w: penalti tidak sedang diajukan banding", helpers.ErrInvalidStatusTransition)
+	}
+
+	switch input.Decision {
+	case "approve":
+		return u.resolvePenalty(penalty, adminID, models.PenaltyStatusWaived, input.Note)
+	case "reject":
+		return u.resolvePenalty(penalty, adminID, models.PenaltyStatusActive, input.Note)
+	default:
+		return dtos.PenaltyResponse{}, errors.New("decision harus approve atau reject")
+	}
+}
+
+func (u *penaltyUsecase) resolvePenalty(penalty models.Penalty, adminID uint, status, note string) (dtos.PenaltyResponse, error) {
+	now := time.Now()
+	penalty.Status = status
+	penalty.ResolutionNote = note
+	penalty.ResolvedByID = &adminID
+	penalty.ResolvedAt = &now
+
+	updatedPenalty, err := u.penaltyRepo.UpdatePenalty(penalty)
+	if err != nil {
+		return dtos.PenaltyResponse{}, err
+	}
+
+	title := "Penalti Dihapus"
+	if updatedPenalty.Status == models.PenaltyStatusActive {
+		title = "Banding Penalti Ditolak"
+	}
+	content := fmt.Sprintf("Penalti %d poin (%s) berstatus %s. %s", updatedPenalty.Points, updatedPenalty.Reason, updatedPenalty.Status, note)
+	if err := notifyUsers(u.templateMessageRepo, u.notificationRepo, []uint{updatedPenalty.UserID}, title, content); err != nil {
+		log.Printf("gagal mengirim notifikasi penalti %d: %v", updatedPenalty.ID, err)
+	}
+
+	return toPenaltyResponse(updatedPenalty, now), nil
+}
+
+func (p PenaltyPolicy) suspended(totalPoints int) bool {
+	return p.Threshold > 0 && totalPoints >= p.Threshold
+}
+
+// issuePenalty mencatat poin penalti baru dengan masa berlaku default lalu memberi tahu user,
+// termasuk jika poin tersebut membuat user ditangguhkan dari peminjaman
+func issuePenalty(penaltyRepo repositories.PenaltyRepository, templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, policy PenaltyPolicy, penalty models.Penalty) (models.Penalty, error) {
+	now := time.Now()
+	if penalty.ExpiresAt == nil && policy.Expiry > 0 {
+		expiresAt := now.Add(policy.Expiry)
+		penalty.ExpiresAt = &expiresAt
+	}
+	penalty.Status = models.PenaltyStatusActive
+
+	createdPenalty, err := penaltyRepo.CreatePenalty(penalty)
+	if err != nil {
+		return createdPenalty, err
+	}
+
+	content := fmt.Sprintf("Anda mendapat %d poin penalti: %s", createdPenalty.Points, createdPenalty.Reason)
+	if totalPoints, err := penaltyRepo.SumActivePenaltyPoints(createdPenalty.UserID, now); err == nil &&
+		policy.suspended(totalPoints) && !policy.suspended(totalPoints-createdPenalty.Points) {
+		content += fmt.Sprintf(". Total poin anda %d sehingga pengajuan peminjaman ditangguhkan sampai poin kedaluwarsa atau dihapus admin.", totalPoints)
+	}
+	if err := notifyUsers(templateMessageRepo, notificationRepo, []uint{createdPenalty.UserID}, "Poin Penalti", content); err != nil {
+		log.Printf("gagal mengirim notifikasi penalti %d: %v", createdPenalty.ID, err)
+	}
+
+	return createdPenalty, nil
+}
+
+// issuePenaltyAsync dipakai saat penalti adalah efek samping, kegagalan cukup dicatat di log
+func issuePenaltyAsync(penaltyRepo repositories.PenaltyRepository, templateMessageRepo repositories.TemplateMessageRepository, notificationRepo repositories.NotificationRepository, policy PenaltyPolicy, penalty models.Penalty) {
+	if penalty.Points <= 0 {
+		return
+	}
+	if _, err := issuePenalty(penaltyRepo, templateMessageRepo, notificationRepo, policy, penalty); err != nil {
+		log.Printf("gagal mencatat penalti %s untuk user %d: %v", penalty.Source, penalty.UserID, err)
+	}
+}
+
+// lateReturnPenalty menghitung penalti keterlambatan pengembalian alat per hari, Points 0 jika tepat waktu
+func lateReturnPenalty(policy PenaltyPolicy, equipmentLoan models.EquipmentLoan, returnedAt time.Time) models.Penalty {
+	penalty := models.Penalty{
+		UserID:          equipmentLoan.UserID,
+		Source:          models.PenaltySourceLateReturn,
+		EquipmentLoanID: &equipmentLoan.ID,
+	}
+	if equipmentLoan.TanggalKembali == nil {
+		return penalty
+	}
+
+	dueDate, _ := time.Parse("2006-01-02", equipmentLoan.TanggalKembali.Format("2006-01-02"))
+	returnedDate, _ := time.Parse("2006-01-02", returnedAt.Format("2006-01-02"))
+	lateDays := int(returnedDate.Sub(dueDate).Hours() / 24)
+	if lateDays <= 0 {
+		return penalty
+	}
+
+	penalty.Points = lateDays * policy.LateReturnPoints
+	penalty.Reason = fmt.Sprintf("Terlambat mengembalikan alat %d hari dari tanggal %s", lateDays, dueDate.Format("2006-01-02"))
+	return penalty
+}
+
+// checkBorrowingStanding menolak pengajuan baru dari user yang masih punya kasus kerusakan terbuka
+// atau poin penaltinya sudah mencapai batas penangguhan
+func checkBorrowingStanding(damageCaseRepo repositories.DamageCaseRepository, penaltyRepo repositories.PenaltyRepository, policy PenaltyPolicy, userID uint) error {
+	openCases, err := damageCaseRepo.CountOpenDamageCasesByUserID(userID)
+	if err != nil {
+		return errors.New("failed to check borrowing standing")
+	}
+	if openCases > 0 {
+		return fmt.Errorf("%w: masih ada %d kasus kerusakan atau kehilangan yang belum diselesaikan", helpers.ErrBorrowingRestricted, openCases)
+	}
+
+	totalPoints, err := penaltyRepo.SumActivePenaltyPoints(userID, time.Now())
+	if err != nil {
+		return errors.New("failed to check borrowing standing")
+	}
+	if policy.suspended(totalPoints) {
+		return fmt.Errorf("%w: poin penalti anda %d sudah mencapai batas %d, peminjaman ditangguhkan sampai poin kedaluwarsa atau dihapus admin", helpers.ErrBorrowingRestricted, totalPoints, policy.Threshold)
+	}
+	return nil
+}
+
+func toPenaltyResponse(penalty models.Penalty, now time.Time) dtos.PenaltyResponse {
+	return dtos.PenaltyResponse{
+		PenaltyID:       penalty.ID,
+		UserID:          penalty.UserID,
+		Source:          penalty.Source,
+		Points:          penalty.Points,
+		Reason:          penalty.Reason,
+		PeminjamanID:    penalty.PeminjamanID,
+		EquipmentLoanID: penalty.EquipmentLoanID,
+		DamageCaseID:    penalty.DamageCaseID,
+		Status:          penalty.Status,
+		ExpiresAt:       penalty.ExpiresAt,
+		Expired:         penalty.ExpiresAt != nil && !penalty.ExpiresAt.After(now),
+		AppealReason:    penalty.AppealReason,
+		AppealedAt:      penalty.AppealedAt,
+		ResolutionNote:  penalty.ResolutionNote,
+		ResolvedAt:      penalty.ResolvedAt,
+		CreatedAt:       penalty.CreatedAt,
+	}
+}
@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"sistem_peminjaman_be/models"
+	"sistem_peminjaman_be/repositories"
+	"time"
+)
+
+// SchedulerUsecase berisi pekerjaan berkala yang digerakkan oleh waktu. Setiap fungsi menerima
+// waktu sekarang dari luar sehingga bisa dijalankan dengan clock buatan.
+type SchedulerUsecase interface {
+	ExpireStaleRequests(now time.Time) (int, error)
+	AdvancePeminjamanStatus(now time.Time) (int, error)
+	AdvanceJadwalStatus(now time.Time) (int, error)
+}
+
+type schedulerUsecase struct {
+	peminjamanRepo    repositories.PeminjamanRepository
+	jadwalRepo        repositories.JadwalRepository
+	peminjamanUsecase PeminjamanUsecase
+	checkinGrace      time.Duration
+}
+
+func NewSchedulerUsecase(peminjamanRepo repositories.PeminjamanRepository, jadwalRepo repositories.JadwalRepository, peminjamanUsecase PeminjamanUsecase, checkinGrace time.Duration) SchedulerUsecase {
+	return &schedulerUsecase{peminjamanRepo, jadwalRepo, peminjamanUsecase, checkinGrace}
+}
+
+// ExpireStaleRequests menandai peminjaman yang masih request padahal jam mulainya sudah lewat
+func (u *schedulerUsecase) ExpireStaleRequests(now time.Time) (int, error) {
+	peminjamans, err := u.peminjamanRepo.GetPeminjamansStartedBefore(models.PeminjamanStatusRequest, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return u.transitionAll(peminjamans, models.PeminjamanStatusExpired, "permintaan tidak diproses sampai jam peminjaman dimulai")
+}
+
+// AdvancePeminjamanStatus menandai peminjaman accept yang tidak check-in sampai batas toleransi
+// sebagai no_show, lalu peminjaman in_use yang sudah lewat jam selesai ditambah toleransi yang sama
+// menjadi finished, sehingga check-out dengan token yang masih berlaku tetap tercatat.
+// Peminjaman hanya menjadi in_use lewat check-in.
+func (u *schedulerUsecase) AdvancePeminjamanStatus(now time.Time) (int, error) {
+	started, err := u.peminjamanRepo.GetPeminjamansStartedBefore(models.PeminjamanStatusAccept, now.Add(-u.checkinGrace))
+	if err != nil {
+		return 0, err
+	}
+
+	startedCount, err := u.transitionAll(started, models.PeminjamanStatusNoShow, "tidak check-in sampai batas toleransi")
+	if err != nil {
+		return startedCount, err
+	}
+
+	ended, err := u.peminjamanRepo.GetPeminjamansEndedBefore(models.PeminjamanStatusInUse, now.Add(-u.checkinGrace))
+	if err != nil {
+		return startedCount, err
+	}
+
+	endedCount, err := u.transitionAll(ended, models.PeminjamanStatusFinished, "jam peminjaman selesai")
+	return startedCount + endedCount, err
+}
+
+// AdvanceJadwalStatus memindahkan status jadwal dari notused ke inused lalu finished sesuai waktunya.
+// Jadwal hasil peminjaman mengikuti status peminjamannya sehingga tidak diproses di sini.
+func (u *schedulerUsecase) AdvanceJadwalStatus(now time.Time) (int, error) {
+	started, err := u.jadwalRepo.StartDueJadwals(now)
+	if err != nil {
+		return int(started), err
+	}
+
+	finished, err := u.jadwalRepo.FinishDueJadwals(now)
+	return int(started + finished), err
+}
+
+// transitionAll tetap memproses peminjaman berikutnya jika satu peminjaman gagal, error pertama dikembalikan
+func (u *schedulerUsecase) transitionAll(peminjamans []models.Peminjaman, toStatus, reason string) (int, error) {
+	var firstErr error
+	count := 0
+
+	for _, peminjaman := range peminjamans {
+		_, err := u.peminjamanUsecase.TransitionPeminjamanStatus(peminjaman.ID, toStatus, nil, ActorRoleSystem, reason)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		count++
+	}
+
+	return count, firstErr
+}
@@ -736,7 +736,16 @@ func (u *userUsecase) UserAdminUpdate(id uint, input dtos.UserRegisterInputUpdat
 	user.Email = input.Email
 	user.NIMNIP = input.NIMNIP
 	user.ProfilePicture = "https://icon-library.com/images/default-user-icon/default-user-icon-13.jpg"
-	user.Role = "user"
+
+	// Admin bisa menjadikan user sebagai approver peminjaman (dosen atau kepala lab),
+	// role kosong berarti role user tidak diubah
+	switch input.Role {
+	case "":
+	case models.UserRoleUser, models.UserRoleDosen, models.UserRoleKepalaLab:
+		user.Role = input.Role
+	default:
+		return userResponse, errors.New("Invalid role")
+	}
 
 	isActive := false // Default value if the pointer is nil
 